@@ -8,6 +8,12 @@ const (
 	// HTTP requests
 	Requests ID = 1
 
+	// HTTP status code classes (10 - 50) e.g., 40 for 4xx.
+	Status2xx ID = 20
+	Status3xx ID = 30
+	Status4xx ID = 40
+	Status5xx ID = 50
+
 	// HTTP status codes (100 - 999).
 	StatusOK                  ID = 200
 	StatusBadRequest          ID = 400
@@ -24,7 +30,15 @@ const (
 	MemHeapObjects ID = 1003 // total number of allocated objects
 
 	// Other runtime stats
-	Routines ID = 1100 // number of Go routines in use.
+	Routines     ID = 1100 // number of Go routines in use.
+	GCCount      ID = 1101 // number of garbage collections in the interval.
+	GCPauseFifty ID = 1102 // 50th percentile GC pause (µs) in the interval.
+	GCPauseMax   ID = 1103 // max GC pause (µs) in the interval.
+	NextGC       ID = 1104 // target heap size (bytes) of the next GC.
+	StackInuse   ID = 1105 // bytes in stack spans.
+	OpenFDs      ID = 1106 // number of open file descriptors.
+	CPUTime      ID = 1107 // process user and system CPU time (ms) in the interval.
+	Threads      ID = 1108 // number of OS threads created.
 
 	// Messaging
 	MsgRx   ID = 1201
@@ -32,10 +46,15 @@ const (
 	MsgProc ID = 1203
 	MsgErr  ID = 1204
 
+	// mtrapp queue for metrics waiting to be sent.
+	SpoolDepth   ID = 1301 // metrics waiting to be sent.
+	SpoolDropped ID = 1302 // metrics dropped because the queue was full.
+
 	// Timer
-	AvgMean   ID = 2001
-	MaxFifty  ID = 2002
-	MaxNinety ID = 2003
+	AvgMean       ID = 2001
+	MaxFifty      ID = 2002
+	MaxNinety     ID = 2003
+	MaxNinetyNine ID = 2004
 
 	// Data latency
 	Mean   ID = 3001
@@ -45,6 +64,10 @@ const (
 
 var idColours = map[int]string{
 	1:   "#4daf4a",
+	20:  "deepskyblue",
+	30:  "#4daf4a",
+	40:  "#ff7f00",
+	50:  "#e41a1c",
 	200: "deepskyblue",
 	400: "#984ea3",
 	401: "#a65628",
@@ -58,15 +81,27 @@ var idColours = map[int]string{
 	1003: "deepskyblue",
 
 	1100: "deepskyblue",
+	1101: "#a6cee3",
+	1102: "#1f78b4",
+	1103: "#e41a1c",
+	1104: "#b2df8a",
+	1105: "#33a02c",
+	1106: "#ff7f00",
+	1107: "#984ea3",
+	1108: "#a65628",
 
 	1201: "#4daf4a",
 	1202: "#984ea3",
 	1203: "deepskyblue",
 	1204: "#e41a1c",
 
+	1301: "deepskyblue",
+	1302: "#e41a1c",
+
 	2001: "#ff0000",
 	2002: "#00ff00",
 	2003: "#0000ff",
+	2004: "#ff00ff",
 
 	3001: "deepskyblue",
 	3002: "deeppink",
@@ -75,6 +110,10 @@ var idColours = map[int]string{
 
 var idLabels = map[int]string{
 	1:   "Requests",
+	20:  "2xx",
+	30:  "3xx",
+	40:  "4xx",
+	50:  "5xx",
 	200: "200 OK",
 	400: "400 Bad Request",
 	401: "401 Unauthorized",
@@ -88,15 +127,27 @@ var idLabels = map[int]string{
 	1003: "Mem Heap Objects",
 
 	1100: "Go Routines",
+	1101: "GC Count",
+	1102: "GC Pause Fifty (µs)",
+	1103: "GC Pause Max (µs)",
+	1104: "Next GC",
+	1105: "Stack In Use",
+	1106: "Open Files",
+	1107: "CPU Time (ms)",
+	1108: "Threads",
 
 	1201: "Msg Rx",
 	1202: "Msg Tx",
 	1203: "Msg Processed",
 	1204: "Msg Error",
 
+	1301: "Spool Depth",
+	1302: "Spool Dropped",
+
 	2001: "Avg Mean",
 	2002: "Max Fifty",
 	2003: "Max Ninety",
+	2004: "Max Ninety Nine",
 
 	3001: "Mean",
 	3002: "Fifty",
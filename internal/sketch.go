@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+/*
+Sketch summarises the distribution of timer values (ms) in logarithmically sized buckets, in the
+style of HDR histograms and DDSketch.  The memory used depends on the range of the values not the
+number of them.  Quantiles are within 1% of a value that was added.  Sketches for many minutes or
+instances are merged by adding their buckets so the quantiles for the merged values are correct.
+*/
+type Sketch struct {
+	Count, Sum int64
+	Min, Max   int64
+	zero       int64 // values <= 0
+	buckets    map[int]int64
+}
+
+// sketchGamma is the ratio between the upper and lower bound of a bucket.
+const sketchGamma = 1.02
+
+var logGamma = math.Log(sketchGamma)
+
+// NewSketch returns an empty Sketch.
+func NewSketch() *Sketch {
+	return &Sketch{buckets: make(map[int]int64)}
+}
+
+// Add adds the value v.
+func (s *Sketch) Add(v int64) {
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+
+	s.Count++
+	s.Sum += v
+
+	if v <= 0 {
+		s.zero++
+		return
+	}
+
+	s.buckets[int(math.Ceil(math.Log(float64(v))/logGamma))]++
+}
+
+// Merge adds the values in o to s.
+func (s *Sketch) Merge(o *Sketch) {
+	if o.Count == 0 {
+		return
+	}
+
+	if s.Count == 0 || o.Min < s.Min {
+		s.Min = o.Min
+	}
+	if s.Count == 0 || o.Max > s.Max {
+		s.Max = o.Max
+	}
+
+	s.Count += o.Count
+	s.Sum += o.Sum
+	s.zero += o.zero
+
+	for k, v := range o.buckets {
+		s.buckets[k] += v
+	}
+}
+
+// Mean returns the mean of the values or 0 if there are none.
+func (s *Sketch) Mean() int64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return s.Sum / s.Count
+}
+
+// Quantile returns the value at quantile q e.g., 0.99.  Returns 0 if there are no values.
+func (s *Sketch) Quantile(q float64) int64 {
+	switch {
+	case s.Count == 0:
+		return 0
+	case q <= 0:
+		return s.Min
+	case q >= 1:
+		return s.Max
+	}
+
+	rank := int64(q * float64(s.Count-1))
+
+	if rank < s.zero {
+		return s.Min
+	}
+
+	n := s.zero
+
+	for _, k := range s.keys() {
+		n += s.buckets[k]
+		if n > rank {
+			// the middle of the bucket for the smallest relative error.
+			v := int64(math.Floor(2*math.Pow(sketchGamma, float64(k))/(sketchGamma+1) + 0.5))
+
+			switch {
+			case v < s.Min:
+				return s.Min
+			case v > s.Max:
+				return s.Max
+			}
+
+			return v
+		}
+	}
+
+	return s.Max
+}
+
+// keys returns the bucket keys in order.
+func (s *Sketch) keys() []int {
+	var k []int
+	for i := range s.buckets {
+		k = append(k, i)
+	}
+
+	sort.Ints(k)
+
+	return k
+}
+
+// Marshal returns s encoded for storage or sending to mtr-api.  See UnmarshalSketch.
+func (s *Sketch) Marshal() []byte {
+	b := make([]byte, 0, 4*binary.MaxVarintLen64+len(s.buckets)*4)
+	var buf [binary.MaxVarintLen64]byte
+
+	put := func(v int64) {
+		b = append(b, buf[:binary.PutVarint(buf[:], v)]...)
+	}
+
+	put(s.Min)
+	put(s.Max)
+	put(s.Sum)
+	put(s.zero)
+
+	// bucket keys are encoded as the difference from the previous key.
+	var last int
+
+	for _, k := range s.keys() {
+		put(int64(k - last))
+		put(s.buckets[k])
+		last = k
+	}
+
+	return b
+}
+
+// UnmarshalSketch decodes a Sketch encoded with Marshal.
+func UnmarshalSketch(b []byte) (*Sketch, error) {
+	s := NewSketch()
+
+	get := func() (int64, error) {
+		v, n := binary.Varint(b)
+		if n <= 0 {
+			return 0, errors.New("invalid sketch")
+		}
+		b = b[n:]
+		return v, nil
+	}
+
+	var err error
+
+	for _, v := range []*int64{&s.Min, &s.Max, &s.Sum, &s.zero} {
+		if *v, err = get(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.zero < 0 {
+		return nil, errors.New("invalid sketch count")
+	}
+
+	s.Count = s.zero
+
+	var k int
+
+	for len(b) > 0 {
+		var d, c int64
+
+		if d, err = get(); err != nil {
+			return nil, err
+		}
+		if c, err = get(); err != nil {
+			return nil, err
+		}
+		if c < 0 {
+			return nil, errors.New("invalid sketch count")
+		}
+
+		k += int(d)
+		s.buckets[k] += c
+		s.Count += c
+	}
+
+	return s, nil
+}
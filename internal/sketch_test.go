@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSketchQuantile(t *testing.T) {
+	s := NewSketch()
+
+	for i := int64(1); i <= 10000; i++ {
+		s.Add(i)
+	}
+
+	if s.Count != 10000 || s.Min != 1 || s.Max != 10000 || s.Mean() != 5000 {
+		t.Errorf("expected count 10000 min 1 max 10000 mean 5000 got %d %d %d %d", s.Count, s.Min, s.Max, s.Mean())
+	}
+
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		expected := q * 10000
+		if v := float64(s.Quantile(q)); math.Abs(v-expected)/expected > 0.02 {
+			t.Errorf("quantile %f expected %f got %f", q, expected, v)
+		}
+	}
+
+	if s.Quantile(0) != 1 || s.Quantile(1) != 10000 {
+		t.Errorf("expected min and max for quantile 0 and 1 got %d %d", s.Quantile(0), s.Quantile(1))
+	}
+
+	if v := NewSketch().Quantile(0.5); v != 0 {
+		t.Errorf("expected 0 for an empty sketch got %d", v)
+	}
+}
+
+// The quantiles for merged sketches are for all the values not the max of the quantiles.
+func TestSketchMerge(t *testing.T) {
+	a := NewSketch()
+	b := NewSketch()
+
+	// a fast instance and a slow instance.
+	for i := 0; i < 990; i++ {
+		a.Add(10)
+	}
+
+	for i := 0; i < 10; i++ {
+		b.Add(1000)
+	}
+
+	if b.Quantile(0.9) != 1000 {
+		t.Errorf("expected 1000 got %d", b.Quantile(0.9))
+	}
+
+	a.Merge(b)
+
+	if a.Count != 1000 || a.Min != 10 || a.Max != 1000 {
+		t.Errorf("expected count 1000 min 10 max 1000 got %d %d %d", a.Count, a.Min, a.Max)
+	}
+
+	if v := a.Quantile(0.9); v != 10 {
+		t.Errorf("expected 90th percentile 10 got %d", v)
+	}
+
+	if v := a.Quantile(0.995); v < 990 || v > 1000 {
+		t.Errorf("expected 99.5th percentile within 1%% of 1000 got %d", v)
+	}
+}
+
+func TestSketchMarshal(t *testing.T) {
+	s := NewSketch()
+
+	for _, v := range []int64{0, 3, 3, 17, 250, 250, 251, 40000} {
+		s.Add(v)
+	}
+
+	u, err := UnmarshalSketch(s.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Count != s.Count || u.Sum != s.Sum || u.Min != s.Min || u.Max != s.Max {
+		t.Errorf("expected %d %d %d %d got %d %d %d %d", s.Count, s.Sum, s.Min, s.Max, u.Count, u.Sum, u.Min, u.Max)
+	}
+
+	for _, q := range []float64{0.1, 0.5, 0.9, 0.99} {
+		if u.Quantile(q) != s.Quantile(q) {
+			t.Errorf("quantile %f expected %d got %d", q, s.Quantile(q), u.Quantile(q))
+		}
+	}
+
+	for _, b := range [][]byte{{}, {0x80}, {0, 0, 0, 1}, {0, 0, 0, 0, 2}} {
+		if _, err = UnmarshalSketch(b); err == nil {
+			t.Errorf("expected error for %v", b)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"log"
+	"net/http"
+	"time"
+)
+
+// the source for alerts.  Matches the metric table.
+const (
+	alertField        = "field.metric"
+	alertLatency      = "data.latency"
+	alertCompleteness = "data.completeness"
+)
+
+// alertKey identifies the metric for an alert.
+type alertKey struct {
+	source, id, typeID string
+}
+
+/*
+badMetrics selects the metric summaries that are outside their critical thresholds with the
+same rules as the bad status strings in mtr-ui:
+
+	field.metric_summary - value outside lower and upper.  Metrics with lower and upper 0 have no threshold.
+	data.latency_summary - mean, or a non zero fifty or ninety, outside lower and upper.
+	data.completeness_summary - less than the expected count for five minutes.
+
+Retired devices and sites are not checked.
+
+The last column is false for field metrics and latencies that are inside lower and upper by less
+than the hysteresis for the threshold.  They keep an open alert open but don't open one.
+*/
+const badMetrics = `SELECT 'field.metric', deviceID, typeID, value::DOUBLE PRECISION,
+		(value < lower OR value > upper)
+		FROM field.metric_summary
+		JOIN field.device USING (devicePK)
+		JOIN field.type USING (typePK)
+		JOIN ` + fieldThreshold + ` AS threshold USING (devicePK, typePK)
+		WHERE NOT (lower = 0 AND upper = 0)
+		AND status != 'retired'
+		AND (value < lower + hysteresis OR value > upper - hysteresis)
+		UNION ALL
+		SELECT 'data.latency', siteID, typeID, mean::DOUBLE PRECISION,
+		(mean < lower OR mean > upper
+			OR (fifty != 0 AND (fifty < lower OR fifty > upper))
+			OR (ninety != 0 AND (ninety < lower OR ninety > upper)))
+		FROM data.latency_summary
+		JOIN data.site USING (sitePK)
+		JOIN data.type USING (typePK)
+		JOIN ` + dataLatencyThreshold + ` AS threshold USING (sitePK, typePK)
+		WHERE NOT (lower = 0 AND upper = 0)
+		AND status != 'retired'
+		AND (mean < lower + hysteresis OR mean > upper - hysteresis
+			OR (fifty != 0 AND (fifty < lower + hysteresis OR fifty > upper - hysteresis))
+			OR (ninety != 0 AND (ninety < lower + hysteresis OR ninety > upper - hysteresis)))
+		UNION ALL
+		SELECT 'data.completeness', siteID, typeID, count / (expected / 288.0), true
+		FROM data.completeness_summary
+		JOIN data.site USING (sitePK)
+		JOIN data.completeness_type USING (typePK)
+		WHERE count / (expected / 288.0) < 1.0
+		AND status != 'retired'`
+
+/*
+evaluateAlerts checks metric summaries against their thresholds every minute.
+*/
+func evaluateAlerts() {
+	ticker := time.NewTicker(time.Minute).C
+	for {
+		select {
+		case <-ticker:
+			if err := checkAlerts(time.Now().UTC()); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+/*
+checkAlerts opens an alert at now for each metric that is outside its threshold
+and closes the open alerts for metrics that are back inside their threshold, by at least the
+hysteresis, (or no longer exist).
+There may be more than one instance of mtr-api checking alerts so an alert that
+has already been opened or closed by another instance is ignored.  Notifications
+are only sent by the instance that opens or closes the alert.
+*/
+func checkAlerts(now time.Time) error {
+	var err error
+	var rows *sql.Rows
+
+	if rows, err = db.Query(badMetrics); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bad := make(map[alertKey]float64)
+	held := make(map[alertKey]bool) // inside the threshold by less than the hysteresis.
+
+	for rows.Next() {
+		var k alertKey
+		var v float64
+		var critical bool
+
+		if err = rows.Scan(&k.source, &k.id, &k.typeID, &v, &critical); err != nil {
+			return err
+		}
+
+		if !critical {
+			held[k] = true
+			continue
+		}
+
+		bad[k] = v
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if rows, err = db.Query(`SELECT source, ID, typeID FROM mtr.alert WHERE closed IS NULL`); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	open := make(map[alertKey]bool)
+
+	for rows.Next() {
+		var k alertKey
+
+		if err = rows.Scan(&k.source, &k.id, &k.typeID); err != nil {
+			return err
+		}
+
+		open[k] = true
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for k, v := range bad {
+		if open[k] {
+			continue
+		}
+
+		if _, err = db.Exec(`INSERT INTO mtr.alert(source, ID, typeID, opened, value) VALUES($1, $2, $3, $4, $5)`,
+			k.source, k.id, k.typeID, now, v); err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+				continue
+			}
+			return err
+		}
+
+		if err = notifyAlert(k, &mtrpb.Alert{Source: k.source, ID: k.id, TypeID: k.typeID, Opened: now.Unix(), Value: v}); err != nil {
+			log.Println(err)
+		}
+	}
+
+	for k := range open {
+		if _, ok := bad[k]; ok || held[k] {
+			continue
+		}
+
+		a := mtrpb.Alert{Source: k.source, ID: k.id, TypeID: k.typeID, Closed: now.Unix()}
+		var opened time.Time
+
+		// no rows if the alert has already been closed by another instance.
+		err = db.QueryRow(`UPDATE mtr.alert SET closed = $4
+				WHERE source = $1
+				AND ID = $2
+				AND typeID = $3
+				AND closed IS NULL
+				RETURNING opened, value`, k.source, k.id, k.typeID, now).Scan(&opened, &a.Value)
+		switch err {
+		case nil:
+			a.Opened = opened.Unix()
+			if err = notifyAlert(k, &a); err != nil {
+				log.Println(err)
+			}
+		case sql.ErrNoRows:
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+alerts returns the alerts that were open at any time between startDate and endDate (optional, default
+the last 12 hours).  Alerts that are still open have Closed 0.
+*/
+func alerts(r *http.Request) (mtrpb.AlertResult, *weft.Result) {
+	var ar mtrpb.AlertResult
+
+	timeRange, err := parseTimeRange(r.URL.Query())
+	if err != nil {
+		return ar, weft.BadRequest("invalid startDate or endDate")
+	}
+
+	var rows *sql.Rows
+
+	if rows, err = dbR.Query(`SELECT source, ID, typeID, opened, closed, value
+		FROM mtr.alert
+		WHERE opened <= $2
+		AND (closed IS NULL OR closed >= $1)
+		ORDER BY opened ASC`, timeRange[0], timeRange[1]); err != nil {
+		return ar, weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a mtrpb.Alert
+		var opened time.Time
+		var closed pq.NullTime
+
+		if err = rows.Scan(&a.Source, &a.ID, &a.TypeID, &opened, &closed, &a.Value); err != nil {
+			return ar, weft.InternalServerError(err)
+		}
+
+		a.Opened = opened.Unix()
+		if closed.Valid {
+			a.Closed = closed.Time.Unix()
+		}
+
+		ar.Result = append(ar.Result, &a)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return ar, weft.InternalServerError(err)
+	}
+
+	return ar, &weft.StatusOK
+}
+
+func alertProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	ar, res := alerts(r)
+	if !res.Ok {
+		return res
+	}
+
+	by, err := proto.Marshal(&ar)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func alertJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	ar, res := alerts(r)
+	if !res.Ok {
+		return res
+	}
+
+	by, err := json.Marshal(&ar)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+/*
+recentAlerts returns the alerts for id that are open or were closed in the last week, most recent
+first.  schema is the schema of the alert source; field for devices or data for sites.
+*/
+func recentAlerts(schema, id string) ([]*mtrpb.Alert, error) {
+	rows, err := dbR.Query(`SELECT source, ID, typeID, opened, closed, value
+		FROM mtr.alert
+		WHERE ID = $1
+		AND source LIKE $2
+		AND (closed IS NULL OR closed >= now() - interval '7 days')
+		ORDER BY opened DESC`, id, schema+".%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*mtrpb.Alert
+
+	for rows.Next() {
+		var a mtrpb.Alert
+		var opened time.Time
+		var closed pq.NullTime
+
+		if err = rows.Scan(&a.Source, &a.ID, &a.TypeID, &opened, &closed, &a.Value); err != nil {
+			return nil, err
+		}
+
+		a.Opened = opened.Unix()
+		if closed.Valid {
+			a.Closed = closed.Time.Unix()
+		}
+
+		alerts = append(alerts, &a)
+	}
+
+	return alerts, rows.Err()
+}
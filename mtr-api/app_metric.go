@@ -94,6 +94,14 @@ func appMetricCsv(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 		if res := a.loadAppMetrics(applicationID, resolution, internal.Routines, timeRange, &p); !res.Ok {
 			return res
 		}
+	case "gauges":
+		if res := a.loadInstanceMetrics(applicationID, resolution, typeBetween(dynamicTypePK, maxTypePK), timeRange, &p); !res.Ok {
+			return res
+		}
+	case "runtime", "gc_pauses", "runtime_memory", "cpu":
+		if res := a.loadInstanceMetrics(applicationID, resolution, runtimeGroups[v.Get("group")].typePKs, timeRange, &p); !res.Ok {
+			return res
+		}
 	default:
 		return weft.BadRequest("invalid value for group")
 	}
@@ -192,6 +200,12 @@ func appMetricSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 	case "hour":
 		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*28), time.Now().UTC())
 		p.SetXLabel("4 weeks")
+	case "day":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365), time.Now().UTC())
+		p.SetXLabel("1 year")
+	case "week":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365*5), time.Now().UTC())
+		p.SetXLabel("5 years")
 	default:
 		return weft.BadRequest("invalid value for resolution")
 	}
@@ -273,6 +287,21 @@ func appMetricSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 		p.SetTitle(fmt.Sprintf("Application: %s, Metric: Routines (n) - Average per %s",
 			applicationID, resTitle))
 		err = ts.LineAppMetrics.Draw(p, b)
+	case "gauges":
+		if res := a.loadInstanceMetrics(applicationID, resolution, typeBetween(dynamicTypePK, maxTypePK), timeRange, &p); !res.Ok {
+			return res
+		}
+		p.SetTitle(fmt.Sprintf("Application: %s, Metric: Gauges - Average per %s",
+			applicationID, resTitle))
+		err = ts.LineAppMetrics.Draw(p, b)
+	case "runtime", "gc_pauses", "runtime_memory", "cpu":
+		g := runtimeGroups[v.Get("group")]
+		if res := a.loadInstanceMetrics(applicationID, resolution, g.typePKs, timeRange, &p); !res.Ok {
+			return res
+		}
+		p.SetTitle(fmt.Sprintf("Application: %s, Metric: %s - Average per %s",
+			applicationID, g.title, resTitle))
+		err = ts.LineAppMetrics.Draw(p, b)
 	default:
 		return weft.BadRequest("invalid value for type")
 	}
@@ -305,13 +334,35 @@ func (a appMetric) loadCounters(applicationID, resolution string, timeRange []ti
 		AND time >= $2 AND time <= $3
 		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min', typePK
 		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
-	case "hour":
-		rows, err = dbR.Query(`SELECT typePK, date_trunc('`+resolution+`',time) as t, sum(count)
+	case "hour", "day":
+		// read the rollup and counters that haven't been rolled up yet.
+		rows, err = dbR.Query(`SELECT typePK, t, sum(c)::BIGINT FROM (
+		SELECT typePK, time as t, avg * count as c
+		FROM app.counter_`+resolution+`
+		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
+		AND time >= $2 AND time <= $3
+		UNION ALL
+		SELECT typePK, date_trunc('`+resolution+`',time), count
 		FROM app.counter
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
 		AND time >= $2 AND time <= $3
-		GROUP BY date_trunc('`+resolution+`',time), typePK
+		AND time >= `+rolledUp("app.counter", resolution)+`) r
+		GROUP BY t, typePK
 		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
+	case "week":
+		rows, err = dbR.Query(`SELECT typePK, date_trunc('week', t) as w, sum(c)::BIGINT FROM (
+		SELECT typePK, time as t, avg * count as c
+		FROM app.counter_day
+		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
+		AND time >= $2 AND time <= $3
+		UNION ALL
+		SELECT typePK, time, count
+		FROM app.counter
+		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
+		AND time >= $2 AND time <= $3
+		AND time >= `+rolledUp("app.counter", "day")+`) r
+		GROUP BY date_trunc('week', t), typePK
+		ORDER BY w ASC`, applicationID, timeRange[0], timeRange[1])
 	case "full":
 		rows, err = dbR.Query(`SELECT typePK, time, count
 		FROM app.counter
@@ -349,11 +400,16 @@ func (a appMetric) loadCounters(applicationID, resolution string, timeRange []ti
 
 	sort.Ints(keys)
 
+	types, err := appTypes()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
 	var labels ts.Labels
 
 	for _, k := range keys {
-		p.AddSeries(ts.Series{Colour: internal.Colour(k), Points: pts[k]})
-		labels = append(labels, ts.Label{Colour: internal.Colour(k), Label: fmt.Sprintf("%s (n=%d)", internal.Label(k), total[k])})
+		p.AddSeries(ts.Series{Colour: typeColour(k), Points: pts[k]})
+		labels = append(labels, ts.Label{Colour: typeColour(k), Label: fmt.Sprintf("%s (n=%d)", typeLabel(k, types), total[k])})
 	}
 
 	p.SetLabels(labels)
@@ -367,60 +423,56 @@ func (a appMetric) loadTimers(applicationID, resolution string, timeRange []time
 
 	var rows *sql.Rows
 
-	switch resolution {
-	case "minute":
-		rows, err = dbR.Query(`SELECT sourcePK, date_trunc('`+resolution+`',time) as t, max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND time >= $2 AND time <= $3
-		GROUP BY date_trunc('`+resolution+`',time), sourcePK
-		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
-	case "five_minutes":
-		rows, err = dbR.Query(`SELECT sourcePK,
-		date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min' as t,
-		max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND time >= $2 AND time <= $3
-		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min', sourcePK
-		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
-	case "hour":
-		rows, err = dbR.Query(`SELECT sourcePK, date_trunc('`+resolution+`',time) as t, max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND time >= $2 AND time <= $3
-		GROUP BY date_trunc('`+resolution+`',time), sourcePK
-		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
-	case "full":
-		rows, err = dbR.Query(`SELECT sourcePK, time, ninety, count
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND time >= $2 AND time <= $3
-		ORDER BY time ASC`, applicationID, timeRange[0], timeRange[1])
-	default:
-		return weft.InternalServerError(fmt.Errorf("invalid resolution: %s", resolution))
-	}
-	if err != nil {
+	if rows, err = timerRows(applicationID, "", resolution, timeRange); err != nil {
 		return weft.InternalServerError(err)
 	}
 
 	defer rows.Close()
 
+	// merge the timers for each source and time across instances.
+	type key struct {
+		sourcePK int
+		t        int64
+	}
+
 	var t time.Time
-	var sourcePK, max, n int
+	var sourcePK, count, fifty, ninety, ninetyNine int
+	var average float64
+	var sketch []byte
 	var sourceID string
-	pts := make(map[int][]ts.Point)
-	total := make(map[int]int) // track the total counts (call) for each timer.
+	var keys []key
+	times := make(map[key]time.Time)
+	merged := make(map[key]*timerMerge)
 
 	for rows.Next() {
-		if err = rows.Scan(&sourcePK, &t, &max, &n); err != nil {
+		if err = rows.Scan(&sourcePK, &t, &average, &count, &fifty, &ninety, &ninetyNine, &sketch); err != nil {
 			return weft.InternalServerError(err)
 		}
-		pts[sourcePK] = append(pts[sourcePK], ts.Point{DateTime: t, Value: float64(max)})
-		total[sourcePK] += n
+
+		k := key{sourcePK: sourcePK, t: t.Unix()}
+
+		m, ok := merged[k]
+		if !ok {
+			m = newTimerMerge()
+			merged[k] = m
+			times[k] = t
+			keys = append(keys, k)
+		}
+
+		m.add(average, count, fifty, ninety, ninetyNine, sketch)
 	}
 	rows.Close()
 
+	pts := make(map[int][]ts.Point)
+	total := make(map[int]int) // track the total counts (call) for each timer.
+
+	// keys are in time order.
+	for _, k := range keys {
+		v, _ := merged[k].quantile(0.9)
+		pts[k.sourcePK] = append(pts[k.sourcePK], ts.Point{DateTime: times[k], Value: float64(v)})
+		total[k.sourcePK] += merged[k].count
+	}
+
 	sourceIDs := make(map[int]string)
 
 	if rows, err = dbR.Query(`SELECT sourcePK, sourceID FROM app.source`); err != nil {
@@ -438,11 +490,11 @@ func (a appMetric) loadTimers(applicationID, resolution string, timeRange []time
 	rows.Close()
 
 	// sort the sourcePKs based on number of calls.
-	keys := rank(total)
+	ranked := rank(total)
 
 	var labels ts.Labels
 
-	for _, k := range keys {
+	for _, k := range ranked {
 		p.AddSeries(ts.Series{Points: pts[k.Key], Colour: "#e34a33"})
 		labels = append(labels, ts.Label{Label: fmt.Sprintf("%s (n=%d)", strings.TrimPrefix(sourceIDs[k.Key], `main.`), total[k.Key]), Colour: "lightgrey"})
 	}
@@ -458,65 +510,55 @@ func (a appMetric) loadTimersWithSourceID(applicationID, sourceID, resolution st
 
 	var rows *sql.Rows
 
-	switch resolution {
-	case "minute":
-		rows, err = dbR.Query(`SELECT date_trunc('minute',time) as t, avg(average), max(fifty), max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND sourcePK = (SELECT sourcePK from app.source WHERE sourceID = $2)
-		AND time >= $3 AND time <= $4
-		GROUP BY date_trunc('minute',time)
-		ORDER BY t ASC`, applicationID, sourceID, timeRange[0], timeRange[1])
-	case "five_minutes":
-		rows, err = dbR.Query(`SELECT
-		date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min' as t,
-		avg(average), max(fifty), max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND sourcePK = (SELECT sourcePK from app.source WHERE sourceID = $2)
-		AND time >= $3 AND time <= $4
-		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min'
-		ORDER BY t ASC`, applicationID, sourceID, timeRange[0], timeRange[1])
-	case "hour":
-		rows, err = dbR.Query(`SELECT date_trunc('hour',time) as t, avg(average), max(fifty), max(ninety), sum(count)
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND sourcePK = (SELECT sourcePK from app.source WHERE sourceID = $2)
-		AND time >= $3 AND time <= $4
-		GROUP BY date_trunc('hour', time)
-		ORDER BY t ASC`, applicationID, sourceID, timeRange[0], timeRange[1])
-	case "full":
-		rows, err = dbR.Query(`SELECT time, average, fifty, ninety, count
-		FROM app.timer
-		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND sourcePK = (SELECT sourcePK from app.source WHERE sourceID = $2)
-		AND time >= $3 AND time <= $4
-		ORDER BY time ASC`, applicationID, sourceID, timeRange[0], timeRange[1])
-	default:
-		return weft.InternalServerError(fmt.Errorf("invalid resolution: %s", resolution))
-	}
-	if err != nil {
+	if rows, err = timerRows(applicationID, sourceID, resolution, timeRange); err != nil {
 		return weft.InternalServerError(err)
 	}
 
 	defer rows.Close()
 
+	// merge the timers for each time across instances.
 	var t time.Time
-	var avg_mean float64
-	var max_fifty, max_ninety, n int
-	pts := make(map[internal.ID][]ts.Point)
+	var sourcePK, count, fifty, ninety, ninetyNine int
+	var average float64
+	var sketch []byte
+	var keys []int64
+	times := make(map[int64]time.Time)
+	merged := make(map[int64]*timerMerge)
 
 	for rows.Next() {
-		if err = rows.Scan(&t, &avg_mean, &max_fifty, &max_ninety, &n); err != nil {
+		if err = rows.Scan(&sourcePK, &t, &average, &count, &fifty, &ninety, &ninetyNine, &sketch); err != nil {
 			return weft.InternalServerError(err)
 		}
 
-		pts[internal.AvgMean] = append(pts[internal.AvgMean], ts.Point{DateTime: t, Value: avg_mean})
-		pts[internal.MaxFifty] = append(pts[internal.MaxFifty], ts.Point{DateTime: t, Value: float64(max_fifty)})
-		pts[internal.MaxNinety] = append(pts[internal.MaxNinety], ts.Point{DateTime: t, Value: float64(max_ninety)})
+		m, ok := merged[t.Unix()]
+		if !ok {
+			m = newTimerMerge()
+			merged[t.Unix()] = m
+			times[t.Unix()] = t
+			keys = append(keys, t.Unix())
+		}
+
+		m.add(average, count, fifty, ninety, ninetyNine, sketch)
 	}
 	rows.Close()
 
+	pts := make(map[internal.ID][]ts.Point)
+
+	for _, k := range keys {
+		m := merged[k]
+
+		pts[internal.AvgMean] = append(pts[internal.AvgMean], ts.Point{DateTime: times[k], Value: m.average()})
+
+		for _, q := range []struct {
+			id internal.ID
+			q  float64
+		}{{internal.MaxFifty, 0.5}, {internal.MaxNinety, 0.9}, {internal.MaxNinetyNine, 0.99}} {
+			if v, ok := m.quantile(q.q); ok {
+				pts[q.id] = append(pts[q.id], ts.Point{DateTime: times[k], Value: float64(v)})
+			}
+		}
+	}
+
 	var labels ts.Labels
 
 	for k, v := range pts {
@@ -554,7 +596,7 @@ func (a appMetric) loadMemory(applicationID, resolution string, timeRange []time
 		AND time >= $2 AND time <= $3
 		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min', typePK, instancePK
 		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
-	case "hour":
+	case "hour", "day", "week": // app.metric is not rolled up.
 		rows, err = dbR.Query(`SELECT instancePK, typePK, date_trunc('`+resolution+`',time) as t, avg(value)
 		FROM app.metric
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
@@ -648,39 +690,80 @@ func (a appMetric) loadAppMetrics(applicationID, resolution string, typeID inter
 
 	rows.Close()
 
+	return a.loadInstanceMetrics(applicationID, resolution, typeIn(typeID), timeRange, p)
+}
+
+// runtimeGroup is the runtime metrics plotted together.  They all have the same unit.
+type runtimeGroup struct {
+	title   string
+	typePKs string
+}
+
+var runtimeGroups = map[string]runtimeGroup{
+	"runtime":        {title: "Runtime (n)", typePKs: typeIn(internal.GCCount, internal.OpenFDs, internal.Threads)},
+	"gc_pauses":      {title: "GC Pauses (µs)", typePKs: typeIn(internal.GCPauseFifty, internal.GCPauseMax)},
+	"runtime_memory": {title: "Runtime Memory (bytes)", typePKs: typeIn(internal.NextGC, internal.StackInuse)},
+	"cpu":            {title: "CPU Time (ms)", typePKs: typeIn(internal.CPUTime)},
+}
+
+// typeBetween returns a typePK condition for lo to hi inclusive.
+func typeBetween(lo, hi int) string {
+	return fmt.Sprintf("BETWEEN %d AND %d", lo, hi)
+}
+
+// typeIn returns a typePK condition for the ids.
+func typeIn(ids ...internal.ID) string {
+	var s []string
+	for _, id := range ids {
+		s = append(s, strconv.Itoa(int(id)))
+	}
+
+	return "IN (" + strings.Join(s, ", ") + ")"
+}
+
+/*
+loadInstanceMetrics loads app.metric values for each instance and type where the typePK matches the
+condition in typePKs e.g., typeIn(typeID) for a single type or typeBetween(dynamicTypePK, maxTypePK) for
+the types added with PUT /application/type.
+*/
+func (a appMetric) loadInstanceMetrics(applicationID, resolution, typePKs string, timeRange []time.Time, p *ts.Plot) *weft.Result {
+	var err error
+
+	var rows *sql.Rows
+
 	switch resolution {
 	case "minute":
 		rows, err = dbR.Query(`SELECT instancePK, typePK, date_trunc('`+resolution+`',time) as t, avg(value)
 		FROM app.metric
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND typePK = $2
-		AND time >= $3 AND time <= $4
+		AND typePK `+typePKs+`
+		AND time >= $2 AND time <= $3
 		GROUP BY date_trunc('`+resolution+`',time), typePK, instancePK
-		ORDER BY t ASC`, applicationID, int(typeID), timeRange[0], timeRange[1])
+		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
 	case "five_minutes":
 		rows, err = dbR.Query(`SELECT instancePK, typePK,
 		date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min' as t, avg(value)
 		FROM app.metric
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND typePK = $2
-		AND time >= $3 AND time <= $4
+		AND typePK `+typePKs+`
+		AND time >= $2 AND time <= $3
 		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min', typePK, instancePK
-		ORDER BY t ASC`, applicationID, int(typeID), timeRange[0], timeRange[1])
-	case "hour":
+		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
+	case "hour", "day", "week": // app.metric is not rolled up.
 		rows, err = dbR.Query(`SELECT instancePK, typePK, date_trunc('`+resolution+`',time) as t, avg(value)
 		FROM app.metric
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND typePK = $2
-		AND time >= $3 AND time <= $4
+		AND typePK `+typePKs+`
+		AND time >= $2 AND time <= $3
 		GROUP BY date_trunc('`+resolution+`',time), typePK, instancePK
-		ORDER BY t ASC`, applicationID, int(typeID), timeRange[0], timeRange[1])
+		ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1])
 	case "full":
 		rows, err = dbR.Query(`SELECT instancePK, typePK, time as t, value
 		FROM app.metric
 		WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
-		AND typePK = $2
-		AND time >= $3 AND time <= $4
-		ORDER BY time ASC`, applicationID, int(typeID), timeRange[0], timeRange[1])
+		AND typePK `+typePKs+`
+		AND time >= $2 AND time <= $3
+		ORDER BY time ASC`, applicationID, timeRange[0], timeRange[1])
 	default:
 		return weft.InternalServerError(fmt.Errorf("invalid resolution: %s", resolution))
 	}
@@ -691,16 +774,16 @@ func (a appMetric) loadAppMetrics(applicationID, resolution string, typeID inter
 	defer rows.Close()
 
 	var t time.Time
-	var typePK, instancePK int
+	var instancePK int
 	var avg float64
 	var instanceID string
 	pts := make(map[InstanceMetric][]ts.Point)
 
 	for rows.Next() {
-		if err = rows.Scan(&instancePK, &typePK, &t, &avg); err != nil {
+		var key InstanceMetric
+		if err = rows.Scan(&key.instancePK, &key.typePK, &t, &avg); err != nil {
 			return weft.InternalServerError(err)
 		}
-		key := InstanceMetric{instancePK: instancePK, typePK: typePK}
 		pts[key] = append(pts[key], ts.Point{DateTime: t, Value: avg})
 	}
 	rows.Close()
@@ -721,6 +804,11 @@ func (a appMetric) loadAppMetrics(applicationID, resolution string, typeID inter
 	}
 	rows.Close()
 
+	types, err := appTypes()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
 	var keys InstanceMetrics
 
 	for k := range pts {
@@ -732,14 +820,10 @@ func (a appMetric) loadAppMetrics(applicationID, resolution string, typeID inter
 	var labels ts.Labels
 
 	for i, k := range keys {
-		if i > len(colours) {
-			i = 0
-		}
-
-		c := colours[i]
+		c := colours[i%len(colours)]
 
 		p.AddSeries(ts.Series{Colour: c, Points: pts[k]})
-		labels = append(labels, ts.Label{Colour: c, Label: fmt.Sprintf("%s.%s", instanceIDs[k.instancePK], internal.Label(k.typePK))})
+		labels = append(labels, ts.Label{Colour: c, Label: fmt.Sprintf("%s.%s", instanceIDs[k.instancePK], typeLabel(k.typePK, types))})
 	}
 
 	p.SetLabels(labels)
@@ -748,6 +832,24 @@ func (a appMetric) loadAppMetrics(applicationID, resolution string, typeID inter
 
 }
 
+// typeLabel returns the label for an app.type.  Types added with PUT /application/type are labelled with their typeID.
+func typeLabel(typePK int, types map[int]string) string {
+	if typePK >= dynamicTypePK {
+		return types[typePK]
+	}
+
+	return internal.Label(typePK)
+}
+
+// typeColour returns the colour for an app.type.
+func typeColour(typePK int) string {
+	if typePK >= dynamicTypePK {
+		return colours[typePK%len(colours)]
+	}
+
+	return internal.Colour(typePK)
+}
+
 // Returns a slice of two time.Time values if startDate and endDate are specified as params
 func parseTimeRange(v url.Values) (timeRange []time.Time, err error) {
 	res := v.Get("resolution")
@@ -786,6 +888,10 @@ func defaultTimeDelta(resolution string) (time.Duration, error) {
 		return time.Hour * 24 * 2, nil
 	case "hour":
 		return time.Hour * 24 * 28, nil
+	case "day":
+		return time.Hour * 24 * 365, nil
+	case "week":
+		return time.Hour * 24 * 365 * 5, nil
 	case "full":
 		return time.Hour * 24 * 40, nil
 	case "":
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/internal"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var unknownAppType = weft.Result{Ok: false, Code: http.StatusBadRequest, Msg: "unknown typeID"}
+
+// appKey is the key for a row in app.metric, app.counter, or app.timer for an application instance.
+type appKey struct {
+	pk      int // typePK or sourcePK
+	seconds int64
+}
+
+func applicationbatchHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+			return res
+		}
+
+		switch batchContentType(r) {
+		case "application/x-protobuf":
+			h.Set("Content-Type", "application/x-protobuf")
+			return applicationBatchProto(r, h, b)
+		default:
+			return weft.BadRequest("Content-Type must be application/x-protobuf")
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+/*
+applicationBatchProto saves the metrics, counters, and timers in an mtrpb.ApplicationBatch from the
+request body.  The result rows are numbered for the metrics followed by the counters then the timers.
+*/
+func applicationBatchProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var by []byte
+	var err error
+
+	if by, err = ioutil.ReadAll(r.Body); err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	var a mtrpb.ApplicationBatch
+
+	if err = proto.Unmarshal(by, &a); err != nil {
+		return weft.BadRequest("invalid protobuf: " + err.Error())
+	}
+
+	if a.ApplicationID == "" || a.InstanceID == "" {
+		return weft.BadRequest("applicationID and instanceID are required")
+	}
+
+	res := newBatchResult(len(a.Metric) + len(a.Counter) + len(a.Timer))
+
+	if s := saveApplicationBatch(&a, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "application/x-protobuf", b)
+}
+
+/*
+saveApplicationBatch inserts the rows in a into app.metric, app.counter, and app.timer in a
+single transaction.  The application, instance, and timer sources are added if required.
+The outcome for each row is set in res:
+
+	http.StatusOK - saved.
+	http.StatusTooManyRequests - there is already a row for the type or source at the time.
+	http.StatusBadRequest - the typeID or type name does not exist.
+*/
+func saveApplicationBatch(a *mtrpb.ApplicationBatch, res *mtrpb.BatchResult) *weft.Result {
+	// add the application, instance, and sources outside the transaction.  Errors are
+	// ignored - these could race from other handlers.
+	db.Exec(`INSERT INTO app.application(applicationID) SELECT $1
+			WHERE NOT EXISTS (SELECT 1 FROM app.application WHERE applicationID = $1)`, a.ApplicationID)
+	db.Exec(`INSERT INTO app.instance(instanceID) SELECT $1
+			WHERE NOT EXISTS (SELECT 1 FROM app.instance WHERE instanceID = $1)`, a.InstanceID)
+
+	sources := make(map[string]bool)
+	for _, v := range a.Timer {
+		if !sources[v.SourceID] {
+			sources[v.SourceID] = true
+			db.Exec(`INSERT INTO app.source(sourceID) SELECT $1
+					WHERE NOT EXISTS (SELECT 1 FROM app.source WHERE sourceID = $1)`, v.SourceID)
+		}
+	}
+
+	var err error
+	var txn *sql.Tx
+
+	if txn, err = db.Begin(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var applicationPK, instancePK int
+
+	if err = txn.QueryRow(`SELECT applicationPK, instancePK FROM app.application, app.instance
+			WHERE applicationID = $1
+			AND instanceID = $2`, a.ApplicationID, a.InstanceID).Scan(&applicationPK, &instancePK); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	// types is keyed by typePK and names by typeID for types added with PUT /application/type.
+	types := make(map[int32]bool)
+	names := make(map[string]int32)
+
+	var rows *sql.Rows
+	if rows, err = txn.Query(`SELECT typePK, typeID FROM app.type`); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	for rows.Next() {
+		var typePK int32
+		var typeID string
+		if err = rows.Scan(&typePK, &typeID); err != nil {
+			rows.Close()
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+		types[typePK] = true
+		names[typeID] = typePK
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	// row is the index of the first row for each table in res.
+	row := 0
+
+	// metrics
+	keys := make(map[int]appKey)
+	seen := make(map[appKey]bool)
+	var args [][]interface{}
+
+	for i, v := range a.Metric {
+		if v.TypeID == 0 {
+			v.TypeID = names[v.TypeName]
+		}
+
+		if !types[v.TypeID] {
+			setRow(res, row+i, &unknownAppType)
+			continue
+		}
+
+		k := appKey{pk: int(v.TypeID), seconds: v.Seconds}
+		if seen[k] {
+			setRow(res, row+i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[row+i] = k
+		args = append(args, []interface{}{applicationPK, instancePK, v.TypeID, time.Unix(v.Seconds, 0).UTC(), v.Value})
+	}
+
+	if s := insertAppBatch(txn, "app.metric", []string{"applicationPK", "instancePK", "typePK", "time", "value"},
+		[]string{"SMALLINT", "SMALLINT", "SMALLINT", "TIMESTAMPTZ", "BIGINT"}, args, keys, res); !s.Ok {
+		return s
+	}
+
+	row += len(a.Metric)
+
+	// counters
+	keys = make(map[int]appKey)
+	seen = make(map[appKey]bool)
+	args = nil
+
+	for i, v := range a.Counter {
+		if v.TypeID == 0 {
+			v.TypeID = names[v.TypeName]
+		}
+
+		if !types[v.TypeID] {
+			setRow(res, row+i, &unknownAppType)
+			continue
+		}
+
+		k := appKey{pk: int(v.TypeID), seconds: v.Seconds}
+		if seen[k] {
+			setRow(res, row+i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[row+i] = k
+		args = append(args, []interface{}{applicationPK, instancePK, v.TypeID, time.Unix(v.Seconds, 0).UTC(), v.Count})
+	}
+
+	if s := insertAppBatch(txn, "app.counter", []string{"applicationPK", "instancePK", "typePK", "time", "count"},
+		[]string{"SMALLINT", "SMALLINT", "SMALLINT", "TIMESTAMPTZ", "INTEGER"}, args, keys, res); !s.Ok {
+		return s
+	}
+
+	row += len(a.Counter)
+
+	// timers
+	source := newPKCache(`SELECT sourcePK FROM app.source WHERE sourceID = $1`)
+	keys = make(map[int]appKey)
+	seen = make(map[appKey]bool)
+	args = nil
+
+	for i, v := range a.Timer {
+		var ok bool
+		var k appKey
+
+		if k.pk, ok, err = source.get(txn, v.SourceID); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+		if !ok {
+			// the insert before the transaction failed.
+			setRow(res, row+i, weft.BadRequest("unknown sourceID"))
+			continue
+		}
+
+		// min, max, ninetynine, and sketch are null for timers from older versions of mtrapp.
+		var min, max, ninetyNine, sketch interface{}
+
+		if len(v.Sketch) > 0 {
+			if _, err = internal.UnmarshalSketch(v.Sketch); err != nil {
+				setRow(res, row+i, weft.BadRequest("invalid sketch"))
+				continue
+			}
+
+			min, max, ninetyNine, sketch = v.Min, v.Max, v.NinetyNine, v.Sketch
+		}
+
+		k.seconds = v.Seconds
+		if seen[k] {
+			setRow(res, row+i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[row+i] = k
+		args = append(args, []interface{}{applicationPK, instancePK, k.pk, time.Unix(v.Seconds, 0).UTC(),
+			v.Average, v.Count, v.Fifty, v.Ninety, min, max, ninetyNine, sketch})
+	}
+
+	if s := insertAppBatch(txn, "app.timer", []string{"applicationPK", "instancePK", "sourcePK", "time", "average", "count", "fifty", "ninety",
+		"min", "max", "ninetynine", "sketch"},
+		[]string{"SMALLINT", "SMALLINT", "INTEGER", "TIMESTAMPTZ", "INTEGER", "INTEGER", "INTEGER", "INTEGER",
+			"INTEGER", "INTEGER", "INTEGER", "BYTEA"}, args, keys, res); !s.Ok {
+		return s
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+/*
+insertAppBatch inserts rows into table with multi-row inserts.  The first four columns in cols must
+be applicationPK, instancePK, typePK or sourcePK, and time.  types are the Postgres types for cols.
+keys are the appKey for each row keyed by the row in res.  Rows that already exist are skipped and
+their row in res is set to statusTooManyRequests.
+
+The transaction is rolled back if the result is not ok.
+*/
+func insertAppBatch(txn *sql.Tx, table string, cols, types []string, rows [][]interface{},
+	keys map[int]appKey, res *mtrpb.BatchResult) *weft.Result {
+	inserted := make(map[appKey]bool)
+
+	for start := 0; start < len(rows); start += batchRows {
+		end := start + batchRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var args []interface{}
+		for _, r := range rows[start:end] {
+			args = append(args, r...)
+		}
+
+		rs, err := txn.Query(`INSERT INTO `+table+`(`+strings.Join(cols, ", ")+`)
+				SELECT * FROM (VALUES `+values(end-start, types)+`) AS v(`+strings.Join(cols, ", ")+`)
+				WHERE NOT EXISTS (SELECT 1 FROM `+table+` m
+					WHERE m.applicationPK = v.applicationPK
+					AND m.instancePK = v.instancePK
+					AND m.`+cols[2]+` = v.`+cols[2]+`
+					AND m.time = v.time)
+				RETURNING `+cols[2]+`, time`, args...)
+		if err != nil {
+			txn.Rollback()
+			if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+				// a concurrent upload for the same minute.
+				return &statusTooManyRequests
+			}
+			return weft.InternalServerError(err)
+		}
+
+		for rs.Next() {
+			var k appKey
+			var t time.Time
+
+			if err = rs.Scan(&k.pk, &t); err != nil {
+				rs.Close()
+				txn.Rollback()
+				return weft.InternalServerError(err)
+			}
+
+			k.seconds = t.Unix()
+			inserted[k] = true
+		}
+		rs.Close()
+
+		if err = rs.Err(); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	for i, k := range keys {
+		if !inserted[k] {
+			setRow(res, i, &statusTooManyRequests)
+		}
+	}
+
+	return &weft.StatusOK
+}
@@ -27,9 +27,9 @@ func applicationMetricPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft
 		return weft.BadRequest("invalid time")
 	}
 
-	// TODO could validate this from internal
-	if typePK, err = strconv.Atoi(v.Get("typeID")); err != nil {
-		return weft.BadRequest("invalid typeID")
+	var res *weft.Result
+	if typePK, res = appTypePK(v.Get("typeID")); !res.Ok {
+		return res
 	}
 
 	applicationID := v.Get("applicationID")
@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"database/sql"
+	"encoding/base64"
+	"github.com/GeoNet/mtr/internal"
 	"github.com/GeoNet/weft"
 	"net/http"
 	"strconv"
@@ -43,17 +45,45 @@ func applicationTimerPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.
 		return weft.BadRequest("invalid time")
 	}
 
+	// min, max, ninetynine, and sketch are null for timers from older versions of mtrapp.
+	var min, max, ninetyNine, sketch interface{}
+
+	if v.Get("sketch") != "" {
+		var by []byte
+		if by, err = base64.StdEncoding.DecodeString(v.Get("sketch")); err != nil {
+			return weft.BadRequest("invalid sketch")
+		}
+
+		if _, err = internal.UnmarshalSketch(by); err != nil {
+			return weft.BadRequest("invalid sketch")
+		}
+
+		sketch = by
+
+		for _, p := range []struct {
+			k string
+			v *interface{}
+		}{{"min", &min}, {"max", &max}, {"ninetynine", &ninetyNine}} {
+			var i int
+			if i, err = strconv.Atoi(v.Get(p.k)); err != nil {
+				return weft.BadRequest("invalid " + p.k)
+			}
+			*p.v = i
+		}
+	}
+
 	var result sql.Result
 
 	// If we insert one row then return.
 	// This will be the most common outcome.
-	if result, err = db.Exec(`INSERT INTO app.timer(applicationPK, instancePK, sourcePK, time, average, count, fifty, ninety)
-	 			SELECT applicationPK, instancePK, sourcePK, $4, $5, $6, $7, $8
+	if result, err = db.Exec(`INSERT INTO app.timer(applicationPK, instancePK, sourcePK, time, average, count, fifty, ninety,
+				min, max, ninetynine, sketch)
+	 			SELECT applicationPK, instancePK, sourcePK, $4, $5, $6, $7, $8, $9, $10, $11, $12
 	 			FROM app.application, app.instance, app.source
 				WHERE applicationID = $1
 				AND instanceID = $2
 				AND sourceID = $3`,
-		applicationID, instanceID, sourceID, t, average, count, fifty, ninety); err == nil {
+		applicationID, instanceID, sourceID, t, average, count, fifty, ninety, min, max, ninetyNine, sketch); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
@@ -70,13 +100,14 @@ func applicationTimerPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.
 	db.Exec(`INSERT INTO app.source(sourceID) VALUES($1)`, sourceID)
 
 	// Try to insert again - if we insert one row then return.
-	if result, err = db.Exec(`INSERT INTO app.timer(applicationPK, instancePK, sourcePK, time, average, count, fifty, ninety)
-	 			SELECT applicationPK, instancePK, sourcePK, $4, $5, $6, $7, $8
+	if result, err = db.Exec(`INSERT INTO app.timer(applicationPK, instancePK, sourcePK, time, average, count, fifty, ninety,
+				min, max, ninetynine, sketch)
+	 			SELECT applicationPK, instancePK, sourcePK, $4, $5, $6, $7, $8, $9, $10, $11, $12
 	 			FROM app.application, app.instance, app.source
 				WHERE applicationID = $1
 				AND instanceID = $2
 				AND sourceID = $3`,
-		applicationID, instanceID, sourceID, t, average, count, fifty, ninety); err == nil {
+		applicationID, instanceID, sourceID, t, average, count, fifty, ninety, min, max, ninetyNine, sketch); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/weft"
+	"github.com/lib/pq"
+	"net/http"
+	"strconv"
+)
+
+// dynamicTypePK is the first typePK for types added with PUT /application/type.
+// Lower typePKs are mtr.internal.ID.
+const dynamicTypePK = 10000
+
+// maxTypePK is the last typePK in app.type_seq.
+const maxTypePK = 32767
+
+/*
+applicationTypePut adds an app.type for an application counter or gauge e.g., quakes.published.
+The typePK is allocated from app.type_seq.  Adding a type that already exists is not an error.
+If description or unit are in the query an existing type is updated.
+*/
+func applicationTypePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	typeID := v.Get("typeID")
+
+	if res := validTypeID(typeID); !res.Ok {
+		return res
+	}
+
+	unit := v.Get("unit")
+	if unit == "" {
+		unit = "n"
+	}
+
+	if v.Get("description") != "" || v.Get("unit") != "" {
+		return appType.put(typeID, []string{"description", "unit"}, v.Get("description"), unit)
+	}
+
+	// nextval is only called if the type doesn't exist.
+	if _, err := db.Exec(`INSERT INTO app.type(typePK, typeID, description, unit)
+				SELECT nextval('app.type_seq'), $1, $2, $3
+				WHERE NOT EXISTS (SELECT 1 FROM app.type WHERE typeID = $1)`,
+		typeID, v.Get("description"), unit); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// added by a concurrent request.
+			return &weft.StatusOK
+		}
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+/*
+applicationTypeDelete deletes an app.type added with PUT /application/type.
+The mtr.internal types can't be deleted.
+*/
+func applicationTypeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var typePK int
+
+	switch err := db.QueryRow(`SELECT typePK FROM app.type WHERE typeID = $1`, r.URL.Query().Get("typeID")).Scan(&typePK); err {
+	case nil:
+		if typePK < dynamicTypePK {
+			return weft.BadRequest("can't delete an internal type")
+		}
+	case sql.ErrNoRows:
+		return &weft.StatusOK
+	default:
+		return weft.InternalServerError(err)
+	}
+
+	return appType.delete(r)
+}
+
+/*
+appTypePK returns the typePK for typeID.  typeID is either an mtr.internal.ID e.g., 1000
+or the typeID of a type added with PUT /application/type e.g., quakes.published.
+*/
+func appTypePK(typeID string) (int, *weft.Result) {
+	if typePK, err := strconv.Atoi(typeID); err == nil {
+		return typePK, &weft.StatusOK
+	}
+
+	var typePK int
+
+	switch err := db.QueryRow(`SELECT typePK FROM app.type WHERE typeID = $1`, typeID).Scan(&typePK); err {
+	case nil:
+		return typePK, &weft.StatusOK
+	case sql.ErrNoRows:
+		return 0, weft.BadRequest("invalid typeID")
+	default:
+		return 0, weft.InternalServerError(err)
+	}
+}
+
+// appTypes returns the typeID for each app.type keyed by typePK.
+func appTypes() (map[int]string, error) {
+	rows, err := dbR.Query(`SELECT typePK, typeID FROM app.type`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make(map[int]string)
+
+	for rows.Next() {
+		var typePK int
+		var typeID string
+
+		if err = rows.Scan(&typePK, &typeID); err != nil {
+			return nil, err
+		}
+
+		types[typePK] = typeID
+	}
+
+	return types, rows.Err()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/GeoNet/weft"
+	"github.com/lib/pq"
+	"net/http"
+)
+
+/*
+Devices and sites have a lifecycle status and arbitrary key value attributes e.g., owner or
+installed.  Retired devices and sites keep their history but are left out of summaries, maps,
+tag searches, and alerts.
+*/
+
+var statuses = map[string]bool{
+	"planned":     true,
+	"active":      true,
+	"maintenance": true,
+	"retired":     true,
+}
+
+// validStatus returns a bad request if status is not a lifecycle status.  An empty status is valid.
+func validStatus(status string) *weft.Result {
+	if status != "" && !statuses[status] {
+		return weft.BadRequest("status must be one of planned, active, maintenance, or retired")
+	}
+
+	return &weft.StatusOK
+}
+
+// attribute is the tables for the attributes of a device or site.
+type attribute struct {
+	table string // the attribute table e.g., field.device_attribute
+	owner string // the table the attributes are for e.g., field.device
+	pk    string // the primary key column in owner e.g., devicePK
+	id    string // the id column in owner and the query parameter for it e.g., deviceID
+}
+
+var (
+	fieldDeviceAttribute = attribute{table: "field.device_attribute", owner: "field.device", pk: "devicePK", id: "deviceID"}
+	dataSiteAttribute    = attribute{table: "data.site_attribute", owner: "data.site", pk: "sitePK", id: "siteID"}
+)
+
+// ownerPK returns the primary key for the device or site in the query or NotFound if it doesn't exist.
+func (a attribute) ownerPK(r *http.Request) (int, *weft.Result) {
+	var pk int
+
+	switch err := db.QueryRow(`SELECT `+a.pk+` FROM `+a.owner+` WHERE `+a.id+` = $1`,
+		r.URL.Query().Get(a.id)).Scan(&pk); err {
+	case nil:
+	case sql.ErrNoRows:
+		return 0, &weft.NotFound
+	default:
+		return 0, weft.InternalServerError(err)
+	}
+
+	return pk, &weft.StatusOK
+}
+
+// put adds or updates the attribute key with value from the query.
+func (a attribute) put(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	key := v.Get("key")
+	if key == "" {
+		return weft.BadRequest("empty key")
+	}
+
+	pk, res := a.ownerPK(r)
+	if !res.Ok {
+		return res
+	}
+
+	result, err := db.Exec(`UPDATE `+a.table+` SET value = $3 WHERE `+a.pk+` = $1 AND key = $2`, pk, key, v.Get("value"))
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	if i == 1 {
+		return &weft.StatusOK
+	}
+
+	if _, err = db.Exec(`INSERT INTO `+a.table+`(`+a.pk+`, key, value) VALUES ($1, $2, $3)`, pk, key, v.Get("value")); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// added by a concurrent request.
+			return &weft.StatusOK
+		}
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// delete deletes the attribute key from the query.  Deleting an attribute that doesn't exist is not an error.
+func (a attribute) delete(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	if _, err := db.Exec(`DELETE FROM `+a.table+`
+			WHERE `+a.pk+` = (SELECT `+a.pk+` FROM `+a.owner+` WHERE `+a.id+` = $1)
+			AND key = $2`, v.Get(a.id), v.Get("key")); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"github.com/GeoNet/mtr/mtrapp"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Batch uploads are POSTed with many rows in the request body and return a result
+for each row in the response body.  weft.MakeHandlerAPI only passes a non nil
+buffer for GET requests so POST requests are routed to batchMux by inbound (see server.go)
+and the handlers are wrapped with makeHandlerBatch instead of being generated from weft.toml.
+
+The body is either protobuf (Content-Type application/x-protobuf) or, where supported, CSV (Content-Type text/csv).
+The results are returned in the same format as the request; an mtrpb.BatchResult
+or CSV with the columns row,code,msg.
+*/
+
+// batchRows is the number of rows in each multi-row insert.  Postgres allows
+// at most 65535 parameters in a query.
+const batchRows = 1000
+
+var batchMux = http.NewServeMux()
+
+// batchKey is the primary key for a row in a rate limited metric table e.g., field.metric.
+type batchKey struct {
+	pk, typePK int
+	rateLimit  int64
+}
+
+// summaryKey is the primary key for a row in a summary table e.g., field.metric_summary.
+type summaryKey struct {
+	pk, typePK int
+}
+
+// pkCache caches primary keys looked up by ID with query e.g.,
+// SELECT devicePK FROM field.device WHERE deviceID = $1
+type pkCache struct {
+	query string
+	pk    map[string]int
+}
+
+// makeHandlerBatch executes f for POST requests with a non nil bytes.Buffer
+// and writes the response in b to the client.  n is used as the timer id.
+func makeHandlerBatch(n string, f weft.RequestHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t := mtrapp.Start()
+
+		var b bytes.Buffer
+
+		res := f(r, w.Header(), &b)
+		t.Stop()
+		weft.WriteBytes(w, r, res, &b, false)
+
+		t.Track(n + "." + r.Method)
+		res.Count()
+
+		if res.Code != http.StatusOK {
+			log.Printf("status: %d serving %s", res.Code, r.RequestURI)
+		}
+
+		if t.Taken() > 250 {
+			log.Printf("slow: took %d ms serving %s", t.Taken(), r.RequestURI)
+		}
+	}
+}
+
+// batchContentType returns the media type of the request body.
+func batchContentType(r *http.Request) string {
+	t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+
+	return t
+}
+
+// newBatchResult returns a BatchResult with a result for each of n rows.
+// All rows default to http.StatusOK.
+func newBatchResult(n int) mtrpb.BatchResult {
+	var res mtrpb.BatchResult
+
+	res.Result = make([]*mtrpb.BatchRowResult, n)
+	for i := range res.Result {
+		res.Result[i] = &mtrpb.BatchRowResult{Row: int32(i), Code: http.StatusOK}
+	}
+
+	return res
+}
+
+// setRow sets the result for row i to the failure in f.
+func setRow(res *mtrpb.BatchResult, i int, f *weft.Result) {
+	res.Result[i].Code = int32(f.Code)
+	res.Result[i].Msg = f.Msg
+}
+
+// writeBatchResult writes res to b as contentType (protobuf or CSV).
+func writeBatchResult(res *mtrpb.BatchResult, contentType string, b *bytes.Buffer) *weft.Result {
+	switch contentType {
+	case "application/x-protobuf":
+		by, err := proto.Marshal(res)
+		if err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		b.Write(by)
+	default:
+		w := csv.NewWriter(b)
+
+		w.Write([]string{"row", "code", "msg"})
+
+		for _, v := range res.Result {
+			w.Write([]string{strconv.Itoa(int(v.Row)), strconv.Itoa(int(v.Code)), v.Msg})
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	return &weft.StatusOK
+}
+
+func newPKCache(query string) pkCache {
+	return pkCache{query: query, pk: make(map[string]int)}
+}
+
+// get returns the primary key for id.  ok is false if id does not exist.
+func (c *pkCache) get(txn *sql.Tx, id string) (pk int, ok bool, err error) {
+	if pk, ok = c.pk[id]; ok {
+		return
+	}
+
+	err = txn.QueryRow(c.query, id).Scan(&pk)
+	switch err {
+	case nil:
+		c.pk[id] = pk
+		return pk, true, nil
+	case sql.ErrNoRows:
+		return 0, false, nil
+	default:
+		return 0, false, err
+	}
+}
+
+// values returns the placeholders for n rows in a multi-row VALUES list with the column types in types e.g.,
+// values(2, []string{"INTEGER", "TEXT"}) is ($1::INTEGER, $2::TEXT), ($3::INTEGER, $4::TEXT)
+func values(n int, types []string) string {
+	var s []string
+	var p int
+
+	for i := 0; i < n; i++ {
+		var c []string
+		for _, t := range types {
+			p++
+			c = append(c, fmt.Sprintf("$%d::%s", p, t))
+		}
+		s = append(s, "("+strings.Join(c, ", ")+")")
+	}
+
+	return strings.Join(s, ", ")
+}
+
+/*
+insertBatch inserts rows into table with multi-row inserts.  The first three columns in cols must
+be the primary key pk, typePK, and rate_limit.  types are the Postgres types for cols.
+Rows where there is already data for the minute are skipped so the transaction isn't aborted by a
+unique violation.  rows must not contain more than one row for the same batchKey.
+
+Returns the keys for the inserted rows.
+*/
+func insertBatch(txn *sql.Tx, table string, cols, types []string, rows [][]interface{}) (map[batchKey]bool, error) {
+	inserted := make(map[batchKey]bool)
+
+	for start := 0; start < len(rows); start += batchRows {
+		end := start + batchRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var args []interface{}
+		for _, r := range rows[start:end] {
+			args = append(args, r...)
+		}
+
+		rs, err := txn.Query(`INSERT INTO `+table+`(`+strings.Join(cols, ", ")+`)
+				SELECT * FROM (VALUES `+values(end-start, types)+`) AS v(`+strings.Join(cols, ", ")+`)
+				WHERE NOT EXISTS (SELECT 1 FROM `+table+` m
+					WHERE m.`+cols[0]+` = v.`+cols[0]+`
+					AND m.typePK = v.typePK
+					AND m.rate_limit = v.rate_limit)
+				RETURNING `+cols[0]+`, typePK, rate_limit`, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for rs.Next() {
+			var k batchKey
+			if err = rs.Scan(&k.pk, &k.typePK, &k.rateLimit); err != nil {
+				rs.Close()
+				return nil, err
+			}
+			inserted[k] = true
+		}
+		rs.Close()
+
+		if err = rs.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return inserted, nil
+}
+
+/*
+updateSummary updates the summary row in table if t is newer than the current summary
+or inserts it if it is the first time we've seen this metric.  pkCol is the name of the
+primary key column with typePK e.g., devicePK.  cols are the value columns in the summary
+and vals their values.
+*/
+func updateSummary(txn *sql.Tx, table, pkCol string, k summaryKey, t time.Time, cols []string, vals []interface{}) error {
+	var set, params []string
+	for i, c := range cols {
+		set = append(set, fmt.Sprintf("%s = $%d", c, i+4))
+		params = append(params, fmt.Sprintf("$%d", i+4))
+	}
+
+	args := append([]interface{}{k.pk, k.typePK, t}, vals...)
+
+	result, err := txn.Exec(`UPDATE `+table+` SET time = $3, `+strings.Join(set, ", ")+`
+				WHERE time < $3
+				AND `+pkCol+` = $1
+				AND typePK = $2`, args...)
+	if err != nil {
+		return err
+	}
+
+	var n int64
+	if n, err = result.RowsAffected(); err != nil {
+		return err
+	}
+
+	// If no rows change either the value is old or it's the first time we've seen this metric.
+	if n != 1 {
+		if _, err = txn.Exec(`INSERT INTO `+table+`(`+pkCol+`, typePK, time, `+strings.Join(cols, ", ")+`)
+				SELECT $1, $2, $3, `+strings.Join(params, ", ")+`
+				WHERE NOT EXISTS (SELECT 1 FROM `+table+`
+					WHERE `+pkCol+` = $1
+					AND typePK = $2)`, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
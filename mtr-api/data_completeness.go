@@ -219,11 +219,28 @@ func dataCompletenessPlot(siteID, typeID, resolution string, plotter ts.SVGPlot,
 		p.SetXLabel("4 weeks")
 
 		expectedf /= 24
-		rows, err = dbR.Query(`SELECT date_trunc('`+resolution+`',time) as t, sum(count) FROM data.completeness WHERE
+		rows, err = queryCompletenessRollup(sitePK, typePK, resolution, "28 days")
+	case "day":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365), time.Now().UTC())
+		p.SetXLabel("1 year")
+
+		rows, err = queryCompletenessRollup(sitePK, typePK, resolution, "365 days")
+	case "week":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365*5), time.Now().UTC())
+		p.SetXLabel("5 years")
+
+		expectedf *= 7
+		rows, err = dbR.Query(`SELECT date_trunc('week', t) as w, sum(c)::BIGINT FROM (
+		SELECT time as t, avg * count as c FROM data.completeness_day WHERE
 		sitePK = $1 AND typePK = $2
-		AND time > now() - interval '28 days'
-		GROUP BY date_trunc('`+resolution+`',time)
-		ORDER BY t ASC`,
+		AND time > now() - interval '1825 days'
+		UNION ALL
+		SELECT time, count FROM data.completeness WHERE
+		sitePK = $1 AND typePK = $2
+		AND time > now() - interval '1825 days'
+		AND time >= `+rolledUp("data.completeness", "day")+`) r
+		GROUP BY date_trunc('week', t)
+		ORDER BY w ASC`,
 			sitePK, typePK)
 	case "twelve_hours":
 		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*28), time.Now().UTC())
@@ -341,3 +358,21 @@ func dataCompletenessSpark(siteID, typeID string, b *bytes.Buffer) *weft.Result
 
 	return &weft.StatusOK
 }
+
+/*
+queryCompletenessRollup returns the time and sum of the counts at resolution (hour or day) for the last period
+e.g., 28 days.  Reads the rollup and completeness that hasn't been rolled up yet.
+*/
+func queryCompletenessRollup(sitePK, typePK int, resolution, period string) (*sql.Rows, error) {
+	return dbR.Query(`SELECT time as t, (avg * count)::BIGINT FROM data.completeness_`+resolution+` WHERE
+		sitePK = $1 AND typePK = $2
+		AND time > now() - interval '`+period+`'
+		UNION ALL
+		SELECT date_trunc('`+resolution+`',time) as t, sum(count) FROM data.completeness WHERE
+		sitePK = $1 AND typePK = $2
+		AND time > now() - interval '`+period+`'
+		AND time >= `+rolledUp("data.completeness", resolution)+`
+		GROUP BY date_trunc('`+resolution+`',time)
+		ORDER BY t ASC`,
+		sitePK, typePK)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// dataCompletenessBatch - batch uploads to table data.completeness
+type dataCompletenessBatch struct {
+	site, typ pkCache
+}
+
+func datacompletenessbatchHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+			return res
+		}
+
+		switch batchContentType(r) {
+		case "application/x-protobuf":
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataCompletenessBatchProto(r, h, b)
+		default:
+			return weft.BadRequest("Content-Type must be application/x-protobuf")
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+// dataCompletenessBatchProto saves the completeness counts in an mtrpb.DataCompletenessBatch from the request body.
+func dataCompletenessBatchProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var by []byte
+	var err error
+
+	if by, err = ioutil.ReadAll(r.Body); err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	var d mtrpb.DataCompletenessBatch
+
+	if err = proto.Unmarshal(by, &d); err != nil {
+		return weft.BadRequest("invalid protobuf: " + err.Error())
+	}
+
+	res := newBatchResult(len(d.Row))
+
+	if s := (&dataCompletenessBatch{}).save(d.Row, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "application/x-protobuf", b)
+}
+
+/*
+save inserts rows into data.completeness in a single transaction and updates data.completeness_summary
+with the newest count for each site and type in the batch.
+The outcome for each row is set in res:
+
+	http.StatusOK - saved.
+	http.StatusTooManyRequests - there is already data for the minute (see statusTooManyRequests).
+	http.StatusBadRequest - the siteID or typeID does not exist.
+*/
+func (d *dataCompletenessBatch) save(rows []*mtrpb.DataCompletenessBatchRow, res *mtrpb.BatchResult) *weft.Result {
+	d.site = newPKCache(`SELECT sitePK FROM data.site WHERE siteID = $1`)
+	d.typ = newPKCache(`SELECT typePK FROM data.completeness_type WHERE typeID = $1`)
+
+	var err error
+	var txn *sql.Tx
+
+	if txn, err = db.Begin(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	keys := make([]batchKey, len(rows))
+	seen := make(map[batchKey]bool)
+
+	var pending []int
+	var args [][]interface{}
+
+	for i, v := range rows {
+		var ok bool
+		var k batchKey
+
+		if k.pk, ok, err = d.site.get(txn, v.SiteID); err == nil && ok {
+			k.typePK, ok, err = d.typ.get(txn, v.TypeID)
+		}
+		if err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+		if !ok {
+			setRow(res, i, &unknownSiteType)
+			continue
+		}
+
+		t := time.Unix(v.Seconds, 0).UTC()
+		k.rateLimit = t.Truncate(time.Minute).Unix()
+
+		if seen[k] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[i] = k
+		pending = append(pending, i)
+		args = append(args, []interface{}{k.pk, k.typePK, k.rateLimit, t, v.Count})
+	}
+
+	var inserted map[batchKey]bool
+
+	if inserted, err = insertBatch(txn, "data.completeness",
+		[]string{"sitePK", "typePK", "rate_limit", "time", "count"},
+		[]string{"INTEGER", "SMALLINT", "BIGINT", "TIMESTAMPTZ", "INTEGER"}, args); err != nil {
+		txn.Rollback()
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// a concurrent upload for the same minute.
+			return &statusTooManyRequests
+		}
+		return weft.InternalServerError(err)
+	}
+
+	// the newest row in the batch for each summary.
+	latest := make(map[summaryKey]int)
+
+	for _, i := range pending {
+		if !inserted[keys[i]] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+
+		k := summaryKey{pk: keys[i].pk, typePK: keys[i].typePK}
+		if l, ok := latest[k]; !ok || rows[i].Seconds > rows[l].Seconds {
+			latest[k] = i
+		}
+	}
+
+	for k, i := range latest {
+		v := rows[i]
+		if err = updateSummary(txn, "data.completeness_summary", "sitePK", k, time.Unix(v.Seconds, 0).UTC(),
+			[]string{"count"}, []interface{}{v.Count}); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
@@ -23,10 +23,12 @@ func dataCompletenessSummaryProto(r *http.Request, h http.Header, b *bytes.Buffe
 
 	switch typeID {
 	case "":
-		rows, err = dbR.Query(`SELECT siteID, typeID, count, expected
+		rows, err = dbR.Query(`SELECT siteID, typeID, count, expected, ` + dataCompletenessInterval.late() + `
 		FROM data.completeness_summary
 		JOIN data.site USING (sitePK)
-		JOIN data.completeness_type USING (typePK)`)
+		JOIN data.completeness_type USING (typePK)
+		LEFT JOIN data.completeness_interval USING (sitePK, typePK)
+		WHERE status != 'retired'`)
 	default:
 		var typePK int
 		if err = dbR.QueryRow(`SELECT typePK FROM data.completeness_type WHERE typeID = $1`,
@@ -37,11 +39,13 @@ func dataCompletenessSummaryProto(r *http.Request, h http.Header, b *bytes.Buffe
 			return weft.InternalServerError(err)
 		}
 
-		rows, err = dbR.Query(`SELECT siteID, typeID, count, expected
+		rows, err = dbR.Query(`SELECT siteID, typeID, count, expected, `+dataCompletenessInterval.late()+`
 		FROM data.completeness_summary
 		JOIN data.site USING (sitePK)
 		JOIN data.completeness_type USING (typePK)
-		WHERE typeID = $1;`, typeID)
+		LEFT JOIN data.completeness_interval USING (sitePK, typePK)
+		WHERE typeID = $1
+		AND status != 'retired';`, typeID)
 	}
 
 	if err != nil {
@@ -56,13 +60,14 @@ func dataCompletenessSummaryProto(r *http.Request, h http.Header, b *bytes.Buffe
 	for rows.Next() {
 		var count int
 		var siteID string
+		var late bool
 
-		if err = rows.Scan(&siteID, &typeID, &count, &expected); err != nil {
+		if err = rows.Scan(&siteID, &typeID, &count, &expected, &late); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		c := float32(count) / (float32(expected) / 288)
-		dc := mtrpb.DataCompletenessSummary{TypeID: typeID, SiteID: siteID, Completeness: c, Seconds: t.Unix()}
+		dc := mtrpb.DataCompletenessSummary{TypeID: typeID, SiteID: siteID, Completeness: c, Seconds: t.Unix(), Late: late}
 		dcr.Result = append(dcr.Result, &dc)
 	}
 
@@ -103,15 +108,22 @@ func dataCompletenessSummarySvg(r *http.Request, h http.Header, b *bytes.Buffer)
 		return weft.InternalServerError(err)
 	}
 
-	if rows, err = dbR.Query(`with p as (select geom, time, count, expected,
-			st_transform(geom::geometry, 3857) as pt
+	at, res := parseTime(r.URL.Query())
+	if !res.Ok {
+		return res
+	}
+
+	if rows, err = dbR.Query(`with p as (select location.geom, time, count, expected,
+			st_transform(location.geom::geometry, 3857) as pt
 			FROM data.completeness_summary
 			JOIN data.site USING (sitePK)
+			JOIN `+dataSiteLocation.at("$3")+` AS location USING (sitePK)
 			JOIN data.completeness_type USING (typePK)
-			where typeID = $1)
+			where typeID = $1
+			AND status != 'retired')
 			select ST_X(pt), ST_Y(pt)*-1, ST_X(geom::geometry),ST_Y(geom::geometry), time,
 			count, expected from p
-			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt); err != nil {
+			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt, at); err != nil {
 		return weft.InternalServerError(err)
 	}
 
@@ -13,7 +13,6 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/lib/pq"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -24,32 +23,32 @@ func dataLatencyPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 	var err error
 
 	var t time.Time
-	var mean, min, max, fifty, ninety int
+	var mean, min, max, fifty, ninety float64
 
-	if mean, err = strconv.Atoi(v.Get("mean")); err != nil {
+	if mean, err = parseValue(v.Get("mean")); err != nil {
 		return weft.BadRequest("invalid value for mean")
 	}
 
 	if v.Get("min") != "" {
-		if min, err = strconv.Atoi(v.Get("min")); err != nil {
+		if min, err = parseValue(v.Get("min")); err != nil {
 			return weft.BadRequest("invalid value for min")
 		}
 	}
 
 	if v.Get("max") != "" {
-		if max, err = strconv.Atoi(v.Get("max")); err != nil {
+		if max, err = parseValue(v.Get("max")); err != nil {
 			return weft.BadRequest("invalid value for max")
 		}
 	}
 
 	if v.Get("fifty") != "" {
-		if fifty, err = strconv.Atoi(v.Get("fifty")); err != nil {
+		if fifty, err = parseValue(v.Get("fifty")); err != nil {
 			return weft.BadRequest("invalid value for fifty")
 		}
 	}
 
 	if v.Get("ninety") != "" {
-		if ninety, err = strconv.Atoi(v.Get("ninety")); err != nil {
+		if ninety, err = parseValue(v.Get("ninety")); err != nil {
 			return weft.BadRequest("invalid value for ninety")
 		}
 	}
@@ -69,7 +68,7 @@ func dataLatencyPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				WHERE siteID = $1
 				AND typeID = $2`,
 		siteID, typeID, t.Truncate(time.Minute).Unix(),
-		t, int32(mean), int32(min), int32(max), int32(fifty), int32(ninety)); err != nil {
+		t, mean, min, max, fifty, ninety); err != nil {
 		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
 			return &statusTooManyRequests
 		} else {
@@ -91,7 +90,7 @@ func dataLatencyPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				WHERE time < $3
 				AND sitePK = (SELECT sitePK from data.site WHERE siteID = $1)
 				AND typePK = (SELECT typePK from data.type WHERE typeID = $2)`,
-		siteID, typeID, t, int32(mean), int32(min), int32(max), int32(fifty), int32(ninety)); err != nil {
+		siteID, typeID, t, mean, min, max, fifty, ninety); err != nil {
 		return weft.InternalServerError(err)
 	}
 
@@ -105,7 +104,7 @@ func dataLatencyPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				FROM data.site, data.type
 				WHERE siteID = $1
 				AND typeID = $2`,
-			siteID, typeID, t, int32(mean), int32(min), int32(max), int32(fifty), int32(ninety)); err != nil {
+			siteID, typeID, t, mean, min, max, fifty, ninety); err != nil {
 			if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
 				// incoming value was old
 			} else {
@@ -234,15 +233,15 @@ func dataLatencyCsv(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 		// CSV data
 		var dl mtrpb.DataLatency // using a protobuf but just to temporarily hold data
 		var t time.Time
-		err := rows.Scan(&t, &dl.Mean, &dl.Fifty, &dl.Ninety)
+		err := rows.Scan(&t, &dl.FloatMean, &dl.FloatFifty, &dl.FloatNinety)
 		if err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		if err = w.Write([]string{t.Format(DYGRAPH_TIME_FORMAT),
-			fmt.Sprintf("%.2f", float64(dl.Mean)),
-			fmt.Sprintf("%.2f", float64(dl.Fifty)),
-			fmt.Sprintf("%.2f", float64(dl.Ninety))}); err != nil {
+			formatValue(dl.FloatMean),
+			formatValue(dl.FloatFifty),
+			formatValue(dl.FloatNinety)}); err != nil {
 			return weft.InternalServerError(err)
 		}
 		i++
@@ -291,9 +290,9 @@ func dataLatencyProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 		return weft.InternalServerError(err)
 	}
 
-	if err := dbR.QueryRow(`SELECT lower,upper FROM data.latency_threshold
+	if err := dbR.QueryRow(`SELECT lower,upper FROM `+dataLatencyThreshold+` AS threshold
 		WHERE sitePK = $1 AND typePK = $2`,
-		sitePK, typePK).Scan(&dlr.Lower, &dlr.Upper); err != nil && err != sql.ErrNoRows {
+		sitePK, typePK).Scan(&dlr.FloatLower, &dlr.FloatUpper); err != nil && err != sql.ErrNoRows {
 		return weft.InternalServerError(err)
 	}
 
@@ -311,16 +310,20 @@ func dataLatencyProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 	for rows.Next() {
 		var dl mtrpb.DataLatency
 		var t time.Time
-		if err = rows.Scan(&t, &dl.Mean, &dl.Fifty, &dl.Ninety); err != nil {
+		if err = rows.Scan(&t, &dl.FloatMean, &dl.FloatFifty, &dl.FloatNinety); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		dl.Seconds = t.Unix()
+		roundDataLatency(&dl)
+
 		dlr.Result = append(dlr.Result, &dl)
 	}
 
 	var by []byte
 
+	roundDataLatencyResult(&dlr)
+
 	if by, err = proto.Marshal(&dlr); err != nil {
 		return weft.InternalServerError(err)
 	}
@@ -358,16 +361,16 @@ func dataLatencyPlot(siteID, typeID, resolution string, plotter ts.SVGPlot, b *b
 
 	p.SetUnit(display)
 
-	var lower, upper int
+	var lower, upper, warningLower, warningUpper float64
 
-	if err := dbR.QueryRow(`SELECT lower,upper FROM data.latency_threshold
+	if err := dbR.QueryRow(`SELECT lower,upper,warning_lower,warning_upper FROM `+dataLatencyThreshold+` AS threshold
 		WHERE sitePK = $1 AND typePK = $2`,
-		sitePK, typePK).Scan(&lower, &upper); err != nil && err != sql.ErrNoRows {
+		sitePK, typePK).Scan(&lower, &upper, &warningLower, &warningUpper); err != nil && err != sql.ErrNoRows {
 		return weft.InternalServerError(err)
 	}
 
 	if !(lower == 0 && upper == 0) {
-		p.SetThreshold(float64(lower)*scale, float64(upper)*scale)
+		p.SetThreshold(lower*scale, upper*scale, warningLower*scale, warningUpper*scale)
 	}
 
 	var tags []string
@@ -405,6 +408,12 @@ func dataLatencyPlot(siteID, typeID, resolution string, plotter ts.SVGPlot, b *b
 	case "hour":
 		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*28), time.Now().UTC())
 		p.SetXLabel("4 weeks")
+	case "day":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365), time.Now().UTC())
+		p.SetXLabel("1 year")
+	case "week":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365*5), time.Now().UTC())
+		p.SetXLabel("5 years")
 	default:
 		return weft.BadRequest("invalid resolution")
 	}
@@ -423,9 +432,7 @@ func dataLatencyPlot(siteID, typeID, resolution string, plotter ts.SVGPlot, b *b
 
 	pts := make(map[internal.ID]([]ts.Point))
 
-	var mean float64
-	var fifty int
-	var ninety int
+	var mean, fifty, ninety float64
 	var pt ts.Point
 
 	for rows.Next() {
@@ -435,10 +442,10 @@ func dataLatencyPlot(siteID, typeID, resolution string, plotter ts.SVGPlot, b *b
 		pt.Value = mean * scale
 		pts[internal.Mean] = append(pts[internal.Mean], pt)
 
-		pt.Value = float64(fifty) * scale
+		pt.Value = fifty * scale
 		pts[internal.Fifty] = append(pts[internal.Fifty], pt)
 
-		pt.Value = float64(ninety) * scale
+		pt.Value = ninety * scale
 		pts[internal.Ninety] = append(pts[internal.Ninety], pt)
 
 	}
@@ -458,10 +465,10 @@ func dataLatencyPlot(siteID, typeID, resolution string, plotter ts.SVGPlot, b *b
 	p.SetLatest(pt, internal.Colour(int(internal.Mean)))
 
 	// No latest label for fifty and ninety
-	pt.Value = float64(fifty) * scale
+	pt.Value = fifty * scale
 	pts[internal.Fifty] = append(pts[internal.Fifty], pt)
 
-	pt.Value = float64(ninety) * scale
+	pt.Value = ninety * scale
 	pts[internal.Ninety] = append(pts[internal.Ninety], pt)
 
 	for k, v := range pts {
@@ -548,13 +555,32 @@ func queryLatencyRows(sitePK, typePK int, resolution string, timeRange []time.Ti
 		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min'
 		ORDER BY t ASC`,
 			sitePK, typePK, timeRange[0], timeRange[1])
-	case "hour":
-		rows, err = dbR.Query(`SELECT date_trunc('`+resolution+`',time) as t, avg(mean), max(fifty), max(ninety) FROM data.latency WHERE
+	case "hour", "day":
+		// read the rollup and latencies that haven't been rolled up yet.
+		rows, err = dbR.Query(`SELECT time as t, avg, fifty, ninety FROM data.latency_`+resolution+` WHERE
+		sitePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		UNION ALL
+		SELECT date_trunc('`+resolution+`',time) as t, avg(mean), max(fifty), max(ninety) FROM data.latency WHERE
 		sitePK = $1 AND typePK = $2
 		AND time >= $3 AND time <= $4
+		AND time >= `+rolledUp("data.latency", resolution)+`
 		GROUP BY date_trunc('`+resolution+`',time)
 		ORDER BY t ASC`,
 			sitePK, typePK, timeRange[0], timeRange[1])
+	case "week":
+		rows, err = dbR.Query(`SELECT date_trunc('week', t) as w, sum(a * n) / sum(n), max(f), max(ni) FROM (
+		SELECT time as t, avg as a, count as n, fifty as f, ninety as ni FROM data.latency_day WHERE
+		sitePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		UNION ALL
+		SELECT time, mean, 1, fifty, ninety FROM data.latency WHERE
+		sitePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		AND time >= `+rolledUp("data.latency", "day")+`) r
+		GROUP BY date_trunc('week', t)
+		ORDER BY w ASC`,
+			sitePK, typePK, timeRange[0], timeRange[1])
 	case "full":
 		rows, err = dbR.Query(`SELECT time, mean, fifty, ninety FROM data.latency WHERE
 		sitePK = $1 AND typePK = $2
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+var unknownSiteType = weft.Result{Ok: false, Code: http.StatusBadRequest, Msg: "unknown siteID or typeID"}
+
+// dataLatencyBatch - batch uploads to table data.latency
+type dataLatencyBatch struct {
+	site, typ pkCache
+}
+
+func datalatencybatchHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+			return res
+		}
+
+		switch batchContentType(r) {
+		case "application/x-protobuf":
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataLatencyBatchProto(r, h, b)
+		default:
+			return weft.BadRequest("Content-Type must be application/x-protobuf")
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+// dataLatencyBatchProto saves the latencies in an mtrpb.DataLatencyBatch from the request body.
+func dataLatencyBatchProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var by []byte
+	var err error
+
+	if by, err = ioutil.ReadAll(r.Body); err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	var d mtrpb.DataLatencyBatch
+
+	if err = proto.Unmarshal(by, &d); err != nil {
+		return weft.BadRequest("invalid protobuf: " + err.Error())
+	}
+
+	res := newBatchResult(len(d.Row))
+
+	if s := (&dataLatencyBatch{}).save(d.Row, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "application/x-protobuf", b)
+}
+
+/*
+save inserts rows into data.latency in a single transaction and updates data.latency_summary
+with the newest values for each site and type in the batch.  nil rows are skipped.
+The outcome for each row is set in res:
+
+	http.StatusOK - saved.
+	http.StatusTooManyRequests - there is already data for the minute (see statusTooManyRequests).
+	http.StatusBadRequest - the siteID or typeID does not exist.
+*/
+func (d *dataLatencyBatch) save(rows []*mtrpb.DataLatencyBatchRow, res *mtrpb.BatchResult) *weft.Result {
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	if s := d.saveTxn(txn, rows, res); !s.Ok {
+		txn.Rollback()
+		return s
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// saveTxn is save in txn.  If the result is not ok txn must be rolled back.
+func (d *dataLatencyBatch) saveTxn(txn *sql.Tx, rows []*mtrpb.DataLatencyBatchRow, res *mtrpb.BatchResult) *weft.Result {
+	d.site = newPKCache(`SELECT sitePK FROM data.site WHERE siteID = $1`)
+	d.typ = newPKCache(`SELECT typePK FROM data.type WHERE typeID = $1`)
+
+	var err error
+
+	keys := make([]batchKey, len(rows))
+	seen := make(map[batchKey]bool)
+
+	var pending []int
+	var args [][]interface{}
+
+	for i, v := range rows {
+		if v == nil {
+			continue
+		}
+
+		var ok bool
+		var k batchKey
+
+		if k.pk, ok, err = d.site.get(txn, v.SiteID); err == nil && ok {
+			k.typePK, ok, err = d.typ.get(txn, v.TypeID)
+		}
+		if err != nil {
+			return weft.InternalServerError(err)
+		}
+		if !ok {
+			setRow(res, i, &unknownSiteType)
+			continue
+		}
+
+		t := time.Unix(v.Seconds, 0).UTC()
+		k.rateLimit = t.Truncate(time.Minute).Unix()
+
+		if seen[k] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[i] = k
+		pending = append(pending, i)
+		args = append(args, append([]interface{}{k.pk, k.typePK, k.rateLimit, t}, latencyValues(v)...))
+	}
+
+	var inserted map[batchKey]bool
+
+	if inserted, err = insertBatch(txn, "data.latency",
+		[]string{"sitePK", "typePK", "rate_limit", "time", "mean", "min", "max", "fifty", "ninety"},
+		[]string{"INTEGER", "SMALLINT", "BIGINT", "TIMESTAMPTZ", "DOUBLE PRECISION", "DOUBLE PRECISION", "DOUBLE PRECISION",
+			"DOUBLE PRECISION", "DOUBLE PRECISION"}, args); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// a concurrent upload for the same minute.
+			return &statusTooManyRequests
+		}
+		return weft.InternalServerError(err)
+	}
+
+	// the newest row in the batch for each summary.
+	latest := make(map[summaryKey]int)
+
+	for _, i := range pending {
+		if !inserted[keys[i]] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+
+		k := summaryKey{pk: keys[i].pk, typePK: keys[i].typePK}
+		if l, ok := latest[k]; !ok || rows[i].Seconds > rows[l].Seconds {
+			latest[k] = i
+		}
+	}
+
+	for k, i := range latest {
+		v := rows[i]
+		if err = updateSummary(txn, "data.latency_summary", "sitePK", k, time.Unix(v.Seconds, 0).UTC(),
+			[]string{"mean", "min", "max", "fifty", "ninety"},
+			latencyValues(v)); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	return &weft.StatusOK
+}
+
+// latencyValues returns mean, min, max, fifty, and ninety for r.  The float latencies are
+// used if they are set.
+func latencyValues(r *mtrpb.DataLatencyBatchRow) []interface{} {
+	var v []interface{}
+
+	for _, l := range []struct {
+		i int32
+		f float64
+	}{
+		{r.Mean, r.FloatMean},
+		{r.Min, r.FloatMin},
+		{r.Max, r.FloatMax},
+		{r.Fifty, r.FloatFifty},
+		{r.Ninety, r.FloatNinety},
+	} {
+		if l.f != 0.0 {
+			v = append(v, l.f)
+			continue
+		}
+
+		v = append(v, float64(l.i))
+	}
+
+	return v
+}
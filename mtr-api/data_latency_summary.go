@@ -23,18 +23,22 @@ func dataLatencySummaryProto(r *http.Request, h http.Header, b *bytes.Buffer) *w
 
 	switch typeID {
 	case "":
-		rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, lower, upper, scale
+		rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, ` + dataLatencyInterval.late() + `
 		FROM data.latency_summary
 		JOIN data.site USING (sitePK)
-		JOIN data.latency_threshold USING (sitePK, typePK)
-		JOIN data.type USING (typePK)`)
+		LEFT JOIN ` + dataLatencyThreshold + ` AS threshold USING (sitePK, typePK)
+		JOIN data.type USING (typePK)
+		LEFT JOIN data.latency_interval USING (sitePK, typePK)
+		WHERE status != 'retired'`)
 	default:
-		rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, lower, upper, scale
+		rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, `+dataLatencyInterval.late()+`
 		FROM data.latency_summary
 		JOIN data.site USING (sitePK)
-		JOIN data.latency_threshold USING (sitePK, typePK)
+		LEFT JOIN `+dataLatencyThreshold+` AS threshold USING (sitePK, typePK)
 		JOIN data.type USING (typePK)
-		WHERE typeID = $1;`, typeID)
+		LEFT JOIN data.latency_interval USING (sitePK, typePK)
+		WHERE typeID = $1
+		AND status != 'retired';`, typeID)
 	}
 	if err != nil {
 		return weft.InternalServerError(err)
@@ -49,12 +53,15 @@ func dataLatencySummaryProto(r *http.Request, h http.Header, b *bytes.Buffer) *w
 
 		var dls mtrpb.DataLatencySummary
 
-		if err = rows.Scan(&dls.SiteID, &dls.TypeID, &t, &dls.Mean, &dls.Fifty, &dls.Ninety,
-			&dls.Lower, &dls.Upper, &dls.Scale); err != nil {
+		if err = rows.Scan(&dls.SiteID, &dls.TypeID, &t, &dls.FloatMean, &dls.FloatFifty, &dls.FloatNinety,
+			&dls.FloatLower, &dls.FloatUpper, &dls.FloatWarningLower, &dls.FloatWarningUpper, &dls.Scale, &dls.Late); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		dls.Seconds = t.Unix()
+		dls.Unknown = dls.FloatLower == 0 && dls.FloatUpper == 0
+
+		roundDataLatencySummary(&dls)
 
 		dlsr.Result = append(dlsr.Result, &dls)
 	}
@@ -97,16 +104,24 @@ func dataLatencySummarySvg(r *http.Request, h http.Header, b *bytes.Buffer) *wef
 		return weft.InternalServerError(err)
 	}
 
-	if rows, err = dbR.Query(`with p as (select geom, time, mean, lower, upper,
-			st_transform(geom::geometry, 3857) as pt
+	at, res := parseTime(r.URL.Query())
+	if !res.Ok {
+		return res
+	}
+
+	if rows, err = dbR.Query(`with p as (select location.geom, time, mean, COALESCE(lower, 0) AS lower, COALESCE(upper, 0) AS upper,
+			COALESCE(warning_lower, 0) AS warning_lower, COALESCE(warning_upper, 0) AS warning_upper,
+			st_transform(location.geom::geometry, 3857) as pt
 			FROM data.latency_summary
 			JOIN data.site USING (sitePK)
+			JOIN `+dataSiteLocation.at("$3")+` AS location USING (sitePK)
 			JOIN data.type USING (typePK)
-			JOIN data.latency_threshold USING (sitePK, typePK)
-			where typeID = $1)
+			LEFT JOIN `+dataLatencyThreshold+` AS threshold USING (sitePK, typePK)
+			where typeID = $1
+			AND status != 'retired')
 			select ST_X(pt), ST_Y(pt)*-1, ST_X(geom::geometry),ST_Y(geom::geometry), time,
-			mean, lower,upper from p
-			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt); err != nil {
+			mean, lower,upper, warning_lower, warning_upper from p
+			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt, at); err != nil {
 		return weft.InternalServerError(err)
 	}
 
@@ -116,15 +131,18 @@ func dataLatencySummarySvg(r *http.Request, h http.Header, b *bytes.Buffer) *wef
 
 	var late []point
 	var good []point
+	var warning []point
 	var bad []point
 	var dunno []point
 
 	for rows.Next() {
 		var p point
 		var t time.Time
-		var min, max, v int
+		var th threshold
+		var v float64
 
-		if err = rows.Scan(&p.x, &p.y, &p.longitude, &p.latitude, &t, &v, &min, &max); err != nil {
+		if err = rows.Scan(&p.x, &p.y, &p.longitude, &p.latitude, &t, &v,
+			&th.lower, &th.upper, &th.warningLower, &th.warningUpper); err != nil {
 			return weft.InternalServerError(err)
 		}
 
@@ -144,10 +162,12 @@ func dataLatencySummarySvg(r *http.Request, h http.Header, b *bytes.Buffer) *wef
 		switch {
 		case t.Before(ago):
 			late = append(late, p)
-		case min == 0 && max == 0:
+		case th.lower == 0 && th.upper == 0:
 			dunno = append(dunno, p)
-		case v < min || v > max:
+		case th.critical(v):
 			bad = append(bad, p)
+		case th.warning(v):
+			warning = append(warning, p)
 		default:
 			good = append(good, p)
 		}
@@ -173,6 +193,12 @@ func dataLatencySummarySvg(r *http.Request, h http.Header, b *bytes.Buffer) *wef
 	}
 	b.WriteString("</g>")
 
+	b.WriteString("<g style=\"stroke: #ff7f00; fill: #ff7f00; \">") // amber
+	for _, p := range warning {
+		b.WriteString(fmt.Sprintf("<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%d\"/>", p.x, p.y, 5))
+	}
+	b.WriteString("</g>")
+
 	b.WriteString("<g style=\"stroke: #e41a1c; fill: #e41a1c; \">") //red
 	for _, p := range bad {
 		b.WriteString(fmt.Sprintf("<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%d\"/>", p.x, p.y, 6))
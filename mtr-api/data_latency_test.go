@@ -26,7 +26,7 @@ func TestDataLatencyCsv(t *testing.T) {
 
 	type latencyTest struct {
 		time                time.Time
-		mean, fifty, ninety float32
+		mean, fifty, ninety float64
 	}
 
 	utcNow := time.Now().UTC().Truncate(time.Second)
@@ -42,10 +42,10 @@ func TestDataLatencyCsv(t *testing.T) {
 
 	expectedVals := [][]string{
 		{""}, // header line, ignored in test.
-		{latencyTestData[0].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", latencyTestData[0].mean), fmt.Sprintf("%.2f", latencyTestData[0].fifty), fmt.Sprintf("%.2f", latencyTestData[0].ninety)},
-		//{latencyTestData[1].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", latencyTestData[1].mean), fmt.Sprintf("%.2f", latencyTestData[1].fifty), fmt.Sprintf("%.2f", latencyTestData[1].ninety)},
-		//{latencyTestData[2].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", latencyTestData[2].mean), fmt.Sprintf("%.2f", latencyTestData[2].fifty), fmt.Sprintf("%.2f", latencyTestData[2].ninety)},
-		//{latencyTestData[3].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", latencyTestData[3].mean), fmt.Sprintf("%.2f", latencyTestData[3].fifty), fmt.Sprintf("%.2f", latencyTestData[3].ninety)},
+		{latencyTestData[0].time.Format(DYGRAPH_TIME_FORMAT), formatValue(latencyTestData[0].mean), formatValue(latencyTestData[0].fifty), formatValue(latencyTestData[0].ninety)},
+		//{latencyTestData[1].time.Format(DYGRAPH_TIME_FORMAT), formatValue(latencyTestData[1].mean), formatValue(latencyTestData[1].fifty), formatValue(latencyTestData[1].ninety)},
+		//{latencyTestData[2].time.Format(DYGRAPH_TIME_FORMAT), formatValue(latencyTestData[2].mean), formatValue(latencyTestData[2].fifty), formatValue(latencyTestData[2].ninety)},
+		//{latencyTestData[3].time.Format(DYGRAPH_TIME_FORMAT), formatValue(latencyTestData[3].mean), formatValue(latencyTestData[3].fifty), formatValue(latencyTestData[3].ninety)},
 	}
 
 	// Add metrics
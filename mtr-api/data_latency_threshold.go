@@ -9,37 +9,36 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/lib/pq"
 	"net/http"
-	"strconv"
+	"time"
 )
 
 func dataLatencyThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	v := r.URL.Query()
 	var err error
 
-	var lower, upper int
-
-	if lower, err = strconv.Atoi(v.Get("lower")); err != nil {
-		return weft.BadRequest("invalid lower")
-	}
-
-	if upper, err = strconv.Atoi(v.Get("upper")); err != nil {
-		return weft.BadRequest("invalid upper")
+	t, res := parseThreshold(v)
+	if !res.Ok {
+		return res
 	}
 
 	siteID := v.Get("siteID")
 	typeID := v.Get("typeID")
 
+	if siteID == "" {
+		return dataLatencyTypeThresholdPut(typeID, t)
+	}
+
 	var result sql.Result
 
 	// TODO Change to upsert 9.5
 
 	// return if insert succeeds
-	if result, err = db.Exec(`INSERT INTO data.latency_threshold(sitePK, typePK, lower, upper)
-				SELECT sitePK, typePK, $3, $4
+	if result, err = db.Exec(`INSERT INTO data.latency_threshold(sitePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis)
+				SELECT sitePK, typePK, $3, $4, $5, $6, $7
 				FROM data.site, data.type
 				WHERE siteID = $1
 				AND typeID = $2`,
-		siteID, typeID, lower, upper); err == nil {
+		siteID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
@@ -51,10 +50,10 @@ func dataLatencyThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *w
 
 	// return if update one row
 	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
-		if result, err = db.Exec(`UPDATE data.latency_threshold SET lower=$3, upper=$4
+		if result, err = db.Exec(`UPDATE data.latency_threshold SET lower=$3, upper=$4, warning_lower=$5, warning_upper=$6, hysteresis=$7
 				WHERE sitePK = (SELECT sitePK FROM data.site WHERE siteID = $1)
 				AND typePK = (SELECT typePK FROM data.type WHERE typeID = $2)`,
-			siteID, typeID, lower, upper); err == nil {
+			siteID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err == nil {
 			var i int64
 			if i, err = result.RowsAffected(); err != nil {
 				return weft.InternalServerError(err)
@@ -72,9 +71,56 @@ func dataLatencyThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *w
 	return weft.InternalServerError(err)
 }
 
+/*
+dataLatencyTypeThresholdPut sets the threshold template for typeID at all sites.
+Sites with their own threshold for typeID are not changed.
+*/
+func dataLatencyTypeThresholdPut(typeID string, t threshold) *weft.Result {
+	var result sql.Result
+	var err error
+
+	// return if insert succeeds or update if there is already a template.
+	if result, err = db.Exec(`INSERT INTO data.latency_type_threshold(typePK, lower, upper, warning_lower, warning_upper, hysteresis)
+				SELECT typePK, $2, $3, $4, $5, $6
+				FROM data.type
+				WHERE typeID = $1`,
+		typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err != nil {
+		if pqErr, ok := err.(*pq.Error); !ok || pqErr.Code != errorUniqueViolation {
+			return weft.InternalServerError(err)
+		}
+
+		if result, err = db.Exec(`UPDATE data.latency_type_threshold SET lower=$2, upper=$3, warning_lower=$4, warning_upper=$5, hysteresis=$6
+				WHERE typePK = (SELECT typePK FROM data.type WHERE typeID = $1)`,
+			typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return weft.InternalServerError(err)
+	}
+	if i != 1 {
+		return weft.BadRequest("Didn't create row, check your query parameters exist")
+	}
+
+	return &weft.StatusOK
+}
+
+// dataLatencyThresholdDelete deletes the threshold for the site or, if there is no siteID, the template for the type.
 func dataLatencyThresholdDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	v := r.URL.Query()
 
+	if v.Get("siteID") == "" {
+		if _, err := db.Exec(`DELETE FROM data.latency_type_threshold
+				WHERE typePK = (SELECT typePK FROM data.type WHERE typeID = $1)`,
+			v.Get("typeID")); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		return &weft.StatusOK
+	}
+
 	if _, err := db.Exec(`DELETE FROM data.latency_threshold
 				WHERE sitePK = (SELECT sitePK FROM data.site WHERE siteID = $1)
 				AND typePK = (SELECT typePK FROM data.type WHERE typeID = $2)`,
@@ -85,6 +131,10 @@ func dataLatencyThresholdDelete(r *http.Request, h http.Header, b *bytes.Buffer)
 	return &weft.StatusOK
 }
 
+/*
+dataLatencyThresholdProto returns the threshold in effect for each site, and whether it is inherited from the
+template for the type or overridden for the site, followed by the templates.
+*/
 func dataLatencyThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	var err error
 	var rows *sql.Rows
@@ -94,8 +144,8 @@ func dataLatencyThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer)
 	siteID := v.Get("siteID")
 
 	args := []interface{}{} // empty SQL query args
-	sqlQuery := `SELECT siteID, typeID, lower, upper, scale
-		FROM data.latency_threshold
+	sqlQuery := `SELECT siteID, typeID, lower, upper, warning_lower, warning_upper, hysteresis, scale, inherited
+		FROM ` + dataLatencyThreshold + ` AS threshold
 		JOIN data.site USING (sitepk)
 		JOIN data.type USING (typepk)`
 
@@ -114,18 +164,46 @@ func dataLatencyThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer)
 	if rows, err = dbR.Query(sqlQuery, args...); err != nil {
 		return weft.InternalServerError(err)
 	}
+	defer rows.Close()
 
 	var ts mtrpb.DataLatencyThresholdResult
 
 	for rows.Next() {
 		var t mtrpb.DataLatencyThreshold
 
-		if err = rows.Scan(&t.SiteID, &t.TypeID, &t.Lower, &t.Upper, &t.Scale); err != nil {
+		if err = rows.Scan(&t.SiteID, &t.TypeID, &t.FloatLower, &t.FloatUpper,
+			&t.FloatWarningLower, &t.FloatWarningUpper, &t.FloatHysteresis, &t.Scale, &t.Inherited); err != nil {
 			return weft.InternalServerError(err)
 		}
 
+		roundDataLatencyThreshold(&t)
+
 		ts.Result = append(ts.Result, &t)
 	}
+	rows.Close()
+
+	if rows, err = dbR.Query(`SELECT typeID, lower, upper, warning_lower, warning_upper, hysteresis, scale
+		FROM data.latency_type_threshold
+		JOIN data.type USING (typepk)
+		WHERE $1 = '' OR typeID = $1
+		ORDER BY typeID`, typeID); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t mtrpb.DataLatencyThreshold
+
+		if err = rows.Scan(&t.TypeID, &t.FloatLower, &t.FloatUpper,
+			&t.FloatWarningLower, &t.FloatWarningUpper, &t.FloatHysteresis, &t.Scale); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		roundDataLatencyThreshold(&t)
+
+		ts.Template = append(ts.Template, &t)
+	}
+	rows.Close()
 
 	var by []byte
 	if by, err = proto.Marshal(&ts); err != nil {
@@ -136,3 +214,51 @@ func dataLatencyThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer)
 
 	return &weft.StatusOK
 }
+
+/*
+dataLatencyThresholdMissingProto returns the latest latency for the sites and types that have no
+threshold so they can be found and fixed.  Latencies with a threshold of lower and upper 0 are included.
+*/
+func dataLatencyThresholdMissingProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var err error
+	var rows *sql.Rows
+
+	if rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, scale, ` + dataLatencyInterval.late() + `
+		FROM data.latency_summary
+		JOIN data.site USING (sitePK)
+		JOIN data.type USING (typePK)
+		LEFT JOIN ` + dataLatencyThreshold + ` AS threshold USING (sitePK, typePK)
+		LEFT JOIN data.latency_interval USING (sitePK, typePK)
+		WHERE COALESCE(lower, 0) = 0 AND COALESCE(upper, 0) = 0
+		ORDER BY siteID, typeID`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var t time.Time
+	var dlsr mtrpb.DataLatencySummaryResult
+
+	for rows.Next() {
+		var dls = mtrpb.DataLatencySummary{Unknown: true}
+
+		if err = rows.Scan(&dls.SiteID, &dls.TypeID, &t, &dls.FloatMean, &dls.FloatFifty, &dls.FloatNinety, &dls.Scale, &dls.Late); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		dls.Seconds = t.Unix()
+
+		roundDataLatencySummary(&dls)
+
+		dlsr.Result = append(dlsr.Result, &dls)
+	}
+	rows.Close()
+
+	var by []byte
+	if by, err = proto.Marshal(&dlsr); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
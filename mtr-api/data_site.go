@@ -10,8 +10,15 @@ import (
 	"github.com/lib/pq"
 	"net/http"
 	"strconv"
+	"time"
 )
 
+/*
+dataSitePut adds or updates the site.  status is optional.  A new site is active unless
+a status is given and the status of an existing site is only changed if a status is given.
+A changed location is added to the location history from now.  Use /data/site/location to
+record a move at another time and /data/site/rename to change the siteID.
+*/
 func dataSitePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	v := r.URL.Query()
 
@@ -28,32 +35,39 @@ func dataSitePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 		return weft.BadRequest("longitude invalid")
 	}
 
+	status := v.Get("status")
+
+	if res := validStatus(status); !res.Ok {
+		return res
+	}
+
 	var result sql.Result
 
 	// TODO - use upsert with PG 9.5?
 
 	// return if insert succeeds
-	if result, err = db.Exec(`INSERT INTO data.site(siteID, latitude, longitude) VALUES($1, $2, $3)`,
-		siteID, latitude, longitude); err == nil {
+	if result, err = db.Exec(`INSERT INTO data.site(siteID, latitude, longitude, status)
+				VALUES($1, $2, $3, COALESCE(NULLIF($4, ''), 'active'))`,
+		siteID, latitude, longitude, status); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
 		}
 		if i == 1 {
-			return &weft.StatusOK
+			return dataSiteLocation.create(siteID, time.Now().UTC())
 		}
 	}
 
-	// return if update one row
+	// return if update one row.  The location is changed in the location history.
 	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
-		if result, err = db.Exec(`UPDATE data.site SET latitude=$2, longitude=$3 where siteID=$1`,
-			siteID, latitude, longitude); err == nil {
+		if result, err = db.Exec(`UPDATE data.site SET status=COALESCE(NULLIF($2, ''), status) where siteID=$1`,
+			siteID, status); err == nil {
 			var i int64
 			if i, err = result.RowsAffected(); err != nil {
 				return weft.InternalServerError(err)
 			}
 			if i == 1 {
-				return &weft.StatusOK
+				return dataSiteLocation.add(siteID, time.Now().UTC(), latitude, longitude)
 			}
 		}
 	}
@@ -65,6 +79,14 @@ func dataSitePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	return weft.InternalServerError(err)
 }
 
+func dataSiteAttributePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataSiteAttribute.put(r)
+}
+
+func dataSiteAttributeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataSiteAttribute.delete(r)
+}
+
 func dataSiteDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	if _, err := db.Exec(`DELETE FROM data.site where siteID = $1`, r.URL.Query().Get("siteID")); err != nil {
 		return weft.InternalServerError(err)
@@ -77,20 +99,43 @@ func dataSiteProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT siteID, latitude, longitude FROM data.site`); err != nil {
+	if rows, err = dbR.Query(`SELECT siteID, latitude, longitude, status FROM data.site`); err != nil {
 		return weft.InternalServerError(err)
 	}
 
 	var ts mtrpb.DataSiteResult
+	sites := make(map[string]*mtrpb.DataSite)
 
 	for rows.Next() {
 		var t mtrpb.DataSite
 
-		if err = rows.Scan(&t.SiteID, &t.Latitude, &t.Longitude); err != nil {
+		if err = rows.Scan(&t.SiteID, &t.Latitude, &t.Longitude, &t.Status); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		ts.Result = append(ts.Result, &t)
+		sites[t.SiteID] = &t
+	}
+	rows.Close()
+
+	if rows, err = dbR.Query(`SELECT siteID, key, value
+		FROM data.site_attribute JOIN data.site USING (sitePK)
+		ORDER BY siteID, key`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var siteID string
+		var a mtrpb.DataSiteAttribute
+
+		if err = rows.Scan(&siteID, &a.Key, &a.Value); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		if t, ok := sites[siteID]; ok {
+			t.Attribute = append(t.Attribute, &a)
+		}
 	}
 
 	var by []byte
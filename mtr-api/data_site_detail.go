@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"net/http"
+	"time"
+)
+
+// dataSiteDetail for the detail of one site.
+// needed for use with the fan out.
+type dataSiteDetail struct {
+	sitePK int
+	detail mtrpb.DataSiteDetail
+}
+
+/*
+dataSiteDetailProto returns the location, status, attributes, latest latencies with thresholds, completeness,
+tags, and recent alerts for the site in the query e.g., /data/site/detail?siteID=TAUP
+*/
+func dataSiteDetailProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	siteID := r.URL.Query().Get("siteID")
+
+	var a dataSiteDetail
+	var s = mtrpb.DataSite{SiteID: siteID}
+
+	switch err := dbR.QueryRow(`SELECT sitePK, latitude, longitude, status
+		FROM data.site
+		WHERE siteID = $1`, siteID).Scan(&a.sitePK, &s.Latitude, &s.Longitude, &s.Status); err {
+	case nil:
+	case sql.ErrNoRows:
+		return &weft.NotFound
+	default:
+		return weft.InternalServerError(err)
+	}
+
+	a.detail.Site = &s
+
+	// Load latency, completeness etc in parallel.
+	c1 := a.dataLatency()
+	c2 := a.dataCompleteness()
+	c3 := a.dataLatencyTag()
+	c4 := a.dataCompletenessTag()
+	c5 := a.alerts()
+	c6 := a.attributes()
+
+	resFinal := &weft.StatusOK
+
+	for res := range merge(c1, c2, c3, c4, c5, c6) {
+		if !res.Ok {
+			resFinal = res
+		}
+	}
+
+	if !resFinal.Ok {
+		return resFinal
+	}
+
+	by, err := proto.Marshal(&a.detail)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+// dataLatency loads the latest latencies.  Latencies without a threshold have lower and upper 0.
+func (a *dataSiteDetail) dataLatency() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, time, mean, fifty, ninety, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, `+dataLatencyInterval.late()+`
+			FROM data.latency_summary
+			JOIN data.type USING (typePK)
+			LEFT JOIN `+dataLatencyThreshold+` AS threshold USING (sitePK, typePK)
+			LEFT JOIN data.latency_interval USING (sitePK, typePK)
+			WHERE sitePK = $1
+			ORDER BY typeID ASC`, a.sitePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		var tm time.Time
+
+		for rows.Next() {
+			var dls = mtrpb.DataLatencySummary{SiteID: a.detail.Site.SiteID}
+
+			if err = rows.Scan(&dls.TypeID, &tm, &dls.FloatMean, &dls.FloatFifty, &dls.FloatNinety,
+				&dls.FloatLower, &dls.FloatUpper, &dls.FloatWarningLower, &dls.FloatWarningUpper, &dls.Scale, &dls.Late); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			dls.Seconds = tm.Unix()
+			dls.Unknown = dls.FloatLower == 0 && dls.FloatUpper == 0
+
+			roundDataLatencySummary(&dls)
+
+			a.detail.Latency = append(a.detail.Latency, &dls)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+// dataCompleteness loads the completeness for the last five minutes.
+func (a *dataSiteDetail) dataCompleteness() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, time, count, expected, `+dataCompletenessInterval.late()+`
+			FROM data.completeness_summary
+			JOIN data.completeness_type USING (typePK)
+			LEFT JOIN data.completeness_interval USING (sitePK, typePK)
+			WHERE sitePK = $1
+			ORDER BY typeID ASC`, a.sitePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		var tm time.Time
+
+		for rows.Next() {
+			var dcs = mtrpb.DataCompletenessSummary{SiteID: a.detail.Site.SiteID}
+			var count, expected int
+
+			if err = rows.Scan(&dcs.TypeID, &tm, &count, &expected, &dcs.Late); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			dcs.Seconds = tm.Unix()
+			dcs.Completeness = float32(count) / (float32(expected) / 288)
+
+			a.detail.Completeness = append(a.detail.Completeness, &dcs)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *dataSiteDetail) dataLatencyTag() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, tag
+			FROM data.latency_tag
+			JOIN mtr.tag USING (tagPK)
+			JOIN data.type USING (typePK)
+			WHERE sitePK = $1
+			ORDER BY tag ASC`, a.sitePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t = mtrpb.DataLatencyTag{SiteID: a.detail.Site.SiteID}
+
+			if err = rows.Scan(&t.TypeID, &t.Tag); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.LatencyTag = append(a.detail.LatencyTag, &t)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *dataSiteDetail) dataCompletenessTag() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, tag
+			FROM data.completeness_tag
+			JOIN mtr.tag USING (tagPK)
+			JOIN data.completeness_type USING (typePK)
+			WHERE sitePK = $1
+			ORDER BY tag ASC`, a.sitePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t = mtrpb.DataCompletenessTag{SiteID: a.detail.Site.SiteID}
+
+			if err = rows.Scan(&t.TypeID, &t.Tag); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.CompletenessTag = append(a.detail.CompletenessTag, &t)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *dataSiteDetail) alerts() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		var err error
+		if a.detail.Alert, err = recentAlerts("data", a.detail.Site.SiteID); err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *dataSiteDetail) attributes() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT key, value
+			FROM data.site_attribute
+			WHERE sitePK = $1
+			ORDER BY key ASC`, a.sitePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var at mtrpb.DataSiteAttribute
+
+			if err = rows.Scan(&at.Key, &at.Value); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.Site.Attribute = append(a.detail.Site.Attribute, &at)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
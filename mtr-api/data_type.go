@@ -7,13 +7,70 @@ import (
 	"github.com/GeoNet/weft"
 	"github.com/golang/protobuf/proto"
 	"net/http"
+	"strconv"
 )
 
+// dataTypePut adds or updates a data.type.
+func dataTypePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	typeID := v.Get("typeID")
+
+	if res := validTypeID(typeID); !res.Ok {
+		return res
+	}
+
+	scale, res := parseScale(v.Get("scale"))
+	if !res.Ok {
+		return res
+	}
+
+	if v.Get("display") == "" {
+		return weft.BadRequest("empty display")
+	}
+
+	unit := v.Get("unit")
+	if unit == "" {
+		unit = "n"
+	}
+
+	return dataLatencyType.put(typeID, []string{"description", "unit", "scale", "display"},
+		v.Get("description"), unit, scale, v.Get("display"))
+}
+
+// dataTypeDelete deletes a data.type.  A type with data is only deleted if force is true.
+func dataTypeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataLatencyType.delete(r)
+}
+
+// dataCompletenessTypePut adds or updates a data.completeness_type.
+func dataCompletenessTypePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	typeID := v.Get("typeID")
+
+	if res := validTypeID(typeID); !res.Ok {
+		return res
+	}
+
+	expected, err := strconv.Atoi(v.Get("expected"))
+	if err != nil || expected <= 0 {
+		return weft.BadRequest("invalid expected")
+	}
+
+	return dataCompletenessType.put(typeID, []string{"expected"}, expected)
+}
+
+// dataCompletenessTypeDelete deletes a data.completeness_type.  A type with data is only deleted if force is true.
+func dataCompletenessTypeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataCompletenessType.delete(r)
+}
+
 func dataTypeProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT typeID, display FROM data.type ORDER BY typeID ASC`); err != nil {
+	if rows, err = dbR.Query(`SELECT typeID, display, description, unit, scale FROM data.type ORDER BY typeID ASC`); err != nil {
 		return weft.InternalServerError(err)
 	}
 	defer rows.Close()
@@ -23,7 +80,7 @@ func dataTypeProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 	for rows.Next() {
 		var ft mtrpb.DataType
 
-		if err = rows.Scan(&ft.TypeID, &ft.Display); err != nil {
+		if err = rows.Scan(&ft.TypeID, &ft.Display, &ft.Description, &ft.Unit, &ft.Scale); err != nil {
 			return weft.InternalServerError(err)
 		}
 
@@ -44,7 +101,7 @@ func dataCompletenessTypeProto(r *http.Request, h http.Header, b *bytes.Buffer)
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT typeID FROM data.completeness_type ORDER BY typeID ASC`); err != nil {
+	if rows, err = dbR.Query(`SELECT typeID, expected FROM data.completeness_type ORDER BY typeID ASC`); err != nil {
 		return weft.InternalServerError(err)
 	}
 	defer rows.Close()
@@ -54,7 +111,7 @@ func dataCompletenessTypeProto(r *http.Request, h http.Header, b *bytes.Buffer)
 	for rows.Next() {
 		var ft mtrpb.DataType
 
-		if err = rows.Scan(&ft.TypeID); err != nil {
+		if err = rows.Scan(&ft.TypeID, &ft.Expected); err != nil {
 			return weft.InternalServerError(err)
 		}
 
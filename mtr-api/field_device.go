@@ -10,8 +10,15 @@ import (
 	"github.com/lib/pq"
 	"net/http"
 	"strconv"
+	"time"
 )
 
+/*
+fieldDevicePut adds or updates the device.  status is optional.  A new device is active unless
+a status is given and the status of an existing device is only changed if a status is given.
+A changed location is added to the location history from now.  Use /field/device/location to
+record a move at another time and /field/device/rename to change the deviceID.
+*/
 func fieldDevicePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	v := r.URL.Query()
 
@@ -26,37 +33,43 @@ func fieldDevicePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 		return weft.BadRequest("longitude invalid")
 	}
 
+	status := v.Get("status")
+
+	if res := validStatus(status); !res.Ok {
+		return res
+	}
+
 	var result sql.Result
 
 	// TODO - use upsert with PG 9.5?
 
 	// return if insert succeeds
-	if result, err = db.Exec(`INSERT INTO field.device(deviceID, modelPK, latitude, longitude)
-				SELECT $1, modelPK, $3, $4
+	if result, err = db.Exec(`INSERT INTO field.device(deviceID, modelPK, latitude, longitude, status)
+				SELECT $1, modelPK, $3, $4, COALESCE(NULLIF($5, ''), 'active')
 				FROM field.model
 				WHERE modelID = $2`,
-		v.Get("deviceID"), v.Get("modelID"), latitude, longitude); err == nil {
+		v.Get("deviceID"), v.Get("modelID"), latitude, longitude, status); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
 		}
 		if i == 1 {
-			return &weft.StatusOK
+			return fieldDeviceLocation.create(v.Get("deviceID"), time.Now().UTC())
 		}
 	}
 
-	// return if update one row
+	// return if update one row.  The location is changed in the location history.
 	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
 		if result, err = db.Exec(`UPDATE field.device
-					SET latitude = $2, longitude = $3
+					SET status = COALESCE(NULLIF($2, ''), status)
 					WHERE deviceID = $1`,
-			v.Get("deviceID"), latitude, longitude); err == nil {
+			v.Get("deviceID"), status); err == nil {
 			var i int64
 			if i, err = result.RowsAffected(); err != nil {
 				return weft.InternalServerError(err)
 			}
 			if i == 1 {
-				return &weft.StatusOK
+				return fieldDeviceLocation.add(v.Get("deviceID"), time.Now().UTC(), latitude, longitude)
 			}
 		}
 	}
@@ -68,6 +81,14 @@ func fieldDevicePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 	return weft.InternalServerError(err)
 }
 
+func fieldDeviceAttributePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldDeviceAttribute.put(r)
+}
+
+func fieldDeviceAttributeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldDeviceAttribute.delete(r)
+}
+
 func fieldDeviceDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	if _, err := db.Exec(`DELETE FROM field.device where deviceID = $1`, r.URL.Query().Get("deviceID")); err != nil {
 		return weft.InternalServerError(err)
@@ -80,22 +101,45 @@ func fieldDeviceProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT deviceid, modelid, latitude, longitude
+	if rows, err = dbR.Query(`SELECT deviceid, modelid, latitude, longitude, status
 		FROM
 		field.device JOIN field.model USING(modelpk)`); err != nil {
 		return weft.InternalServerError(err)
 	}
 
 	var fdr mtrpb.FieldDeviceResult
+	devices := make(map[string]*mtrpb.FieldDevice)
 
 	for rows.Next() {
 		var d mtrpb.FieldDevice
 
-		if err = rows.Scan(&d.DeviceID, &d.ModelID, &d.Latitude, &d.Longitude); err != nil {
+		if err = rows.Scan(&d.DeviceID, &d.ModelID, &d.Latitude, &d.Longitude, &d.Status); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		fdr.Result = append(fdr.Result, &d)
+		devices[d.DeviceID] = &d
+	}
+	rows.Close()
+
+	if rows, err = dbR.Query(`SELECT deviceID, key, value
+		FROM field.device_attribute JOIN field.device USING (devicePK)
+		ORDER BY deviceID, key`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deviceID string
+		var a mtrpb.FieldDeviceAttribute
+
+		if err = rows.Scan(&deviceID, &a.Key, &a.Value); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		if d, ok := devices[deviceID]; ok {
+			d.Attribute = append(d.Attribute, &a)
+		}
 	}
 
 	var by []byte
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"net/http"
+	"time"
+)
+
+// fieldDeviceDetail for the detail of one device.
+// needed for use with the fan out.
+type fieldDeviceDetail struct {
+	devicePK int
+	detail   mtrpb.FieldDeviceDetail
+}
+
+/*
+fieldDeviceDetailProto returns the model, location, status, attributes, latest metrics with thresholds,
+states, tags, and recent alerts for the device in the query e.g., /field/device/detail?deviceID=idu-birchfarm
+*/
+func fieldDeviceDetailProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	deviceID := r.URL.Query().Get("deviceID")
+
+	var a fieldDeviceDetail
+	var d = mtrpb.FieldDevice{DeviceID: deviceID}
+
+	switch err := dbR.QueryRow(`SELECT devicePK, modelID, latitude, longitude, status
+		FROM field.device JOIN field.model USING (modelPK)
+		WHERE deviceID = $1`, deviceID).Scan(&a.devicePK, &d.ModelID, &d.Latitude, &d.Longitude, &d.Status); err {
+	case nil:
+	case sql.ErrNoRows:
+		return &weft.NotFound
+	default:
+		return weft.InternalServerError(err)
+	}
+
+	a.detail.Device = &d
+
+	// Load metrics, states etc in parallel.
+	c1 := a.fieldMetric()
+	c2 := a.fieldState()
+	c3 := a.fieldMetricTag()
+	c4 := a.fieldStateTag()
+	c5 := a.alerts()
+	c6 := a.attributes()
+
+	resFinal := &weft.StatusOK
+
+	for res := range merge(c1, c2, c3, c4, c5, c6) {
+		if !res.Ok {
+			resFinal = res
+		}
+	}
+
+	if !resFinal.Ok {
+		return resFinal
+	}
+
+	by, err := proto.Marshal(&a.detail)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+// fieldMetric loads the latest metrics.  Metrics without a threshold have lower and upper 0.
+func (a *fieldDeviceDetail) fieldMetric() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, time, value, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, `+fieldMetricInterval.late()+`
+			FROM field.metric_summary
+			JOIN field.type USING (typePK)
+			LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
+			LEFT JOIN field.metric_interval USING (devicePK, typePK)
+			WHERE devicePK = $1
+			ORDER BY typeID ASC`, a.devicePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		var tm time.Time
+
+		for rows.Next() {
+			var fmr = mtrpb.FieldMetricSummary{DeviceID: a.detail.Device.DeviceID, ModelID: a.detail.Device.ModelID}
+
+			if err = rows.Scan(&fmr.TypeID, &tm, &fmr.FloatValue, &fmr.FloatLower, &fmr.FloatUpper,
+				&fmr.FloatWarningLower, &fmr.FloatWarningUpper, &fmr.Scale, &fmr.Late); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			fmr.Seconds = tm.Unix()
+			fmr.Unknown = fmr.FloatLower == 0 && fmr.FloatUpper == 0
+
+			roundFieldMetricSummary(&fmr)
+
+			a.detail.Metric = append(a.detail.Metric, &fmr)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *fieldDeviceDetail) fieldState() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, time, value
+			FROM field.state
+			JOIN field.state_type USING (typePK)
+			WHERE devicePK = $1
+			ORDER BY typeID ASC`, a.devicePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		var tm time.Time
+
+		for rows.Next() {
+			var fs = mtrpb.FieldState{DeviceID: a.detail.Device.DeviceID}
+
+			if err = rows.Scan(&fs.TypeID, &tm, &fs.Value); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			fs.Seconds = tm.Unix()
+
+			a.detail.State = append(a.detail.State, &fs)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *fieldDeviceDetail) fieldMetricTag() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, tag
+			FROM field.metric_tag
+			JOIN mtr.tag USING (tagPK)
+			JOIN field.type USING (typePK)
+			WHERE devicePK = $1
+			ORDER BY tag ASC`, a.devicePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t = mtrpb.FieldMetricTag{DeviceID: a.detail.Device.DeviceID}
+
+			if err = rows.Scan(&t.TypeID, &t.Tag); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.MetricTag = append(a.detail.MetricTag, &t)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *fieldDeviceDetail) fieldStateTag() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT typeID, tag
+			FROM field.state_tag
+			JOIN mtr.tag USING (tagPK)
+			JOIN field.state_type USING (typePK)
+			WHERE devicePK = $1
+			ORDER BY tag ASC`, a.devicePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t = mtrpb.FieldStateTag{DeviceID: a.detail.Device.DeviceID}
+
+			if err = rows.Scan(&t.TypeID, &t.Tag); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.StateTag = append(a.detail.StateTag, &t)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *fieldDeviceDetail) alerts() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		var err error
+		if a.detail.Alert, err = recentAlerts("field", a.detail.Device.DeviceID); err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
+
+func (a *fieldDeviceDetail) attributes() <-chan *weft.Result {
+	out := make(chan *weft.Result)
+	go func() {
+		defer close(out)
+
+		rows, err := dbR.Query(`SELECT key, value
+			FROM field.device_attribute
+			WHERE devicePK = $1
+			ORDER BY key ASC`, a.devicePK)
+		if err != nil {
+			out <- weft.InternalServerError(err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var at mtrpb.FieldDeviceAttribute
+
+			if err = rows.Scan(&at.Key, &at.Value); err != nil {
+				out <- weft.InternalServerError(err)
+				return
+			}
+
+			a.detail.Device.Attribute = append(a.detail.Device.Attribute, &at)
+		}
+
+		out <- &weft.StatusOK
+	}()
+	return out
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/lib/pq"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -27,10 +26,10 @@ func fieldMetricPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 	v := r.URL.Query()
 
 	var err error
-	var val int
+	var val float64
 	var t time.Time
 
-	if val, err = strconv.Atoi(v.Get("value")); err != nil {
+	if val, err = parseValue(v.Get("value")); err != nil {
 		return weft.BadRequest("invalid value")
 	}
 
@@ -48,7 +47,7 @@ func fieldMetricPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				FROM field.device, field.type
 				WHERE deviceID = $1
 				AND typeID = $2`,
-		deviceID, typeID, t.Truncate(time.Minute).Unix(), t, int32(val)); err != nil {
+		deviceID, typeID, t.Truncate(time.Minute).Unix(), t, val); err != nil {
 		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
 			return &statusTooManyRequests
 		} else {
@@ -69,7 +68,7 @@ func fieldMetricPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				WHERE time < $3
 				AND devicePK = (SELECT devicePK FROM field.device WHERE deviceID = $1)
 				AND typePK = (SELECT typePK FROM field.type WHERE typeID = $2)`,
-		deviceID, typeID, t, int32(val)); err != nil {
+		deviceID, typeID, t, val); err != nil {
 		return weft.InternalServerError(err)
 	}
 
@@ -83,7 +82,7 @@ func fieldMetricPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 				FROM field.device, field.type
 				WHERE deviceID = $1
 				AND typeID = $2`,
-			deviceID, typeID, t, int32(val)); err != nil {
+			deviceID, typeID, t, val); err != nil {
 			if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
 				// incoming value was old
 			} else {
@@ -240,7 +239,7 @@ func fieldMetricCsv(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 		outStrings := []string{t.Format(DYGRAPH_TIME_FORMAT)}
 		for _, hdr := range typeIDs {
 			if val, ok := values[t][hdr]; ok == true {
-				outStrings = append(outStrings, fmt.Sprintf("%.2f", val))
+				outStrings = append(outStrings, formatValue(val))
 			} else {
 				outStrings = append(outStrings, "")
 			}
@@ -294,9 +293,9 @@ func fieldMetricProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 		return weft.InternalServerError(err)
 	}
 
-	if err := dbR.QueryRow(`SELECT lower,upper FROM field.threshold
+	if err := dbR.QueryRow(`SELECT lower,upper FROM `+fieldThreshold+` AS threshold
 		WHERE devicePK = $1 AND typePK = $2`,
-		devicePK, typePK).Scan(&fmr.Lower, &fmr.Upper); err != nil && err != sql.ErrNoRows {
+		devicePK, typePK).Scan(&fmr.FloatLower, &fmr.FloatUpper); err != nil && err != sql.ErrNoRows {
 		return weft.InternalServerError(err)
 	}
 
@@ -315,16 +314,20 @@ func fieldMetricProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 	for rows.Next() {
 		var fm mtrpb.FieldMetric
 		var t time.Time
-		if err = rows.Scan(&t, &fm.Value); err != nil {
+		if err = rows.Scan(&t, &fm.FloatValue); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		fm.Seconds = t.Unix()
+		roundFieldMetric(&fm)
+
 		fmr.Result = append(fmr.Result, &fm)
 	}
 
 	var by []byte
 
+	roundFieldMetricResult(&fmr)
+
 	if by, err = proto.Marshal(&fmr); err != nil {
 		return weft.InternalServerError(err)
 	}
@@ -367,16 +370,16 @@ func (f fieldMetric) plot(deviceID, typeID, resolution string, plotter ts.SVGPlo
 
 	var rows *sql.Rows
 	var err error
-	var lower, upper int
+	var lower, upper, warningLower, warningUpper float64
 
-	if err := dbR.QueryRow(`SELECT lower,upper FROM field.threshold
+	if err := dbR.QueryRow(`SELECT lower,upper,warning_lower,warning_upper FROM `+fieldThreshold+` AS threshold
 		WHERE devicePK = $1 AND typePK = $2`,
-		devicePK, typePK).Scan(&lower, &upper); err != nil && err != sql.ErrNoRows {
+		devicePK, typePK).Scan(&lower, &upper, &warningLower, &warningUpper); err != nil && err != sql.ErrNoRows {
 		return weft.InternalServerError(err)
 	}
 
 	if !(lower == 0 && upper == 0) {
-		p.SetThreshold(float64(lower)*scale, float64(upper)*scale)
+		p.SetThreshold(lower*scale, upper*scale, warningLower*scale, warningUpper*scale)
 	}
 
 	var tags []string
@@ -422,6 +425,12 @@ func (f fieldMetric) plot(deviceID, typeID, resolution string, plotter ts.SVGPlo
 	case "hour":
 		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*28), time.Now().UTC())
 		p.SetXLabel("4 weeks")
+	case "day":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365), time.Now().UTC())
+		p.SetXLabel("1 year")
+	case "week":
+		p.SetXAxis(time.Now().UTC().Add(time.Hour*-24*365*5), time.Now().UTC())
+		p.SetXLabel("5 years")
 	default:
 		return weft.BadRequest("invalid resolution")
 	}
@@ -537,13 +546,32 @@ func queryMetricRows(devicePK, typePK int, resolution string, timeRange []time.T
 		GROUP BY date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min'
 		ORDER BY t ASC`,
 			devicePK, typePK, timeRange[0], timeRange[1])
-	case "hour":
-		rows, err = dbR.Query(`SELECT date_trunc('`+resolution+`',time) as t, avg(value) FROM field.metric WHERE
+	case "hour", "day":
+		// read the rollup and metrics that haven't been rolled up yet.
+		rows, err = dbR.Query(`SELECT time as t, avg FROM field.metric_`+resolution+` WHERE
+		devicePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		UNION ALL
+		SELECT date_trunc('`+resolution+`',time) as t, avg(value) FROM field.metric WHERE
 		devicePK = $1 AND typePK = $2
 		AND time >= $3 AND time <= $4
+		AND time >= `+rolledUp("field.metric", resolution)+`
 		GROUP BY date_trunc('`+resolution+`',time)
 		ORDER BY t ASC`,
 			devicePK, typePK, timeRange[0], timeRange[1])
+	case "week":
+		rows, err = dbR.Query(`SELECT date_trunc('week', t) as w, sum(a * n) / sum(n) FROM (
+		SELECT time as t, avg as a, count as n FROM field.metric_day WHERE
+		devicePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		UNION ALL
+		SELECT time, value, 1 FROM field.metric WHERE
+		devicePK = $1 AND typePK = $2
+		AND time >= $3 AND time <= $4
+		AND time >= `+rolledUp("field.metric", "day")+`) r
+		GROUP BY date_trunc('week', t)
+		ORDER BY w ASC`,
+			devicePK, typePK, timeRange[0], timeRange[1])
 	case "full":
 		rows, err = dbR.Query(`SELECT time, value
 		FROM field.metric
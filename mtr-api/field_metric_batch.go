@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+var unknownDeviceType = weft.Result{Ok: false, Code: http.StatusBadRequest, Msg: "unknown deviceID or typeID"}
+
+// fieldMetricBatch - batch uploads to table field.metric
+type fieldMetricBatch struct {
+	device, typ pkCache
+}
+
+func fieldmetricbatchHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+			return res
+		}
+
+		switch batchContentType(r) {
+		case "application/x-protobuf":
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldMetricBatchProto(r, h, b)
+		case "text/csv":
+			h.Set("Content-Type", "text/csv")
+			return fieldMetricBatchCsv(r, h, b)
+		default:
+			return weft.BadRequest("Content-Type must be application/x-protobuf or text/csv")
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+// fieldMetricBatchProto saves the field metrics in an mtrpb.FieldMetricBatch from the request body.
+func fieldMetricBatchProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var by []byte
+	var err error
+
+	if by, err = ioutil.ReadAll(r.Body); err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	var f mtrpb.FieldMetricBatch
+
+	if err = proto.Unmarshal(by, &f); err != nil {
+		return weft.BadRequest("invalid protobuf: " + err.Error())
+	}
+
+	res := newBatchResult(len(f.Row))
+
+	if s := (&fieldMetricBatch{}).save(f.Row, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "application/x-protobuf", b)
+}
+
+// fieldMetricBatchCsv saves the field metrics in CSV from the request body.
+// Each line is deviceID,typeID,time,value with time in RFC3339 format
+// e.g., gps-taupoairport,voltage,2015-05-14T21:40:30Z,14100
+func fieldMetricBatchCsv(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	c := csv.NewReader(r.Body)
+	c.FieldsPerRecord = -1
+
+	var rows []*mtrpb.FieldMetricBatchRow
+	var bad = make(map[int]*weft.Result)
+
+	for {
+		rec, err := c.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return weft.BadRequest("invalid csv: " + err.Error())
+		}
+
+		if len(rec) != 4 {
+			bad[len(rows)] = weft.BadRequest("expected deviceID,typeID,time,value")
+			rows = append(rows, nil)
+			continue
+		}
+
+		var t time.Time
+		var val float64
+
+		if t, err = time.Parse(time.RFC3339, rec[2]); err != nil {
+			bad[len(rows)] = weft.BadRequest("invalid time")
+			rows = append(rows, nil)
+			continue
+		}
+
+		if val, err = parseValue(rec[3]); err != nil {
+			bad[len(rows)] = weft.BadRequest("invalid value")
+			rows = append(rows, nil)
+			continue
+		}
+
+		rows = append(rows, &mtrpb.FieldMetricBatchRow{DeviceID: rec[0], TypeID: rec[1], Seconds: t.Unix(), FloatValue: val})
+	}
+
+	res := newBatchResult(len(rows))
+
+	for i, v := range bad {
+		setRow(&res, i, v)
+	}
+
+	if s := (&fieldMetricBatch{}).save(rows, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "text/csv", b)
+}
+
+/*
+save inserts rows into field.metric in a single transaction and updates field.metric_summary
+with the newest value for each device and type in the batch.  nil rows are skipped.
+The outcome for each row is set in res:
+
+	http.StatusOK - saved.
+	http.StatusTooManyRequests - there is already data for the minute (see statusTooManyRequests).
+	http.StatusBadRequest - the deviceID or typeID does not exist.
+*/
+func (f *fieldMetricBatch) save(rows []*mtrpb.FieldMetricBatchRow, res *mtrpb.BatchResult) *weft.Result {
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	if s := f.saveTxn(txn, rows, res); !s.Ok {
+		txn.Rollback()
+		return s
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// saveTxn is save in txn.  If the result is not ok txn must be rolled back.
+func (f *fieldMetricBatch) saveTxn(txn *sql.Tx, rows []*mtrpb.FieldMetricBatchRow, res *mtrpb.BatchResult) *weft.Result {
+	f.device = newPKCache(`SELECT devicePK FROM field.device WHERE deviceID = $1`)
+	f.typ = newPKCache(`SELECT typePK FROM field.type WHERE typeID = $1`)
+
+	var err error
+
+	keys := make([]batchKey, len(rows))
+	seen := make(map[batchKey]bool)
+
+	var pending []int
+	var args [][]interface{}
+
+	for i, v := range rows {
+		if v == nil {
+			continue
+		}
+
+		var ok bool
+		var k batchKey
+
+		if k.pk, ok, err = f.device.get(txn, v.DeviceID); err == nil && ok {
+			k.typePK, ok, err = f.typ.get(txn, v.TypeID)
+		}
+		if err != nil {
+			return weft.InternalServerError(err)
+		}
+		if !ok {
+			setRow(res, i, &unknownDeviceType)
+			continue
+		}
+
+		t := time.Unix(v.Seconds, 0).UTC()
+		k.rateLimit = t.Truncate(time.Minute).Unix()
+
+		if seen[k] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+		seen[k] = true
+
+		keys[i] = k
+		pending = append(pending, i)
+		args = append(args, []interface{}{k.pk, k.typePK, k.rateLimit, t, fieldMetricValue(v)})
+	}
+
+	var inserted map[batchKey]bool
+
+	if inserted, err = insertBatch(txn, "field.metric",
+		[]string{"devicePK", "typePK", "rate_limit", "time", "value"},
+		[]string{"SMALLINT", "SMALLINT", "BIGINT", "TIMESTAMPTZ", "DOUBLE PRECISION"}, args); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// a concurrent upload for the same minute.
+			return &statusTooManyRequests
+		}
+		return weft.InternalServerError(err)
+	}
+
+	// the newest row in the batch for each summary.
+	latest := make(map[summaryKey]int)
+
+	for _, i := range pending {
+		if !inserted[keys[i]] {
+			setRow(res, i, &statusTooManyRequests)
+			continue
+		}
+
+		k := summaryKey{pk: keys[i].pk, typePK: keys[i].typePK}
+		if l, ok := latest[k]; !ok || rows[i].Seconds > rows[l].Seconds {
+			latest[k] = i
+		}
+	}
+
+	for k, i := range latest {
+		if err = updateSummary(txn, "field.metric_summary", "devicePK", k, time.Unix(rows[i].Seconds, 0).UTC(),
+			[]string{"value"}, []interface{}{fieldMetricValue(rows[i])}); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	return &weft.StatusOK
+}
+
+// fieldMetricValue returns the value for r.  FloatValue is used if it is set.
+func fieldMetricValue(r *mtrpb.FieldMetricBatchRow) float64 {
+	if r.FloatValue != 0.0 {
+		return r.FloatValue
+	}
+
+	return float64(r.Value)
+}
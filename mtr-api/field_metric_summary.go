@@ -29,20 +29,24 @@ func fieldLatestProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 
 	switch typeID {
 	case "":
-		rows, err = dbR.Query(`select deviceID, modelID, typeid, time, value, lower, upper, scale
+		rows, err = dbR.Query(`select deviceID, modelID, typeid, time, value, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, ` + fieldMetricInterval.late() + `
 		FROM field.metric_summary
 		JOIN field.device using (devicePK)
 		JOIN field.model using (modelPK)
-		JOIN field.threshold using (devicePK, typePK)
-		JOIN field.type using (typePK)`)
+		LEFT JOIN ` + fieldThreshold + ` AS threshold USING (devicePK, typePK)
+		JOIN field.type using (typePK)
+		LEFT JOIN field.metric_interval using (devicePK, typePK)
+		WHERE status != 'retired'`)
 	default:
-		rows, err = dbR.Query(`select deviceID, modelID, typeid, time, value, lower, upper, scale
+		rows, err = dbR.Query(`select deviceID, modelID, typeid, time, value, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), scale, `+fieldMetricInterval.late()+`
 		FROM field.metric_summary
 		JOIN field.device using (devicePK)
 		JOIN field.model using (modelPK)
-		JOIN field.threshold using (devicePK, typePK)
+		LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
 		JOIN field.type using (typePK)
-		WHERE typeID = $1;`, typeID)
+		LEFT JOIN field.metric_interval using (devicePK, typePK)
+		WHERE typeID = $1
+		AND status != 'retired';`, typeID)
 	}
 	if err != nil {
 		return weft.InternalServerError(err)
@@ -56,12 +60,15 @@ func fieldLatestProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 	for rows.Next() {
 		var fmr mtrpb.FieldMetricSummary
 
-		if err = rows.Scan(&fmr.DeviceID, &fmr.ModelID, &fmr.TypeID, &t, &fmr.Value,
-			&fmr.Lower, &fmr.Upper, &fmr.Scale); err != nil {
+		if err = rows.Scan(&fmr.DeviceID, &fmr.ModelID, &fmr.TypeID, &t, &fmr.FloatValue,
+			&fmr.FloatLower, &fmr.FloatUpper, &fmr.FloatWarningLower, &fmr.FloatWarningUpper, &fmr.Scale, &fmr.Late); err != nil {
 			return weft.InternalServerError(err)
 		}
 
 		fmr.Seconds = t.Unix()
+		fmr.Unknown = fmr.FloatLower == 0 && fmr.FloatUpper == 0
+
+		roundFieldMetricSummary(&fmr)
 
 		fmlr.Result = append(fmlr.Result, &fmr)
 	}
@@ -104,16 +111,24 @@ func fieldLatestSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 		return weft.InternalServerError(err)
 	}
 
+	at, res := parseTime(r.URL.Query())
+	if !res.Ok {
+		return res
+	}
+
 	// TODO: handle maps that cross 180 (ST_Within)
-	if rows, err = dbR.Query(`WITH p as (SELECT geom, time, value, lower, upper,
-			ST_Transform(geom::geometry, 3857) as pt
+	if rows, err = dbR.Query(`WITH p as (SELECT location.geom, time, value, COALESCE(lower, 0) AS lower, COALESCE(upper, 0) AS upper,
+			COALESCE(warning_lower, 0) AS warning_lower, COALESCE(warning_upper, 0) AS warning_upper,
+			ST_Transform(location.geom::geometry, 3857) as pt
 			FROM field.metric_summary
 			JOIN field.device using (devicePK)
-			JOIN field.threshold using (devicePK, typePK)
+			JOIN `+fieldDeviceLocation.at("$3")+` AS location USING (devicePK)
+			LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
 			JOIN field.type using (typePK)
-			WHERE typeID = $1)
-			SELECT ST_X(pt), ST_Y(pt)*-1, ST_X(geom::geometry), ST_Y(geom::geometry), time, value, lower, upper FROM p
-			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt); err != nil {
+			WHERE typeID = $1
+			AND status != 'retired')
+			SELECT ST_X(pt), ST_Y(pt)*-1, ST_X(geom::geometry), ST_Y(geom::geometry), time, value, lower, upper, warning_lower, warning_upper FROM p
+			WHERE ST_Within(geom::geometry, ST_GeomFromText($2, 4326))`, typeID, bboxWkt, at); err != nil {
 		return weft.InternalServerError(err)
 	}
 	defer rows.Close()
@@ -122,15 +137,18 @@ func fieldLatestSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 
 	var late []point
 	var good []point
+	var warning []point
 	var bad []point
 	var dunno []point
 
 	for rows.Next() {
 		var p point
 		var t time.Time
-		var min, max, v int
+		var th threshold
+		var v float64
 
-		if err = rows.Scan(&p.x, &p.y, &p.longitude, &p.latitude, &t, &v, &min, &max); err != nil {
+		if err = rows.Scan(&p.x, &p.y, &p.longitude, &p.latitude, &t, &v,
+			&th.lower, &th.upper, &th.warningLower, &th.warningUpper); err != nil {
 			return weft.InternalServerError(err)
 		}
 
@@ -150,10 +168,12 @@ func fieldLatestSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 		switch {
 		case t.Before(ago):
 			late = append(late, p)
-		case min == 0 && max == 0:
+		case th.lower == 0 && th.upper == 0:
 			dunno = append(dunno, p)
-		case v < min || v > max:
+		case th.critical(v):
 			bad = append(bad, p)
+		case th.warning(v):
+			warning = append(warning, p)
 		default:
 			good = append(good, p)
 		}
@@ -179,6 +199,12 @@ func fieldLatestSvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 	}
 	b.WriteString("</g>")
 
+	b.WriteString("<g style=\"stroke: #ff7f00; fill: #ff7f00; \">") // amber
+	for _, p := range warning {
+		b.WriteString(fmt.Sprintf("<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%d\"/>", p.x, p.y, 5))
+	}
+	b.WriteString("</g>")
+
 	b.WriteString("<g style=\"stroke: #e41a1c; fill: #e41a1c; \">") //red
 	for _, p := range bad {
 		b.WriteString(fmt.Sprintf("<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%d\"/>", p.x, p.y, 6))
@@ -202,6 +228,11 @@ func fieldLatestGeoJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 
 	typeID := r.URL.Query().Get("typeID")
 
+	at, res := parseTime(r.URL.Query())
+	if !res.Ok {
+		return res
+	}
+
 	var d string
 	err = db.QueryRow("select typeID FROM field.type where typeID = $1", typeID).Scan(&d)
 
@@ -213,12 +244,15 @@ func fieldLatestGeoJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 	}
 
 	if rows, err = dbR.Query(`
-		WITH p as (SELECT geom, time, value, lower, upper, deviceid, typeid
+		WITH p as (SELECT location.geom, time, value, COALESCE(lower, 0) AS lower, COALESCE(upper, 0) AS upper,
+		COALESCE(warning_lower, 0) AS warning_lower, COALESCE(warning_upper, 0) AS warning_upper, deviceid, typeid
 		FROM field.metric_summary
 		JOIN field.device using (devicePK)
-		JOIN field.threshold using (devicePK, typePK)
+		JOIN `+fieldDeviceLocation.at("$2")+` AS location USING (devicePK)
+		LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
 		JOIN field.type using (typePK)
-		WHERE typeID = $1)
+		WHERE typeID = $1
+		AND status != 'retired')
 		SELECT row_to_json(fc)
 		FROM ( SELECT 'FeatureCollection' as type, COALESCE(array_to_json(array_agg(f)), '[]') as features
 		from (SELECT 'Feature' as type,
@@ -231,12 +265,15 @@ func fieldLatestGeoJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 						value,
 						lower,
 						upper,
+						warning_lower,
+						warning_upper,
+						lower = 0 AND upper = 0 AS unknown,
 						deviceid,
 						typeid
 						) as l
 					)
 				) as properties FROM p
-		) as f ) as fc`, typeID); err != nil {
+		) as f ) as fc`, typeID, at); err != nil {
 		return weft.InternalServerError(err)
 	}
 	defer rows.Close()
@@ -41,7 +41,7 @@ func TestFieldMetricCsv(t *testing.T) {
 	scale := 0.001
 	expectedVals := [][]string{
 		{""}, // header line, ignored in test.  Should be time, value
-		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", testData[0].value*scale)},
+		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), formatValue(testData[0].value * scale)},
 	}
 
 	for _, td := range testData {
@@ -76,7 +76,7 @@ func TestFieldMetricCsv(t *testing.T) {
 
 	expectedSubset := [][]string{
 		{""}, // header line, ignored in test.  Should be time, value
-		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", testData[0].value*scale)},
+		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), formatValue(testData[0].value * scale)},
 	}
 	compareCsvData(b, expectedSubset, t)
 
@@ -89,7 +89,7 @@ func TestFieldMetricCsv(t *testing.T) {
 
 	expectedOutput := [][]string{
 		{""}, // header line, ignored in test.  Should be time, voltage, voltage
-		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), fmt.Sprintf("%.2f", testData[0].value*scale), fmt.Sprintf("%.2f", testData[0].value*scale)},
+		{testData[0].time.Format(DYGRAPH_TIME_FORMAT), formatValue(testData[0].value * scale), formatValue(testData[0].value * scale)},
 	}
 	compareCsvData(b, expectedOutput, t)
 
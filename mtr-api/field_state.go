@@ -27,6 +27,10 @@ func fieldStatePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result
 		return weft.BadRequest("invalid time")
 	}
 
+	if err = fieldStateHistoryAdd(deviceID, typeID, t, value); err != nil {
+		return weft.InternalServerError(err)
+	}
+
 	var result sql.Result
 	if result, err = db.Exec(`UPDATE field.state SET
 				time = $3, value = $4
@@ -83,7 +87,8 @@ func fieldStateProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resu
 	if rows, err = dbR.Query(`SELECT deviceID, typeID, time, value
 				FROM field.state
 				JOIN field.device USING (devicePK)
-				JOIN field.state_type USING (typePK)`); err != nil {
+				JOIN field.state_type USING (typePK)
+				WHERE status != 'retired'`); err != nil {
 		return weft.InternalServerError(err)
 	}
 
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/mtr/ts"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"net/http"
+	"time"
+)
+
+/*
+fieldStateHistoryAdd adds a row to field.state_history if value is a change from the state
+before t.  States can arrive out of order so the change is from the history not field.state.
+*/
+func fieldStateHistoryAdd(deviceID, typeID string, t time.Time, value bool) error {
+	_, err := db.Exec(`INSERT INTO field.state_history(devicePK, typePK, time, value)
+				SELECT devicePK, typePK, $3, $4
+				FROM field.device, field.state_type
+				WHERE deviceID = $1
+				AND typeID = $2
+				AND $4 IS DISTINCT FROM (SELECT value FROM field.state_history h
+					WHERE h.devicePK = field.device.devicePK
+					AND h.typePK = field.state_type.typePK
+					AND h.time <= $3
+					ORDER BY time DESC
+					LIMIT 1)`,
+		deviceID, typeID, t, value)
+
+	// a state has already been recorded at t, update it.
+	if e, ok := err.(*pq.Error); ok && e.Code == errorUniqueViolation {
+		_, err = db.Exec(`UPDATE field.state_history SET value = $4
+				FROM field.device, field.state_type
+				WHERE field.state_history.devicePK = field.device.devicePK
+				AND field.state_history.typePK = field.state_type.typePK
+				AND deviceID = $1
+				AND typeID = $2
+				AND time = $3`,
+			deviceID, typeID, t, value)
+	}
+
+	return err
+}
+
+/*
+fieldStateHistory returns the changes of state for deviceID and typeID in timeRange.  The first
+change is the state at the start of timeRange and may be before it.
+*/
+func fieldStateHistory(deviceID, typeID string, timeRange []time.Time) (mtrpb.FieldStateResult, *weft.Result) {
+	var fr mtrpb.FieldStateResult
+	var devicePK, typePK int
+
+	err := dbR.QueryRow(`SELECT devicePK, typePK FROM field.device, field.state_type
+				WHERE deviceID = $1
+				AND typeID = $2`, deviceID, typeID).Scan(&devicePK, &typePK)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return fr, &weft.NotFound
+	default:
+		return fr, weft.InternalServerError(err)
+	}
+
+	rows, err := dbR.Query(`(SELECT time, value FROM field.state_history
+				WHERE devicePK = $1 AND typePK = $2
+				AND time <= $3
+				ORDER BY time DESC
+				LIMIT 1)
+				UNION ALL
+				SELECT time, value FROM field.state_history
+				WHERE devicePK = $1 AND typePK = $2
+				AND time > $3 AND time <= $4
+				ORDER BY time ASC`, devicePK, typePK, timeRange[0], timeRange[1])
+	if err != nil {
+		return fr, weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var t time.Time
+
+	for rows.Next() {
+		var s = mtrpb.FieldState{DeviceID: deviceID, TypeID: typeID}
+
+		if err = rows.Scan(&t, &s.Value); err != nil {
+			return fr, weft.InternalServerError(err)
+		}
+
+		s.Seconds = t.Unix()
+
+		fr.Result = append(fr.Result, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fr, weft.InternalServerError(err)
+	}
+
+	return fr, &weft.StatusOK
+}
+
+func fieldStateHistoryProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	timeRange, err := parseTimeRange(v)
+	if err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	fr, res := fieldStateHistory(v.Get("deviceID"), v.Get("typeID"), timeRange)
+	if !res.Ok {
+		return res
+	}
+
+	var by []byte
+	if by, err = proto.Marshal(&fr); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+// fieldStateHistoryCsv writes the changes of state with the value as 1 (on) or 0 (off).
+func fieldStateHistoryCsv(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	timeRange, err := parseTimeRange(v)
+	if err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	typeID := v.Get("typeID")
+
+	fr, res := fieldStateHistory(v.Get("deviceID"), typeID, timeRange)
+	if !res.Ok {
+		return res
+	}
+
+	w := csv.NewWriter(b)
+
+	if err = w.Write([]string{"time", typeID}); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	for _, s := range fr.Result {
+		if err = w.Write([]string{time.Unix(s.Seconds, 0).UTC().Format(DYGRAPH_TIME_FORMAT), stateValue(s.Value)}); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+/*
+fieldStateHistorySvg plots the state as a step plot so the on and off intervals e.g., mains
+outages, can be seen.  The state at the start of the time range is extended to the start and
+the last state is extended to the end.
+*/
+func fieldStateHistorySvg(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	timeRange, err := parseTimeRange(v)
+	if err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	deviceID := v.Get("deviceID")
+	typeID := v.Get("typeID")
+
+	fr, res := fieldStateHistory(deviceID, typeID, timeRange)
+	if !res.Ok {
+		return res
+	}
+
+	var pts []ts.Point
+
+	for _, s := range fr.Result {
+		pt := ts.Point{DateTime: time.Unix(s.Seconds, 0).UTC(), Value: 0.0}
+		if s.Value {
+			pt.Value = 1.0
+		}
+
+		if pt.DateTime.Before(timeRange[0]) {
+			pt.DateTime = timeRange[0]
+		}
+
+		pts = append(pts, pt)
+	}
+
+	if len(pts) > 0 {
+		pts = append(pts, ts.Point{DateTime: timeRange[1], Value: pts[len(pts)-1].Value})
+	}
+
+	var p ts.Plot
+
+	p.SetTitle(fmt.Sprintf("Device: %s, State: %s", deviceID, typeID))
+	p.SetSubTitle("1 on, 0 off")
+	p.SetXAxis(timeRange[0], timeRange[1])
+	p.SetXLabel(fmt.Sprintf("%s to %s", timeRange[0].Format(time.RFC3339), timeRange[1].Format(time.RFC3339)))
+	p.SetYAxis(0.0, 1.0)
+	p.AddSeries(ts.Series{Colour: "deepskyblue", Points: pts})
+
+	if err = ts.Step.Draw(p, b); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func stateValue(v bool) string {
+	if v {
+		return "1"
+	}
+
+	return "0"
+}
@@ -9,37 +9,36 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/lib/pq"
 	"net/http"
-	"strconv"
+	"time"
 )
 
 func fieldThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	v := r.URL.Query()
 	var err error
 
-	var lower, upper int
-
-	if lower, err = strconv.Atoi(v.Get("lower")); err != nil {
-		return weft.BadRequest("invalid lower")
-	}
-
-	if upper, err = strconv.Atoi(v.Get("upper")); err != nil {
-		return weft.BadRequest("invalid upper")
+	t, res := parseThreshold(v)
+	if !res.Ok {
+		return res
 	}
 
 	deviceID := v.Get("deviceID")
 	typeID := v.Get("typeID")
 
+	if deviceID == "" {
+		return fieldModelThresholdPut(v.Get("modelID"), typeID, t)
+	}
+
 	var result sql.Result
 
 	// TODO - use upsert with PG 9.5?
 
 	// return if insert succeeds
-	if result, err = db.Exec(`INSERT INTO field.threshold(devicePK, typePK, lower, upper)
-		SELECT devicePK, typePK, $3, $4
+	if result, err = db.Exec(`INSERT INTO field.threshold(devicePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis)
+		SELECT devicePK, typePK, $3, $4, $5, $6, $7
 				FROM field.device, field.type
 				WHERE deviceID = $1
 				AND typeID = $2`,
-		deviceID, typeID, lower, upper); err == nil {
+		deviceID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err == nil {
 		var i int64
 		if i, err = result.RowsAffected(); err != nil {
 			return weft.InternalServerError(err)
@@ -51,10 +50,10 @@ func fieldThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Re
 
 	// return if update one row
 	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
-		if result, err = db.Exec(`UPDATE field.threshold SET lower=$3, upper=$4
+		if result, err = db.Exec(`UPDATE field.threshold SET lower=$3, upper=$4, warning_lower=$5, warning_upper=$6, hysteresis=$7
 		WHERE devicePK = (SELECT devicePK FROM field.device WHERE deviceID = $1)
 		AND typePK = (SELECT typePK FROM field.type WHERE typeID = $2)`,
-			deviceID, typeID, lower, upper); err == nil {
+			deviceID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err == nil {
 			var i int64
 			if i, err = result.RowsAffected(); err != nil {
 				return weft.InternalServerError(err)
@@ -72,11 +71,69 @@ func fieldThresholdPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Re
 	return weft.InternalServerError(err)
 }
 
+/*
+fieldModelThresholdPut sets the threshold template for typeID on all devices of modelID.
+Devices with their own threshold for typeID are not changed.
+*/
+func fieldModelThresholdPut(modelID, typeID string, t threshold) *weft.Result {
+	if modelID == "" {
+		return weft.BadRequest("deviceID or modelID is required")
+	}
+
+	var result sql.Result
+	var err error
+
+	// return if insert succeeds or update if there is already a template.
+	if result, err = db.Exec(`INSERT INTO field.model_threshold(modelPK, typePK, lower, upper, warning_lower, warning_upper, hysteresis)
+		SELECT modelPK, typePK, $3, $4, $5, $6, $7
+				FROM field.model, field.type
+				WHERE modelID = $1
+				AND typeID = $2`,
+		modelID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err != nil {
+		if pqErr, ok := err.(*pq.Error); !ok || pqErr.Code != errorUniqueViolation {
+			return weft.InternalServerError(err)
+		}
+
+		if result, err = db.Exec(`UPDATE field.model_threshold SET lower=$3, upper=$4, warning_lower=$5, warning_upper=$6, hysteresis=$7
+			WHERE modelPK = (SELECT modelPK FROM field.model WHERE modelID = $1)
+			AND typePK = (SELECT typePK FROM field.type WHERE typeID = $2)`,
+			modelID, typeID, t.lower, t.upper, t.warningLower, t.warningUpper, t.hysteresis); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return weft.InternalServerError(err)
+	}
+	if i != 1 {
+		return weft.BadRequest("Didn't create row, check your query parameters exist")
+	}
+
+	return &weft.StatusOK
+}
+
+// fieldThresholdDelete deletes the threshold for the device or, if there is no deviceID, the template for the model.
 func fieldThresholdDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	var err error
 
 	v := r.URL.Query()
 
+	if v.Get("deviceID") == "" {
+		if v.Get("modelID") == "" {
+			return weft.BadRequest("deviceID or modelID is required")
+		}
+
+		if _, err = db.Exec(`DELETE FROM field.model_threshold
+			WHERE modelPK = (SELECT modelPK FROM field.model WHERE modelID = $1)
+			AND typePK = (SELECT typePK FROM field.type WHERE typeID = $2)`,
+			v.Get("modelID"), v.Get("typeID")); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		return &weft.StatusOK
+	}
+
 	if _, err = db.Exec(`DELETE FROM field.threshold
 		WHERE devicePK = (SELECT devicePK FROM field.device WHERE deviceID = $1)
 		AND typePK = (SELECT typePK FROM field.type WHERE typeID = $2)`,
@@ -87,28 +144,62 @@ func fieldThresholdDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft
 	return &weft.StatusOK
 }
 
+/*
+fieldThresholdProto returns the threshold in effect for each device, and whether it is inherited from the
+template for the model or overridden for the device, followed by the templates.
+*/
 func fieldThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT deviceID, typeID, lower, upper, scale
-		FROM
-		field.threshold JOIN field.device USING (devicepk)
-		JOIN field.type USING (typepk)`); err != nil {
+	if rows, err = dbR.Query(`SELECT deviceID, modelID, typeID, lower, upper, warning_lower, warning_upper, hysteresis, scale, inherited
+		FROM ` + fieldThreshold + ` AS threshold
+		JOIN field.device USING (devicepk)
+		JOIN field.model USING (modelpk)
+		JOIN field.type USING (typepk)
+		ORDER BY deviceID, typeID`); err != nil {
 		return weft.InternalServerError(err)
 	}
+	defer rows.Close()
 
 	var ts mtrpb.FieldMetricThresholdResult
 
 	for rows.Next() {
 		var t mtrpb.FieldMetricThreshold
 
-		if err = rows.Scan(&t.DeviceID, &t.TypeID, &t.Lower, &t.Upper, &t.Scale); err != nil {
+		if err = rows.Scan(&t.DeviceID, &t.ModelID, &t.TypeID, &t.FloatLower, &t.FloatUpper,
+			&t.FloatWarningLower, &t.FloatWarningUpper, &t.FloatHysteresis, &t.Scale, &t.Inherited); err != nil {
 			return weft.InternalServerError(err)
 		}
 
+		roundFieldMetricThreshold(&t)
+
 		ts.Result = append(ts.Result, &t)
 	}
+	rows.Close()
+
+	if rows, err = dbR.Query(`SELECT modelID, typeID, lower, upper, warning_lower, warning_upper, hysteresis, scale
+		FROM field.model_threshold
+		JOIN field.model USING (modelpk)
+		JOIN field.type USING (typepk)
+		ORDER BY modelID, typeID`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t mtrpb.FieldMetricThreshold
+
+		if err = rows.Scan(&t.ModelID, &t.TypeID, &t.FloatLower, &t.FloatUpper,
+			&t.FloatWarningLower, &t.FloatWarningUpper, &t.FloatHysteresis, &t.Scale); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		roundFieldMetricThreshold(&t)
+
+		ts.Template = append(ts.Template, &t)
+	}
+	rows.Close()
 
 	var by []byte
 	if by, err = proto.Marshal(&ts); err != nil {
@@ -119,3 +210,52 @@ func fieldThresholdProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.
 
 	return &weft.StatusOK
 }
+
+/*
+fieldThresholdMissingProto returns the latest value for the field metrics that have no threshold so
+they can be found and fixed.  Metrics with a threshold of lower and upper 0 are included.
+*/
+func fieldThresholdMissingProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var err error
+	var rows *sql.Rows
+
+	if rows, err = dbR.Query(`SELECT deviceID, modelID, typeID, time, value, scale, ` + fieldMetricInterval.late() + `
+		FROM field.metric_summary
+		JOIN field.device USING (devicePK)
+		JOIN field.model USING (modelPK)
+		JOIN field.type USING (typePK)
+		LEFT JOIN ` + fieldThreshold + ` AS threshold USING (devicePK, typePK)
+		LEFT JOIN field.metric_interval USING (devicePK, typePK)
+		WHERE COALESCE(lower, 0) = 0 AND COALESCE(upper, 0) = 0
+		ORDER BY deviceID, typeID`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var t time.Time
+	var fmlr mtrpb.FieldMetricSummaryResult
+
+	for rows.Next() {
+		var fmr = mtrpb.FieldMetricSummary{Unknown: true}
+
+		if err = rows.Scan(&fmr.DeviceID, &fmr.ModelID, &fmr.TypeID, &t, &fmr.FloatValue, &fmr.Scale, &fmr.Late); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		fmr.Seconds = t.Unix()
+
+		roundFieldMetricSummary(&fmr)
+
+		fmlr.Result = append(fmlr.Result, &fmr)
+	}
+	rows.Close()
+
+	var by []byte
+	if by, err = proto.Marshal(&fmlr); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
@@ -9,11 +9,91 @@ import (
 	"net/http"
 )
 
+// fieldTypePut adds or updates a field.type.
+func fieldTypePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	typeID := v.Get("typeID")
+
+	if res := validTypeID(typeID); !res.Ok {
+		return res
+	}
+
+	scale, res := parseScale(v.Get("scale"))
+	if !res.Ok {
+		return res
+	}
+
+	if v.Get("display") == "" {
+		return weft.BadRequest("empty display")
+	}
+
+	unit := v.Get("unit")
+	if unit == "" {
+		unit = "n"
+	}
+
+	return fieldMetricType.put(typeID, []string{"description", "unit", "scale", "display"},
+		v.Get("description"), unit, scale, v.Get("display"))
+}
+
+// fieldTypeDelete deletes a field.type.  A type with data is only deleted if force is true.
+func fieldTypeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldMetricType.delete(r)
+}
+
+// fieldStateTypePut adds a field.state_type.
+func fieldStateTypePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	typeID := r.URL.Query().Get("typeID")
+
+	if res := validTypeID(typeID); !res.Ok {
+		return res
+	}
+
+	return fieldStateType.put(typeID, nil)
+}
+
+// fieldStateTypeDelete deletes a field.state_type.  A type with states is only deleted if force is true.
+func fieldStateTypeDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldStateType.delete(r)
+}
+
+func fieldStateTypeProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var err error
+	var rows *sql.Rows
+
+	if rows, err = dbR.Query(`SELECT typeID FROM field.state_type ORDER BY typeID ASC`); err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var ftr mtrpb.FieldTypeResult
+
+	for rows.Next() {
+		var ft mtrpb.FieldType
+
+		if err = rows.Scan(&ft.TypeID); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		ftr.Result = append(ftr.Result, &ft)
+	}
+
+	var by []byte
+	if by, err = proto.Marshal(&ftr); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
 func fieldTypeProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	var err error
 	var rows *sql.Rows
 
-	if rows, err = dbR.Query(`SELECT typeID, display FROM field.type ORDER BY typeID ASC`); err != nil {
+	if rows, err = dbR.Query(`SELECT typeID, display, description, unit, scale FROM field.type ORDER BY typeID ASC`); err != nil {
 		return weft.InternalServerError(err)
 	}
 	defer rows.Close()
@@ -23,7 +103,7 @@ func fieldTypeProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resul
 	for rows.Next() {
 		var ft mtrpb.FieldType
 
-		if err = rows.Scan(&ft.TypeID, &ft.Display); err != nil {
+		if err = rows.Scan(&ft.TypeID, &ft.Display, &ft.Description, &ft.Unit, &ft.Scale); err != nil {
 			return weft.InternalServerError(err)
 		}
 
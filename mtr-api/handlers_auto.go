@@ -14,30 +14,51 @@ var mux = http.NewServeMux()
 
 func init() {
 	mux.HandleFunc("/api-docs", weft.MakeHandlerPage(docHandler))
+	mux.HandleFunc("/alert", weft.MakeHandlerAPI(alertHandler))
+	mux.HandleFunc("/alert/notify", weft.MakeHandlerAPI(alertnotifyHandler))
 	mux.HandleFunc("/app", weft.MakeHandlerAPI(appHandler))
 	mux.HandleFunc("/app/metric", weft.MakeHandlerAPI(appmetricHandler))
 	mux.HandleFunc("/application/counter", weft.MakeHandlerAPI(applicationcounterHandler))
 	mux.HandleFunc("/application/metric", weft.MakeHandlerAPI(applicationmetricHandler))
 	mux.HandleFunc("/application/timer", weft.MakeHandlerAPI(applicationtimerHandler))
+	mux.HandleFunc("/application/type", weft.MakeHandlerAPI(applicationtypeHandler))
 	mux.HandleFunc("/data/completeness", weft.MakeHandlerAPI(datacompletenessHandler))
+	mux.HandleFunc("/data/completeness/interval", weft.MakeHandlerAPI(datacompletenessintervalHandler))
 	mux.HandleFunc("/data/completeness/summary", weft.MakeHandlerAPI(datacompletenesssummaryHandler))
 	mux.HandleFunc("/data/completeness/tag", weft.MakeHandlerAPI(datacompletenesstagHandler))
 	mux.HandleFunc("/data/completeness/type", weft.MakeHandlerAPI(datacompletenesstypeHandler))
 	mux.HandleFunc("/data/latency", weft.MakeHandlerAPI(datalatencyHandler))
+	mux.HandleFunc("/data/latency/interval", weft.MakeHandlerAPI(datalatencyintervalHandler))
 	mux.HandleFunc("/data/latency/summary", weft.MakeHandlerAPI(datalatencysummaryHandler))
 	mux.HandleFunc("/data/latency/tag", weft.MakeHandlerAPI(datalatencytagHandler))
 	mux.HandleFunc("/data/latency/threshold", weft.MakeHandlerAPI(datalatencythresholdHandler))
+	mux.HandleFunc("/data/latency/threshold/missing", weft.MakeHandlerAPI(datalatencythresholdmissingHandler))
 	mux.HandleFunc("/data/site", weft.MakeHandlerAPI(datasiteHandler))
+	mux.HandleFunc("/data/site/attribute", weft.MakeHandlerAPI(datasiteattributeHandler))
+	mux.HandleFunc("/data/site/detail", weft.MakeHandlerAPI(datasitedetailHandler))
+	mux.HandleFunc("/data/site/location", weft.MakeHandlerAPI(datasitelocationHandler))
+	mux.HandleFunc("/data/site/rename", weft.MakeHandlerAPI(datasiterenameHandler))
 	mux.HandleFunc("/data/type", weft.MakeHandlerAPI(datatypeHandler))
 	mux.HandleFunc("/field/device", weft.MakeHandlerAPI(fielddeviceHandler))
+	mux.HandleFunc("/field/device/attribute", weft.MakeHandlerAPI(fielddeviceattributeHandler))
+	mux.HandleFunc("/field/device/detail", weft.MakeHandlerAPI(fielddevicedetailHandler))
+	mux.HandleFunc("/field/device/location", weft.MakeHandlerAPI(fielddevicelocationHandler))
+	mux.HandleFunc("/field/device/rename", weft.MakeHandlerAPI(fielddevicerenameHandler))
 	mux.HandleFunc("/field/metric", weft.MakeHandlerAPI(fieldmetricHandler))
+	mux.HandleFunc("/field/metric/interval", weft.MakeHandlerAPI(fieldmetricintervalHandler))
 	mux.HandleFunc("/field/metric/summary", weft.MakeHandlerAPI(fieldmetricsummaryHandler))
 	mux.HandleFunc("/field/metric/tag", weft.MakeHandlerAPI(fieldmetrictagHandler))
 	mux.HandleFunc("/field/metric/threshold", weft.MakeHandlerAPI(fieldmetricthresholdHandler))
+	mux.HandleFunc("/field/metric/threshold/missing", weft.MakeHandlerAPI(fieldmetricthresholdmissingHandler))
 	mux.HandleFunc("/field/model", weft.MakeHandlerAPI(fieldmodelHandler))
 	mux.HandleFunc("/field/state", weft.MakeHandlerAPI(fieldstateHandler))
+	mux.HandleFunc("/field/state/history", weft.MakeHandlerAPI(fieldstatehistoryHandler))
 	mux.HandleFunc("/field/state/tag", weft.MakeHandlerAPI(fieldstatetagHandler))
+	mux.HandleFunc("/field/state/type", weft.MakeHandlerAPI(fieldstatetypeHandler))
 	mux.HandleFunc("/field/type", weft.MakeHandlerAPI(fieldtypeHandler))
+	mux.HandleFunc("/ingest/map", weft.MakeHandlerAPI(ingestmapHandler))
+	mux.HandleFunc("/metrics", weft.MakeHandlerAPI(metricsHandler))
+	mux.HandleFunc("/retention", weft.MakeHandlerAPI(retentionHandler))
 	mux.HandleFunc("/tag", weft.MakeHandlerAPI(tagHandler))
 	mux.HandleFunc("/tag/", weft.MakeHandlerAPI(tagsHandler))
 }
@@ -55,6 +76,62 @@ func docHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 		return &weft.MethodNotAllowed
 	}
 }
+func alertHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/json":
+			if res := weft.CheckQuery(r, []string{}, []string{"endDate", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return alertJSON(r, h, b)
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{"endDate", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return alertProto(r, h, b)
+		default:
+			if res := weft.CheckQuery(r, []string{}, []string{"endDate", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return alertJSON(r, h, b)
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func alertnotifyHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{"tag"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return notifyRuleProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"receiver", "tag"}, []string{}); !res.Ok {
+			return res
+		}
+		return notifyRulePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"receiver", "tag"}, []string{}); !res.Ok {
+			return res
+		}
+		return notifyRuleDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func appHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -128,7 +205,7 @@ func applicationmetricHandler(r *http.Request, h http.Header, b *bytes.Buffer) *
 func applicationtimerHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "PUT":
-		if res := weft.CheckQuery(r, []string{"applicationID", "average", "count", "fifty", "instanceID", "ninety", "sourceID", "time"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"applicationID", "average", "count", "fifty", "instanceID", "ninety", "sourceID", "time"}, []string{"max", "min", "ninetynine", "sketch"}); !res.Ok {
 			return res
 		}
 		return applicationTimerPut(r, h, b)
@@ -137,6 +214,23 @@ func applicationtimerHandler(r *http.Request, h http.Header, b *bytes.Buffer) *w
 	}
 }
 
+func applicationtypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"description", "unit"}); !res.Ok {
+			return res
+		}
+		return applicationTypePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"force"}); !res.Ok {
+			return res
+		}
+		return applicationTypeDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func datacompletenessHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -169,12 +263,40 @@ func datacompletenessHandler(r *http.Request, h http.Header, b *bytes.Buffer) *w
 	}
 }
 
+func datacompletenessintervalHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{"typeID"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataCompletenessIntervalProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"interval", "typeID"}, []string{"siteID"}); !res.Ok {
+			return res
+		}
+		return dataCompletenessIntervalPut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"siteID", "typeID"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataCompletenessIntervalDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func datacompletenesssummaryHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
 		switch r.Header.Get("Accept") {
 		case "image/svg+xml":
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
@@ -186,7 +308,7 @@ func datacompletenesssummaryHandler(r *http.Request, h http.Header, b *bytes.Buf
 			h.Set("Content-Type", "application/x-protobuf")
 			return dataCompletenessSummaryProto(r, h, b)
 		default:
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
@@ -238,6 +360,16 @@ func datacompletenesstypeHandler(r *http.Request, h http.Header, b *bytes.Buffer
 		default:
 			return &weft.NotAcceptable
 		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"expected", "typeID"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataCompletenessTypePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"force"}); !res.Ok {
+			return res
+		}
+		return dataCompletenessTypeDelete(r, h, b)
 	default:
 		return &weft.MethodNotAllowed
 	}
@@ -287,12 +419,40 @@ func datalatencyHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 	}
 }
 
+func datalatencyintervalHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{"typeID"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataLatencyIntervalProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"interval", "typeID"}, []string{"siteID"}); !res.Ok {
+			return res
+		}
+		return dataLatencyIntervalPut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"siteID", "typeID"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataLatencyIntervalDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func datalatencysummaryHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
 		switch r.Header.Get("Accept") {
 		case "image/svg+xml":
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
@@ -304,7 +464,7 @@ func datalatencysummaryHandler(r *http.Request, h http.Header, b *bytes.Buffer)
 			h.Set("Content-Type", "application/x-protobuf")
 			return dataLatencySummaryProto(r, h, b)
 		default:
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
@@ -357,12 +517,12 @@ func datalatencythresholdHandler(r *http.Request, h http.Header, b *bytes.Buffer
 			return &weft.NotAcceptable
 		}
 	case "PUT":
-		if res := weft.CheckQuery(r, []string{"lower", "siteID", "typeID", "upper"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"lower", "typeID", "upper"}, []string{"hysteresis", "siteID", "warningLower", "warningUpper"}); !res.Ok {
 			return res
 		}
 		return dataLatencyThresholdPut(r, h, b)
 	case "DELETE":
-		if res := weft.CheckQuery(r, []string{"siteID", "typeID"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"siteID"}); !res.Ok {
 			return res
 		}
 		return dataLatencyThresholdDelete(r, h, b)
@@ -371,6 +531,24 @@ func datalatencythresholdHandler(r *http.Request, h http.Header, b *bytes.Buffer
 	}
 }
 
+func datalatencythresholdmissingHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataLatencyThresholdMissingProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func datasiteHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -385,7 +563,7 @@ func datasiteHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resu
 			return &weft.NotAcceptable
 		}
 	case "PUT":
-		if res := weft.CheckQuery(r, []string{"latitude", "longitude", "siteID"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"latitude", "longitude", "siteID"}, []string{"status"}); !res.Ok {
 			return res
 		}
 		return dataSitePut(r, h, b)
@@ -399,6 +577,76 @@ func datasiteHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resu
 	}
 }
 
+func datasiteattributeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"key", "siteID", "value"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataSiteAttributePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"key", "siteID"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataSiteAttributeDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func datasitedetailHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{"siteID"}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataSiteDetailProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func datasitelocationHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{"siteID"}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return dataSiteLocationProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"latitude", "longitude", "siteID"}, []string{"time"}); !res.Ok {
+			return res
+		}
+		return dataSiteLocationPut(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func datasiterenameHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"newSiteID", "siteID"}, []string{}); !res.Ok {
+			return res
+		}
+		return dataSiteRenamePut(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func datatypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -412,6 +660,16 @@ func datatypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Resu
 		default:
 			return &weft.NotAcceptable
 		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"display", "scale", "typeID"}, []string{"description", "unit"}); !res.Ok {
+			return res
+		}
+		return dataTypePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"force"}); !res.Ok {
+			return res
+		}
+		return dataTypeDelete(r, h, b)
 	default:
 		return &weft.MethodNotAllowed
 	}
@@ -431,7 +689,7 @@ func fielddeviceHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 			return &weft.NotAcceptable
 		}
 	case "PUT":
-		if res := weft.CheckQuery(r, []string{"deviceID", "latitude", "longitude", "modelID"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"deviceID", "latitude", "longitude", "modelID"}, []string{"status"}); !res.Ok {
 			return res
 		}
 		return fieldDevicePut(r, h, b)
@@ -445,6 +703,76 @@ func fielddeviceHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 	}
 }
 
+func fielddeviceattributeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"deviceID", "key", "value"}, []string{}); !res.Ok {
+			return res
+		}
+		return fieldDeviceAttributePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"deviceID", "key"}, []string{}); !res.Ok {
+			return res
+		}
+		return fieldDeviceAttributeDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func fielddevicedetailHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{"deviceID"}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldDeviceDetailProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func fielddevicelocationHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{"deviceID"}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldDeviceLocationProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"deviceID", "latitude", "longitude"}, []string{"time"}); !res.Ok {
+			return res
+		}
+		return fieldDeviceLocationPut(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func fielddevicerenameHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"deviceID", "newDeviceID"}, []string{}); !res.Ok {
+			return res
+		}
+		return fieldDeviceRenamePut(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func fieldmetricHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -489,6 +817,34 @@ func fieldmetricHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.R
 	}
 }
 
+func fieldmetricintervalHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{"typeID"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldMetricIntervalProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"interval", "typeID"}, []string{"deviceID"}); !res.Ok {
+			return res
+		}
+		return fieldMetricIntervalPut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{}); !res.Ok {
+			return res
+		}
+		return fieldMetricIntervalDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func fieldmetricsummaryHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -500,19 +856,19 @@ func fieldmetricsummaryHandler(r *http.Request, h http.Header, b *bytes.Buffer)
 			h.Set("Content-Type", "application/x-protobuf")
 			return fieldLatestProto(r, h, b)
 		case "image/svg+xml":
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
 			return fieldLatestSvg(r, h, b)
 		case "application/vnd.geo+json":
-			if res := weft.CheckQuery(r, []string{"typeID"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"typeID"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "application/vnd.geo+json")
 			return fieldLatestGeoJSON(r, h, b)
 		default:
-			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{}); !res.Ok {
+			if res := weft.CheckQuery(r, []string{"bbox", "typeID", "width"}, []string{"time"}); !res.Ok {
 				return res
 			}
 			h.Set("Content-Type", "image/svg+xml")
@@ -565,12 +921,12 @@ func fieldmetricthresholdHandler(r *http.Request, h http.Header, b *bytes.Buffer
 			return &weft.NotAcceptable
 		}
 	case "PUT":
-		if res := weft.CheckQuery(r, []string{"deviceID", "lower", "typeID", "upper"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"lower", "typeID", "upper"}, []string{"deviceID", "hysteresis", "modelID", "warningLower", "warningUpper"}); !res.Ok {
 			return res
 		}
 		return fieldThresholdPut(r, h, b)
 	case "DELETE":
-		if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{}); !res.Ok {
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"deviceID", "modelID"}); !res.Ok {
 			return res
 		}
 		return fieldThresholdDelete(r, h, b)
@@ -579,6 +935,24 @@ func fieldmetricthresholdHandler(r *http.Request, h http.Header, b *bytes.Buffer
 	}
 }
 
+func fieldmetricthresholdmissingHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldThresholdMissingProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func fieldmodelHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -635,6 +1009,40 @@ func fieldstateHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Re
 	}
 }
 
+func fieldstatehistoryHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{"endDate", "resolution", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldStateHistoryProto(r, h, b)
+		case "text/csv":
+			if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{"endDate", "resolution", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "text/csv")
+			return fieldStateHistoryCsv(r, h, b)
+		case "image/svg+xml":
+			if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{"endDate", "resolution", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "image/svg+xml")
+			return fieldStateHistorySvg(r, h, b)
+		default:
+			if res := weft.CheckQuery(r, []string{"deviceID", "typeID"}, []string{"endDate", "resolution", "startDate"}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "image/svg+xml")
+			return fieldStateHistorySvg(r, h, b)
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func fieldstatetagHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -663,6 +1071,34 @@ func fieldstatetagHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft
 	}
 }
 
+func fieldstatetypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return fieldStateTypeProto(r, h, b)
+		default:
+			return &weft.NotAcceptable
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{}); !res.Ok {
+			return res
+		}
+		return fieldStateTypePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"force"}); !res.Ok {
+			return res
+		}
+		return fieldStateTypeDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
 func fieldtypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 	switch r.Method {
 	case "GET":
@@ -676,6 +1112,114 @@ func fieldtypeHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Res
 		default:
 			return &weft.NotAcceptable
 		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"display", "scale", "typeID"}, []string{"description", "unit"}); !res.Ok {
+			return res
+		}
+		return fieldTypePut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"typeID"}, []string{"force"}); !res.Ok {
+			return res
+		}
+		return fieldTypeDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func ingestmapHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/json":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return ingestMapJSON(r, h, b)
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return ingestMapProto(r, h, b)
+		default:
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return ingestMapJSON(r, h, b)
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"label", "name", "table", "typeID"}, []string{"scale"}); !res.Ok {
+			return res
+		}
+		return ingestMapPut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"name"}, []string{}); !res.Ok {
+			return res
+		}
+		return ingestMapDelete(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func metricsHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "text/plain; version=0.0.4":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "text/plain; version=0.0.4")
+			return metricsText(r, h, b)
+		default:
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "text/plain; version=0.0.4")
+			return metricsText(r, h, b)
+		}
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+func retentionHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "GET":
+		switch r.Header.Get("Accept") {
+		case "application/json":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return retentionJSON(r, h, b)
+		case "application/x-protobuf":
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/x-protobuf")
+			return retentionProto(r, h, b)
+		default:
+			if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+				return res
+			}
+			h.Set("Content-Type", "application/json")
+			return retentionJSON(r, h, b)
+		}
+	case "PUT":
+		if res := weft.CheckQuery(r, []string{"days", "table"}, []string{"requireRollup", "typeID"}); !res.Ok {
+			return res
+		}
+		return retentionPut(r, h, b)
+	case "DELETE":
+		if res := weft.CheckQuery(r, []string{"table"}, []string{"typeID"}); !res.Ok {
+			return res
+		}
+		return retentionDelete(r, h, b)
 	default:
 		return &weft.MethodNotAllowed
 	}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/*
+Metrics can be ingested in Influx line protocol (see ingest_influx.go) and Prometheus
+remote write (see ingest_prometheus.go).  The metric name is mapped onto a type in field.metric
+or data.latency by mtr.ingest_map and the deviceID or siteID is read from a tag or label.
+Points are saved with the batch uploads for field.metric and data.latency so the summaries
+are updated in the same way as PUT /field/metric and PUT /data/latency.
+*/
+
+// ingestTables are the tables that metrics can be ingested into and the type table for each of them.
+var ingestTables = map[string]string{
+	"field.metric": "field.type",
+	"data.latency": "data.type",
+}
+
+var (
+	noIngestMap   = weft.Result{Ok: false, Code: http.StatusNotFound, Msg: "no ingest map for the metric name"}
+	noIngestLabel = weft.Result{Ok: false, Code: http.StatusBadRequest, Msg: "missing the label for the deviceID or siteID"}
+	invalidIngest = weft.Result{Ok: false, Code: http.StatusBadRequest, Msg: "value out of range"}
+)
+
+// ingestPoint is a metric value parsed from Influx line protocol or Prometheus remote write.
+type ingestPoint struct {
+	name   string
+	labels map[string]string
+	value  float64
+	t      time.Time
+}
+
+// ingestMaps returns the ingest maps keyed by name.
+func ingestMaps() (map[string]*mtrpb.IngestMap, error) {
+	rows, err := db.Query(`SELECT name, tableName, typeID, label, scale FROM mtr.ingest_map`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	maps := make(map[string]*mtrpb.IngestMap)
+
+	for rows.Next() {
+		var m mtrpb.IngestMap
+
+		if err = rows.Scan(&m.Name, &m.Table, &m.TypeID, &m.Label, &m.Scale); err != nil {
+			return nil, err
+		}
+
+		maps[m.Name] = &m
+	}
+	rows.Close()
+
+	return maps, rows.Err()
+}
+
+/*
+saveIngest saves the points that have an ingest map.  res must have a row for each point and
+nil points are skipped (their result should already be set).  The outcome for each point is set in res
+as for the batch uploads with the addition of:
+
+	http.StatusNotFound - there is no ingest map for the metric name.
+	http.StatusBadRequest - the label is missing or the scaled value is NaN or Inf.
+
+Values are saved as float64 so any other scaled value is valid.
+*/
+func saveIngest(points []*ingestPoint, res *mtrpb.BatchResult) *weft.Result {
+	maps, err := ingestMaps()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	field := make([]*mtrpb.FieldMetricBatchRow, len(points))
+	latency := make([]*mtrpb.DataLatencyBatchRow, len(points))
+
+	var nField, nLatency int
+
+	for i, p := range points {
+		if p == nil {
+			continue
+		}
+
+		m, ok := maps[p.name]
+		if !ok {
+			setRow(res, i, &noIngestMap)
+			continue
+		}
+
+		id := p.labels[m.Label]
+		if id == "" {
+			setRow(res, i, &noIngestLabel)
+			continue
+		}
+
+		v := p.value * m.Scale
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			setRow(res, i, &invalidIngest)
+			continue
+		}
+
+		switch m.Table {
+		case "field.metric":
+			field[i] = &mtrpb.FieldMetricBatchRow{DeviceID: id, TypeID: m.TypeID, Seconds: p.t.Unix(), FloatValue: v}
+			nField++
+		case "data.latency":
+			latency[i] = &mtrpb.DataLatencyBatchRow{SiteID: id, TypeID: m.TypeID, Seconds: p.t.Unix(), FloatMean: v}
+			nLatency++
+		}
+	}
+
+	if nField == 0 && nLatency == 0 {
+		return &weft.StatusOK
+	}
+
+	// the field and latency rows are saved in one transaction so that either all or none of the batch is saved.
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	if nField > 0 {
+		if s := (&fieldMetricBatch{}).saveTxn(txn, field, res); !s.Ok {
+			txn.Rollback()
+			return s
+		}
+	}
+
+	if nLatency > 0 {
+		if s := (&dataLatencyBatch{}).saveTxn(txn, latency, res); !s.Ok {
+			txn.Rollback()
+			return s
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func ingestMapPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	name := v.Get("name")
+	table := v.Get("table")
+	typeID := v.Get("typeID")
+	label := v.Get("label")
+
+	typ, ok := ingestTables[table]
+	if !ok {
+		return weft.BadRequest("table must be field.metric or data.latency")
+	}
+
+	var err error
+	scale := 1.0
+
+	if v.Get("scale") != "" {
+		if scale, err = strconv.ParseFloat(v.Get("scale"), 64); err != nil || scale == 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+			return weft.BadRequest("invalid scale")
+		}
+	}
+
+	var n int
+	if err = db.QueryRow(`SELECT count(*) FROM `+typ+` WHERE typeID = $1`, typeID).Scan(&n); err != nil {
+		return weft.InternalServerError(err)
+	}
+	if n != 1 {
+		return weft.BadRequest("unknown typeID")
+	}
+
+	var txn *sql.Tx
+	if txn, err = db.Begin(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var result sql.Result
+	if result, err = txn.Exec(`UPDATE mtr.ingest_map SET tableName = $2, typeID = $3, label = $4, scale = $5
+			WHERE name = $1`, name, table, typeID, label, scale); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	if i == 0 {
+		if _, err = txn.Exec(`INSERT INTO mtr.ingest_map(name, tableName, typeID, label, scale) VALUES($1, $2, $3, $4, $5)`,
+			name, table, typeID, label, scale); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func ingestMapDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	if _, err := db.Exec(`DELETE FROM mtr.ingest_map WHERE name = $1`, r.URL.Query().Get("name")); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func ingestMapList() (mtrpb.IngestMapResult, *weft.Result) {
+	var ir mtrpb.IngestMapResult
+
+	rows, err := dbR.Query(`SELECT name, tableName, typeID, label, scale FROM mtr.ingest_map ORDER BY name ASC`)
+	if err != nil {
+		return ir, weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m mtrpb.IngestMap
+
+		if err = rows.Scan(&m.Name, &m.Table, &m.TypeID, &m.Label, &m.Scale); err != nil {
+			return ir, weft.InternalServerError(err)
+		}
+
+		ir.Result = append(ir.Result, &m)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return ir, weft.InternalServerError(err)
+	}
+
+	return ir, &weft.StatusOK
+}
+
+func ingestMapProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	ir, res := ingestMapList()
+	if !res.Ok {
+		return res
+	}
+
+	by, err := proto.Marshal(&ir)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func ingestMapJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	ir, res := ingestMapList()
+	if !res.Ok {
+		return res
+	}
+
+	by, err := json.Marshal(&ir)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
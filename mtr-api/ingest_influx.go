@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"github.com/GeoNet/weft"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Metrics are ingested in Influx line protocol e.g.,
+
+	voltage,host=gps-taupoairport value=14.1 1431639630000000000
+	power,host=gps-taupoairport voltage=14.1,current=2i
+
+https://docs.influxdata.com/influxdb/v1.0/write_protocols/line_protocol_reference/
+
+The metric name is the measurement for the field "value" otherwise it is measurement_field
+e.g., power_voltage.  String fields are ignored and booleans are ingested as 1 or 0.
+The result has a row for each field in the order they are in the request body.
+
+The endpoint is also served at /ingest/influx/write so that clients that add /write
+to an Influx URL (e.g., Telegraf) can be pointed at /ingest/influx.
+*/
+
+// influxPrecision is the duration for the timestamp precision query parameter.
+var influxPrecision = map[string]time.Duration{
+	"":   time.Nanosecond,
+	"n":  time.Nanosecond,
+	"ns": time.Nanosecond,
+	"u":  time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+func ingestinfluxHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{"db", "rp", "precision", "consistency"}); !res.Ok {
+			return res
+		}
+
+		h.Set("Content-Type", "text/csv")
+		return ingestInflux(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+// ingestInflux saves the metrics in Influx line protocol from the request body.
+func ingestInflux(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	precision, ok := influxPrecision[r.URL.Query().Get("precision")]
+	if !ok {
+		return weft.BadRequest("invalid precision")
+	}
+
+	points, bad, err := parseInflux(r.Body, precision, time.Now().UTC())
+	if err != nil {
+		return weft.BadRequest("invalid line protocol: " + err.Error())
+	}
+
+	res := newBatchResult(len(points))
+
+	for i, v := range bad {
+		setRow(&res, i, v)
+	}
+
+	if s := saveIngest(points, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "text/csv", b)
+}
+
+/*
+parseInflux parses the points in line protocol from r.  Points without a timestamp are at now.
+Lines that can't be parsed have a nil point and the reason in bad.  err is only non nil if r can't be read.
+*/
+func parseInflux(r io.Reader, precision time.Duration, now time.Time) ([]*ingestPoint, map[int]*weft.Result, error) {
+	var points []*ingestPoint
+	bad := make(map[int]*weft.Result)
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p, err := parseInfluxLine(line, precision, now)
+		if err != nil {
+			bad[len(points)] = weft.BadRequest(err.Error())
+			points = append(points, nil)
+			continue
+		}
+
+		points = append(points, p...)
+	}
+
+	return points, bad, s.Err()
+}
+
+// parseInfluxLine returns a point for each numeric or boolean field in line.
+func parseInfluxLine(line string, precision time.Duration, now time.Time) ([]*ingestPoint, error) {
+	sections := splitInflux(line, ' ', true)
+
+	// remove repeated spaces between sections.
+	var sec []string
+	for _, v := range sections {
+		if v != "" {
+			sec = append(sec, v)
+		}
+	}
+
+	if len(sec) < 2 || len(sec) > 3 {
+		return nil, errors.New("expected measurement[,tags] fields [timestamp]")
+	}
+
+	t := now
+	if len(sec) == 3 {
+		ts, err := strconv.ParseInt(sec[2], 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid timestamp")
+		}
+		t = time.Unix(0, ts*int64(precision)).UTC()
+	}
+
+	keys := splitInflux(sec[0], ',', false)
+
+	measurement := unescapeInflux(keys[0])
+	if measurement == "" {
+		return nil, errors.New("missing measurement")
+	}
+
+	labels := make(map[string]string)
+
+	for _, v := range keys[1:] {
+		kv := splitInflux(v, '=', false)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.New("invalid tag")
+		}
+		labels[unescapeInflux(kv[0])] = unescapeInflux(kv[1])
+	}
+
+	var points []*ingestPoint
+
+	for _, v := range splitInflux(sec[1], ',', true) {
+		kv := splitInflux(v, '=', true)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, errors.New("invalid field")
+		}
+
+		val, ok, err := influxValue(kv[1])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		name := measurement
+		if f := unescapeInflux(kv[0]); f != "value" {
+			name = measurement + "_" + f
+		}
+
+		points = append(points, &ingestPoint{name: name, labels: labels, value: val, t: t})
+	}
+
+	return points, nil
+}
+
+// influxValue parses a field value.  ok is false for string fields.
+func influxValue(s string) (v float64, ok bool, err error) {
+	switch s {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, true, nil
+	}
+
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return 0, false, nil
+	case strings.HasSuffix(s, "i"):
+		var i int64
+		if i, err = strconv.ParseInt(strings.TrimSuffix(s, "i"), 10, 64); err != nil {
+			return 0, false, errors.New("invalid integer field")
+		}
+		return float64(i), true, nil
+	case strings.HasSuffix(s, "u"):
+		var u uint64
+		if u, err = strconv.ParseUint(strings.TrimSuffix(s, "u"), 10, 64); err != nil {
+			return 0, false, errors.New("invalid unsigned integer field")
+		}
+		return float64(u), true, nil
+	}
+
+	if v, err = strconv.ParseFloat(s, 64); err != nil {
+		return 0, false, errors.New("invalid float field")
+	}
+
+	return v, true, nil
+}
+
+// splitInflux splits s on sep where it is not escaped with a backslash.
+// If quoted is true sep is also ignored inside double quotes (string field values).
+func splitInflux(s string, sep byte, quoted bool) []string {
+	var parts []string
+	var inQuote bool
+
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '"' && quoted:
+			inQuote = !inQuote
+		case s[i] == sep && !inQuote:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// influxUnescaper removes the escapes from measurements, tag keys and values, and field keys.
+var influxUnescaper = strings.NewReplacer(`\,`, `,`, `\=`, `=`, `\ `, ` `, `\"`, `"`, `\\`, `\`)
+
+func unescapeInflux(s string) string {
+	return influxUnescaper.Replace(s)
+}
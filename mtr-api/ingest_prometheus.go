@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+/*
+Metrics are ingested from Prometheus remote write.  The request body is an mtrpb.PromWriteRequest
+(wire compatible with the Prometheus WriteRequest) compressed with the snappy block format.
+The metric name is the __name__ label.  The result is an mtrpb.BatchResult with a row for each
+sample in the order they are in the request.
+
+https://prometheus.io/docs/prometheus/latest/configuration/configuration/#remote_write
+*/
+
+// maxSnappyLen is the largest decoded request body that will be accepted.
+const maxSnappyLen = 32 << 20
+
+func ingestprometheusHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	switch r.Method {
+	case "POST":
+		if res := weft.CheckQuery(r, []string{}, []string{}); !res.Ok {
+			return res
+		}
+
+		h.Set("Content-Type", "application/x-protobuf")
+		return ingestPrometheus(r, h, b)
+	default:
+		return &weft.MethodNotAllowed
+	}
+}
+
+// ingestPrometheus saves the samples in a snappy compressed mtrpb.PromWriteRequest from the request body.
+func ingestPrometheus(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	by, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	n, err := snappy.DecodedLen(by)
+	if err != nil {
+		return weft.BadRequest("invalid snappy: " + err.Error())
+	}
+
+	if n > maxSnappyLen {
+		return weft.BadRequest("invalid snappy: decoded length too large")
+	}
+
+	if by, err = snappy.Decode(nil, by); err != nil {
+		return weft.BadRequest("invalid snappy: " + err.Error())
+	}
+
+	var w mtrpb.PromWriteRequest
+
+	if err = proto.Unmarshal(by, &w); err != nil {
+		return weft.BadRequest("invalid protobuf: " + err.Error())
+	}
+
+	var points []*ingestPoint
+
+	for _, ts := range w.Timeseries {
+		labels := make(map[string]string)
+		for _, l := range ts.Labels {
+			labels[l.Name] = l.Value
+		}
+
+		for _, s := range ts.Samples {
+			points = append(points, &ingestPoint{name: labels["__name__"], labels: labels, value: s.Value,
+				t: time.Unix(0, s.Timestamp*int64(time.Millisecond)).UTC()})
+		}
+	}
+
+	res := newBatchResult(len(points))
+
+	if s := saveIngest(points, &res); !s.Ok {
+		return s
+	}
+
+	return writeBatchResult(&res, "application/x-protobuf", b)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInfluxParse(t *testing.T) {
+	now := time.Date(2015, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	in := []struct {
+		line     string
+		name     string
+		labels   map[string]string
+		value    float64
+		t        time.Time
+		expected int // the number of points.
+	}{
+		{`voltage,host=gps-taupoairport value=14.1 1431639630000000000`, "voltage",
+			map[string]string{"host": "gps-taupoairport"}, 14.1, time.Unix(1431639630, 0).UTC(), 1},
+		{`voltage,host=gps-taupoairport value=14i`, "voltage",
+			map[string]string{"host": "gps-taupoairport"}, 14, now, 1},
+		{`power,host=gps-taupoairport,rack=a\ b current=2u,label="a, b=c",mains=t`, "power_current",
+			map[string]string{"host": "gps-taupoairport", "rack": "a b"}, 2, now, 2},
+		{`my\,measure value=-1.5e3`, "my,measure", map[string]string{}, -1500, now, 1},
+		{`voltage value="ok"`, "", nil, 0, now, 0},
+	}
+
+	for _, v := range in {
+		p, err := parseInfluxLine(v.line, time.Nanosecond, now)
+		if err != nil {
+			t.Errorf("%s: %s", v.line, err)
+			continue
+		}
+
+		if len(p) != v.expected {
+			t.Errorf("%s: expected %d points got %d", v.line, v.expected, len(p))
+			continue
+		}
+
+		if v.expected == 0 {
+			continue
+		}
+
+		if p[0].name != v.name {
+			t.Errorf("%s: expected name %s got %s", v.line, v.name, p[0].name)
+		}
+
+		if p[0].value != v.value {
+			t.Errorf("%s: expected value %f got %f", v.line, v.value, p[0].value)
+		}
+
+		if !p[0].t.Equal(v.t) {
+			t.Errorf("%s: expected time %s got %s", v.line, v.t, p[0].t)
+		}
+
+		if len(p[0].labels) != len(v.labels) {
+			t.Errorf("%s: expected %d labels got %d", v.line, len(v.labels), len(p[0].labels))
+		}
+
+		for k, l := range v.labels {
+			if p[0].labels[k] != l {
+				t.Errorf("%s: expected label %s=%s got %s", v.line, k, l, p[0].labels[k])
+			}
+		}
+	}
+}
+
+// Lines that can't be parsed are a nil point with the reason in bad.
+func TestInfluxParseBad(t *testing.T) {
+	body := `# a comment
+voltage,host=gps-taupoairport value=14.1 1431639630
+
+voltage,host=gps-taupoairport
+voltage,host value=14.1
+voltage value=fred
+voltage value=14.1 fred
+`
+	p, bad, err := parseInflux(strings.NewReader(body), time.Second, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p) != 5 {
+		t.Fatalf("expected 5 points got %d", len(p))
+	}
+
+	if p[0] == nil || !p[0].t.Equal(time.Unix(1431639630, 0)) {
+		t.Error("expected a point with the time in seconds for row 0")
+	}
+
+	for i := 1; i < 5; i++ {
+		if p[i] != nil {
+			t.Errorf("expected nil point for row %d", i)
+		}
+		if bad[i] == nil {
+			t.Errorf("expected bad result for row %d", i)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"net/http"
+	"strconv"
+)
+
+/*
+The expected reporting interval (seconds) for a metric is set on the metric type and can be
+overridden for a device or site.  A metric summary with no value for longer than the interval is late.
+An interval of 0 means the metric is never late.
+*/
+
+// expectedInterval is the tables for the expected reporting intervals for a metric summary.
+type expectedInterval struct {
+	summary  string // the summary table e.g., field.metric_summary
+	typ      string // the type table e.g., field.type
+	override string // the per device or site overrides e.g., field.metric_interval
+	pk       string // the device or site primary key e.g., devicePK
+	idTable  string // the device or site table e.g., field.device
+	id       string // the device or site ID column and query parameter e.g., deviceID
+}
+
+var (
+	fieldMetricInterval = expectedInterval{summary: "field.metric_summary", typ: "field.type",
+		override: "field.metric_interval", pk: "devicePK", idTable: "field.device", id: "deviceID"}
+	dataLatencyInterval = expectedInterval{summary: "data.latency_summary", typ: "data.type",
+		override: "data.latency_interval", pk: "sitePK", idTable: "data.site", id: "siteID"}
+	dataCompletenessInterval = expectedInterval{summary: "data.completeness_summary", typ: "data.completeness_type",
+		override: "data.completeness_interval", pk: "sitePK", idTable: "data.site", id: "siteID"}
+)
+
+// intervalRow is an expected interval for a type (id is empty) or a device or site.
+type intervalRow struct {
+	id, typeID string
+	interval   int32
+}
+
+// late returns SQL for a column that is true if the summary is late.
+// The query must join the type table and LEFT JOIN the override table e.g.,
+// LEFT JOIN field.metric_interval USING (devicePK, typePK)
+func (e expectedInterval) late() string {
+	i := `COALESCE(` + e.override + `.expected_interval, ` + e.typ + `.expected_interval)`
+
+	return `(` + i + ` > 0 AND ` + e.summary + `.time < now() - ` + i + ` * interval '1 second')`
+}
+
+// put sets the interval for the type or, if the device or site ID is in the query, overrides it for the device or site.
+func (e expectedInterval) put(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	interval, err := strconv.Atoi(v.Get("interval"))
+	if err != nil || interval < 0 {
+		return weft.BadRequest("invalid interval")
+	}
+
+	var result sql.Result
+
+	if v.Get(e.id) == "" {
+		if result, err = db.Exec(`UPDATE `+e.typ+` SET expected_interval = $2 WHERE typeID = $1`,
+			v.Get("typeID"), interval); err != nil {
+			return weft.InternalServerError(err)
+		}
+	} else {
+		// return if insert succeeds or update if there is already an override.
+		if result, err = db.Exec(`INSERT INTO `+e.override+`(`+e.pk+`, typePK, expected_interval)
+				SELECT `+e.pk+`, typePK, $3
+				FROM `+e.idTable+`, `+e.typ+`
+				WHERE `+e.id+` = $1
+				AND typeID = $2`,
+			v.Get(e.id), v.Get("typeID"), interval); err != nil {
+			if pqErr, ok := err.(*pq.Error); !ok || pqErr.Code != errorUniqueViolation {
+				return weft.InternalServerError(err)
+			}
+
+			if result, err = db.Exec(`UPDATE `+e.override+` SET expected_interval = $3
+				WHERE `+e.pk+` = (SELECT `+e.pk+` FROM `+e.idTable+` WHERE `+e.id+` = $1)
+				AND typePK = (SELECT typePK FROM `+e.typ+` WHERE typeID = $2)`,
+				v.Get(e.id), v.Get("typeID"), interval); err != nil {
+				return weft.InternalServerError(err)
+			}
+		}
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return weft.InternalServerError(err)
+	}
+	if i != 1 {
+		return weft.BadRequest("Didn't create row, check your query parameters exist")
+	}
+
+	return &weft.StatusOK
+}
+
+// delete removes the override for the device or site.
+func (e expectedInterval) delete(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	if _, err := db.Exec(`DELETE FROM `+e.override+`
+			WHERE `+e.pk+` = (SELECT `+e.pk+` FROM `+e.idTable+` WHERE `+e.id+` = $1)
+			AND typePK = (SELECT typePK FROM `+e.typ+` WHERE typeID = $2)`,
+		v.Get(e.id), v.Get("typeID")); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// list returns the intervals for all types followed by the overrides.  If typeID is not
+// empty only intervals for typeID are returned.
+func (e expectedInterval) list(typeID string) ([]intervalRow, *weft.Result) {
+	rows, err := dbR.Query(`SELECT '', typeID, expected_interval, 0 AS o
+			FROM `+e.typ+`
+			WHERE $1 = '' OR typeID = $1
+			UNION ALL
+			SELECT `+e.id+`, typeID, `+e.override+`.expected_interval, 1 AS o
+			FROM `+e.override+`
+			JOIN `+e.idTable+` USING (`+e.pk+`)
+			JOIN `+e.typ+` USING (typePK)
+			WHERE $1 = '' OR typeID = $1
+			ORDER BY o, 2, 1`, typeID)
+	if err != nil {
+		return nil, weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var res []intervalRow
+
+	for rows.Next() {
+		var i intervalRow
+		var o int
+
+		if err = rows.Scan(&i.id, &i.typeID, &i.interval, &o); err != nil {
+			return nil, weft.InternalServerError(err)
+		}
+
+		res = append(res, i)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, weft.InternalServerError(err)
+	}
+
+	return res, &weft.StatusOK
+}
+
+func fieldMetricIntervalPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldMetricInterval.put(r)
+}
+
+func fieldMetricIntervalDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldMetricInterval.delete(r)
+}
+
+func fieldMetricIntervalProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	rows, res := fieldMetricInterval.list(r.URL.Query().Get("typeID"))
+	if !res.Ok {
+		return res
+	}
+
+	var ir mtrpb.FieldMetricIntervalResult
+
+	for _, v := range rows {
+		ir.Result = append(ir.Result, &mtrpb.FieldMetricInterval{DeviceID: v.id, TypeID: v.typeID, ExpectedInterval: v.interval})
+	}
+
+	by, err := proto.Marshal(&ir)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func dataLatencyIntervalPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataLatencyInterval.put(r)
+}
+
+func dataLatencyIntervalDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataLatencyInterval.delete(r)
+}
+
+func dataLatencyIntervalProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataIntervalProto(dataLatencyInterval, r, b)
+}
+
+func dataCompletenessIntervalPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataCompletenessInterval.put(r)
+}
+
+func dataCompletenessIntervalDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataCompletenessInterval.delete(r)
+}
+
+func dataCompletenessIntervalProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataIntervalProto(dataCompletenessInterval, r, b)
+}
+
+func dataIntervalProto(e expectedInterval, r *http.Request, b *bytes.Buffer) *weft.Result {
+	rows, res := e.list(r.URL.Query().Get("typeID"))
+	if !res.Ok {
+		return res
+	}
+
+	var ir mtrpb.DataIntervalResult
+
+	for _, v := range rows {
+		ir.Result = append(ir.Result, &mtrpb.DataInterval{SiteID: v.id, TypeID: v.typeID, ExpectedInterval: v.interval})
+	}
+
+	by, err := proto.Marshal(&ir)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
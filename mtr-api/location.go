@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+/*
+Devices and sites keep a history of their locations.  A location is in effect from its time until
+the next location.  The device or site table has the latest location which is used unless a map
+asks for the locations at another time.  Devices and sites added before there was a history have
+their location recorded from the epoch before it is first changed.
+*/
+
+// location is the tables for the location history of a device or site.
+type location struct {
+	table string // the location history table e.g., field.device_location
+	owner string // the table the locations are for e.g., field.device
+	pk    string // the primary key column in owner e.g., devicePK
+	id    string // the id column in owner and the query parameter for it e.g., deviceID
+}
+
+var (
+	fieldDeviceLocation = location{table: "field.device_location", owner: "field.device", pk: "devicePK", id: "deviceID"}
+	dataSiteLocation    = location{table: "data.site_location", owner: "data.site", pk: "sitePK", id: "siteID"}
+)
+
+/*
+at returns a subquery for the location of each device or site at the time in param with the columns
+pk and geom.  Devices or sites with no location history have never moved and use their latest location.
+It is a subquery so it must be aliased e.g.,
+JOIN `+fieldDeviceLocation.at("$3")+` AS location USING (devicePK)
+*/
+func (l location) at(param string) string {
+	return `(SELECT ` + l.pk + `, COALESCE((SELECT geom FROM ` + l.table + ` h
+			WHERE h.` + l.pk + ` = o.` + l.pk + ` AND h.time <= ` + param + `
+			ORDER BY h.time DESC LIMIT 1), o.geom) AS geom
+		FROM ` + l.owner + ` o)`
+}
+
+// parseTime returns the RFC3339 time from the query parameter time or now if it is empty.
+func parseTime(v url.Values) (time.Time, *weft.Result) {
+	if v.Get("time") == "" {
+		return time.Now().UTC(), &weft.StatusOK
+	}
+
+	t, err := time.Parse(time.RFC3339, v.Get("time"))
+	if err != nil {
+		return t, weft.BadRequest("invalid time")
+	}
+
+	return t, &weft.StatusOK
+}
+
+/*
+add records the location for the device or site id from time t.  The location is not recorded if
+it is the same as the location already in effect at t.  If there is no later location in the
+history the location becomes the latest location for the device or site.  If the device or site has
+no history its latest location is recorded from the epoch first.
+*/
+func (l location) add(id string, t time.Time, latitude, longitude float64) *weft.Result {
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var pk int
+
+	switch err = txn.QueryRow(`SELECT `+l.pk+` FROM `+l.owner+` WHERE `+l.id+` = $1`, id).Scan(&pk); err {
+	case nil:
+	case sql.ErrNoRows:
+		txn.Rollback()
+		return &weft.NotFound
+	default:
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	// the location before there was a history.
+	if _, err = txn.Exec(`INSERT INTO `+l.table+`(`+l.pk+`, time, latitude, longitude)
+			SELECT `+l.pk+`, 'epoch', latitude, longitude FROM `+l.owner+`
+			WHERE `+l.pk+` = $1
+			AND NOT EXISTS (SELECT 1 FROM `+l.table+` WHERE `+l.pk+` = $1)`, pk); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	var result sql.Result
+
+	if result, err = txn.Exec(`UPDATE `+l.table+` SET latitude = $3, longitude = $4
+			WHERE `+l.pk+` = $1 AND time = $2`, pk, t, latitude, longitude); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	if i == 0 {
+		if _, err = txn.Exec(`INSERT INTO `+l.table+`(`+l.pk+`, time, latitude, longitude)
+				SELECT $1, $2, $3, $4
+				WHERE NOT EXISTS (SELECT 1 FROM
+					(SELECT latitude, longitude FROM `+l.table+`
+					WHERE `+l.pk+` = $1 AND time <= $2
+					ORDER BY time DESC LIMIT 1) latest
+				WHERE latest.latitude = $3::NUMERIC(8,5) AND latest.longitude = $4::NUMERIC(8,5))`,
+			pk, t, latitude, longitude); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if _, err = txn.Exec(`UPDATE `+l.owner+` SET latitude = $3, longitude = $4
+			WHERE `+l.pk+` = $1
+			AND NOT EXISTS (SELECT 1 FROM `+l.table+` WHERE `+l.pk+` = $1 AND time > $2)`,
+		pk, t, latitude, longitude); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// create records the location of a new device or site id from time t.
+func (l location) create(id string, t time.Time) *weft.Result {
+	if _, err := db.Exec(`INSERT INTO `+l.table+`(`+l.pk+`, time, latitude, longitude)
+			SELECT `+l.pk+`, $2, latitude, longitude FROM `+l.owner+`
+			WHERE `+l.id+` = $1`, id, t); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// put records the location in the query from the optional time.
+func (l location) put(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	var err error
+	var latitude, longitude float64
+
+	if latitude, err = strconv.ParseFloat(v.Get("latitude"), 64); err != nil {
+		return weft.BadRequest("latitude invalid")
+	}
+
+	if longitude, err = strconv.ParseFloat(v.Get("longitude"), 64); err != nil {
+		return weft.BadRequest("longitude invalid")
+	}
+
+	t, res := parseTime(v)
+	if !res.Ok {
+		return res
+	}
+
+	return l.add(v.Get(l.id), t, latitude, longitude)
+}
+
+// history returns the locations for the device or site id in time order.
+func (l location) history(id string) (*sql.Rows, *weft.Result) {
+	var ok bool
+
+	if err := dbR.QueryRow(`SELECT EXISTS(SELECT 1 FROM `+l.owner+` WHERE `+l.id+` = $1)`, id).Scan(&ok); err != nil {
+		return nil, weft.InternalServerError(err)
+	}
+
+	if !ok {
+		return nil, &weft.NotFound
+	}
+
+	rows, err := dbR.Query(`SELECT time, latitude, longitude FROM `+l.table+`
+			WHERE `+l.pk+` = (SELECT `+l.pk+` FROM `+l.owner+` WHERE `+l.id+` = $1)
+			ORDER BY time ASC`, id)
+	if err != nil {
+		return nil, weft.InternalServerError(err)
+	}
+
+	return rows, &weft.StatusOK
+}
+
+func fieldDeviceLocationProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	deviceID := r.URL.Query().Get("deviceID")
+
+	rows, res := fieldDeviceLocation.history(deviceID)
+	if !res.Ok {
+		return res
+	}
+	defer rows.Close()
+
+	var fr mtrpb.FieldDeviceLocationResult
+	var t time.Time
+
+	for rows.Next() {
+		var d = mtrpb.FieldDeviceLocation{DeviceID: deviceID}
+
+		if err := rows.Scan(&t, &d.Latitude, &d.Longitude); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		d.Seconds = t.Unix()
+
+		fr.Result = append(fr.Result, &d)
+	}
+
+	by, err := proto.Marshal(&fr)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func dataSiteLocationProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	siteID := r.URL.Query().Get("siteID")
+
+	rows, res := dataSiteLocation.history(siteID)
+	if !res.Ok {
+		return res
+	}
+	defer rows.Close()
+
+	var dr mtrpb.DataSiteLocationResult
+	var t time.Time
+
+	for rows.Next() {
+		var d = mtrpb.DataSiteLocation{SiteID: siteID}
+
+		if err := rows.Scan(&t, &d.Latitude, &d.Longitude); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		d.Seconds = t.Unix()
+
+		dr.Result = append(dr.Result, &d)
+	}
+
+	by, err := proto.Marshal(&dr)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func fieldDeviceLocationPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldDeviceLocation.put(r)
+}
+
+func dataSiteLocationPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataSiteLocation.put(r)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/GeoNet/weft"
+	"github.com/lib/pq"
+	"net/http"
+	"strconv"
+)
+
+/*
+Types for metrics and states can be added, updated, and deleted with the API.  The typePK for
+an added type comes from a sequence starting at dynamicTypePK so that it doesn't clash with
+the types inserted by the DDL.  Deleting a type deletes all its data so a type that still
+has data is only deleted if the request forces it.
+*/
+
+// metricType is the tables for a type of metric or state.
+type metricType struct {
+	typ  string   // the type table e.g., field.type
+	seq  string   // the sequence for typePKs of added types e.g., field.type_seq
+	data []string // the tables with data for the type e.g., field.metric
+}
+
+var (
+	fieldMetricType = metricType{typ: "field.type", seq: "field.type_seq",
+		data: []string{"field.metric", "field.metric_hour", "field.metric_day", "field.metric_summary"}}
+	fieldStateType = metricType{typ: "field.state_type", seq: "field.state_type_seq",
+		data: []string{"field.state", "field.state_history"}}
+	dataLatencyType = metricType{typ: "data.type", seq: "data.type_seq",
+		data: []string{"data.latency", "data.latency_hour", "data.latency_day", "data.latency_summary"}}
+	dataCompletenessType = metricType{typ: "data.completeness_type", seq: "data.completeness_type_seq",
+		data: []string{"data.completeness", "data.completeness_hour", "data.completeness_day", "data.completeness_summary"}}
+	appType = metricType{typ: "app.type", seq: "app.type_seq",
+		data: []string{"app.counter", "app.counter_hour", "app.counter_day", "app.metric"}}
+)
+
+var typeHasData = weft.Result{Ok: false, Code: http.StatusConflict, Msg: "the type has data, use force=true to delete it and its data"}
+
+// validTypeID returns a bad request if typeID is empty or a number.
+func validTypeID(typeID string) *weft.Result {
+	if _, err := strconv.Atoi(typeID); err == nil || typeID == "" {
+		return weft.BadRequest("typeID must not be a number")
+	}
+
+	return &weft.StatusOK
+}
+
+// parseScale returns the scale from the query.  The scale must be greater than 0.
+func parseScale(s string) (float64, *weft.Result) {
+	scale, err := strconv.ParseFloat(s, 64)
+	if err != nil || scale <= 0.0 {
+		return 0.0, weft.BadRequest("invalid scale")
+	}
+
+	return scale, &weft.StatusOK
+}
+
+/*
+put adds the type for typeID or updates it if it exists.  cols are the columns in the type
+table for the values in args e.g., description.  cols may be empty for types that only have
+a typeID e.g., field.state_type.
+*/
+func (m metricType) put(typeID string, cols []string, args ...interface{}) *weft.Result {
+	var set, names, params string
+
+	for i, c := range cols {
+		p := "$" + strconv.Itoa(i+2)
+
+		if i > 0 {
+			set += ", "
+		}
+
+		set += c + " = " + p
+		names += ", " + c
+		params += ", " + p
+	}
+
+	args = append([]interface{}{typeID}, args...)
+
+	var err error
+
+	if len(cols) > 0 {
+		var result sql.Result
+
+		if result, err = db.Exec(`UPDATE `+m.typ+` SET `+set+` WHERE typeID = $1`, args...); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		var i int64
+		if i, err = result.RowsAffected(); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		if i == 1 {
+			return &weft.StatusOK
+		}
+	}
+
+	// nextval is only called if the type doesn't exist.
+	if _, err = db.Exec(`INSERT INTO `+m.typ+`(typePK, typeID`+names+`)
+				SELECT nextval('`+m.seq+`'), $1`+params+`
+				WHERE NOT EXISTS (SELECT 1 FROM `+m.typ+` WHERE typeID = $1)`, args...); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// added by a concurrent request.
+			return &weft.StatusOK
+		}
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// hasData returns true if there are any rows in the data tables for typePK.
+func (m metricType) hasData(typePK int) (bool, error) {
+	for _, t := range m.data {
+		var ok bool
+
+		if err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM `+t+` WHERE typePK = $1)`, typePK).Scan(&ok); err != nil {
+			return false, err
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+/*
+delete deletes the type for typeID from the query.  Data, thresholds, and tags for the type
+are deleted by cascade, ingest maps and retention overrides for the type are deleted as well.
+Unless force is true a type with data is not deleted.  Deleting a type that doesn't exist is
+not an error.
+*/
+func (m metricType) delete(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	typeID := v.Get("typeID")
+
+	var typePK int
+
+	switch err := db.QueryRow(`SELECT typePK FROM `+m.typ+` WHERE typeID = $1`, typeID).Scan(&typePK); err {
+	case nil:
+	case sql.ErrNoRows:
+		return &weft.StatusOK
+	default:
+		return weft.InternalServerError(err)
+	}
+
+	if v.Get("force") != "true" {
+		ok, err := m.hasData(typePK)
+		if err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		if ok {
+			return &typeHasData
+		}
+	}
+
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	if _, err = txn.Exec(`DELETE FROM `+m.typ+` WHERE typePK = $1`, typePK); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	for _, t := range m.data {
+		if _, err = txn.Exec(`DELETE FROM mtr.ingest_map WHERE tableName = $1 AND typeID = $2`, t, typeID); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+
+		if _, err = txn.Exec(`DELETE FROM mtr.retention WHERE tableName = $1 AND typeID = $2`, t, typeID); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
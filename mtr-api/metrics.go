@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/GeoNet/weft"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+The latest metrics are exported as gauges in the Prometheus text exposition format
+https://prometheus.io/docs/instrumenting/exposition_formats/
+
+Each gauge is labelled with the device or site, the type, and the tags (comma separated) on the metric.
+Thresholds are exported as their own gauges.
+*/
+
+// gauge is the query for a gauge.  The query selects the values for labels followed by the value.
+type gauge struct {
+	name, help string
+	labels     []string
+	query      string
+}
+
+// fieldTags and friends select the tags on a metric as a comma separated string.
+// They are correlated subqueries on the table aliased as m.
+const (
+	fieldTags = `(SELECT COALESCE(string_agg(tag, ',' ORDER BY tag), '') FROM field.metric_tag JOIN mtr.tag USING (tagPK)
+			WHERE devicePK = m.devicePK AND typePK = m.typePK)`
+	stateTags = `(SELECT COALESCE(string_agg(tag, ',' ORDER BY tag), '') FROM field.state_tag JOIN mtr.tag USING (tagPK)
+			WHERE devicePK = m.devicePK AND typePK = m.typePK)`
+	latencyTags = `(SELECT COALESCE(string_agg(tag, ',' ORDER BY tag), '') FROM data.latency_tag JOIN mtr.tag USING (tagPK)
+			WHERE sitePK = m.sitePK AND typePK = m.typePK)`
+	completenessTags = `(SELECT COALESCE(string_agg(tag, ',' ORDER BY tag), '') FROM data.completeness_tag JOIN mtr.tag USING (tagPK)
+			WHERE sitePK = m.sitePK AND typePK = m.typePK)`
+)
+
+var (
+	fieldLabels = []string{"deviceID", "modelID", "typeID", "tags"}
+	dataLabels  = []string{"siteID", "typeID", "tags"}
+)
+
+// fieldGauge returns the gauge for the column col from the table or subquery (aliased as m) which has devicePK and typePK.
+func fieldGauge(name, help, col, table string) gauge {
+	return gauge{name: name, help: help, labels: fieldLabels,
+		query: `SELECT deviceID, modelID, typeID, ` + fieldTags + `, ` + col + `
+			FROM ` + table + ` m
+			JOIN field.device USING (devicePK)
+			JOIN field.model USING (modelPK)
+			JOIN field.type USING (typePK)
+			WHERE status != 'retired'`}
+}
+
+// latencyGauge returns the gauge for the column col from the table or subquery (aliased as m) which has sitePK and typePK.
+func latencyGauge(name, help, col, table string) gauge {
+	return gauge{name: name, help: help, labels: dataLabels,
+		query: `SELECT siteID, typeID, ` + latencyTags + `, ` + col + `
+			FROM ` + table + ` m
+			JOIN data.site USING (sitePK)
+			JOIN data.type USING (typePK)
+			WHERE status != 'retired'`}
+}
+
+// noThreshold excludes thresholds with lower and upper 0 which means there is no threshold.
+const noThreshold = `NOT (lower = 0 AND upper = 0)`
+
+// where returns g with cond added to the query.
+func (g gauge) where(cond string) gauge {
+	g.query += `
+			AND ` + cond
+
+	return g
+}
+
+var gauges = []gauge{
+	fieldGauge("mtr_field_metric", "Latest field metric value in the unit of the metric type.",
+		"value", "field.metric_summary"),
+	fieldGauge("mtr_field_metric_threshold_lower", "Lower threshold for the field metric.",
+		"lower", fieldThreshold).where(noThreshold),
+	fieldGauge("mtr_field_metric_threshold_upper", "Upper threshold for the field metric.",
+		"upper", fieldThreshold).where(noThreshold),
+	{name: "mtr_field_state", help: "Latest field state (1 true, 0 false).", labels: fieldLabels,
+		query: `SELECT deviceID, modelID, typeID, ` + stateTags + `, CASE WHEN value THEN 1 ELSE 0 END
+			FROM field.state m
+			JOIN field.device USING (devicePK)
+			JOIN field.model USING (modelPK)
+			JOIN field.state_type USING (typePK)
+			WHERE status != 'retired'`},
+	latencyGauge("mtr_data_latency_mean", "Latest mean data latency in the unit of the latency type.",
+		"mean", "data.latency_summary"),
+	latencyGauge("mtr_data_latency_min", "Latest min data latency in the unit of the latency type.",
+		"min", "data.latency_summary"),
+	latencyGauge("mtr_data_latency_max", "Latest max data latency in the unit of the latency type.",
+		"max", "data.latency_summary"),
+	latencyGauge("mtr_data_latency_fifty", "Latest fiftieth percentile data latency in the unit of the latency type.",
+		"fifty", "data.latency_summary"),
+	latencyGauge("mtr_data_latency_ninety", "Latest ninetieth percentile data latency in the unit of the latency type.",
+		"ninety", "data.latency_summary"),
+	latencyGauge("mtr_data_latency_threshold_lower", "Lower threshold for the data latency.",
+		"lower", dataLatencyThreshold).where(noThreshold),
+	latencyGauge("mtr_data_latency_threshold_upper", "Upper threshold for the data latency.",
+		"upper", dataLatencyThreshold).where(noThreshold),
+	{name: "mtr_data_completeness_ratio", help: "Latest data completeness count as a ratio of the expected count for five minutes.",
+		labels: dataLabels,
+		query: `SELECT siteID, typeID, ` + completenessTags + `, count / (expected / 288.0)
+			FROM data.completeness_summary m
+			JOIN data.site USING (sitePK)
+			JOIN data.completeness_type USING (typePK)
+			WHERE expected > 0
+			AND status != 'retired'`},
+}
+
+func metricsText(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	for _, g := range gauges {
+		if err := g.write(b); err != nil {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	return &weft.StatusOK
+}
+
+// write writes the gauge to b in the text exposition format.
+func (g gauge) write(b *bytes.Buffer) error {
+	rows, err := dbR.Query(g.query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fmt.Fprintf(b, "# HELP %s %s\n", g.name, g.help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", g.name)
+
+	values := make([]string, len(g.labels))
+	dest := make([]interface{}, len(g.labels)+1)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var v float64
+	dest[len(g.labels)] = &v
+
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		b.WriteString(g.name)
+		b.WriteString(promLabels(g.labels, values))
+		b.WriteString(" ")
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		b.WriteString("\n")
+	}
+	rows.Close()
+
+	return rows.Err()
+}
+
+// promLabels formats names and values as a label set e.g., {siteID="TAUP",typeID="latency.strong"}
+func promLabels(names, values []string) string {
+	var l []string
+
+	for i := range names {
+		l = append(l, names[i]+`="`+promEscape(values[i])+`"`)
+	}
+
+	return "{" + strings.Join(l, ",") + "}"
+}
+
+// promEscaper escapes backslash, double-quote, and line feed in label values.
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promEscape(s string) string {
+	return promEscaper.Replace(s)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+// Label values are quoted and escaped.
+func TestPromLabels(t *testing.T) {
+	in := []struct {
+		names    []string
+		values   []string
+		expected string
+	}{
+		{[]string{"siteID", "typeID"}, []string{"TAUP", "latency.strong"}, `{siteID="TAUP",typeID="latency.strong"}`},
+		{[]string{"tags"}, []string{""}, `{tags=""}`},
+		{[]string{"deviceID"}, []string{`a"b\c` + "\n"}, `{deviceID="a\"b\\c\n"}`},
+	}
+
+	for _, v := range in {
+		if l := promLabels(v.names, v.values); l != v.expected {
+			t.Errorf("expected %s got %s", v.expected, l)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/GeoNet/mtr/mtrpb"
+	"io"
+	"log"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Notifications are sent to receivers when an alert is opened or closed.  Receivers are
+routed by tag with the rules in mtr.notify; every receiver for any tag on the metric
+is notified once.
+
+A receiver is one of:
+
+	http://... or https://... - webhook, the alert is POSTed as JSON.
+	mailto:ops@example.com - email via the SMTP server in the env var MTR_SMTP_SERVER (host:port).
+	    The sender is MTR_SMTP_FROM.  If MTR_SMTP_USER is set then MTR_SMTP_USER and MTR_SMTP_PASSWORD are used for auth.
+	file:///var/log/mtr/alert.log - a line is appended to the file for each alert.  The file must be
+	    in the directory in the env var MTR_NOTIFY_DIR e.g., /var/log/mtr.  File receivers are
+	    rejected if MTR_NOTIFY_DIR is not set.
+	stdout - a line is written to stdout for each alert.
+*/
+
+// notifyTimeout is how long delivery to a receiver is retried for.
+var notifyTimeout = 3 * time.Minute
+
+// notifyBackoff is the initial wait before retrying a failed delivery.  It doubles for each try.
+var notifyBackoff = time.Second
+
+var notifyClient = &http.Client{Timeout: 30 * time.Second}
+
+var (
+	smtpServer   = os.Getenv("MTR_SMTP_SERVER")
+	smtpFrom     = os.Getenv("MTR_SMTP_FROM")
+	smtpUser     = os.Getenv("MTR_SMTP_USER")
+	smtpPassword = os.Getenv("MTR_SMTP_PASSWORD")
+	notifyDir    = os.Getenv("MTR_NOTIFY_DIR")
+)
+
+// notifier sends a notification for an alert.
+type notifier interface {
+	notify(a *mtrpb.Alert) error
+}
+
+// webhook POSTs alerts as JSON to url.
+type webhook struct {
+	url string
+}
+
+// mailer emails alerts to the address to.
+type mailer struct {
+	server, from, to string
+	auth             smtp.Auth
+}
+
+// fileNotifier appends alerts to the file at path or writes them to w if it is not nil.
+type fileNotifier struct {
+	path string
+	w    io.Writer
+}
+
+// fileMu serialises writes from fileNotifiers.
+var fileMu sync.Mutex
+
+// dedupeWindow is how long the last alert sent to a receiver is kept for deduping.
+const dedupeWindow = time.Hour
+
+// sentAlert is an alert and when it was sent.
+type sentAlert struct {
+	alert mtrpb.Alert
+	at    time.Time
+}
+
+// sent is the last alert sent to each receiver for each metric.  Used to dedupe notifications.
+// Alerts older than dedupeWindow are evicted at most once a minute.
+var sent = struct {
+	sync.Mutex
+	alert  map[string]sentAlert
+	pruned time.Time
+}{alert: make(map[string]sentAlert)}
+
+// receivers finds the receivers for tags on the metric for each alert source.
+var receivers = map[string]string{
+	alertField: `SELECT DISTINCT receiver FROM mtr.notify
+			WHERE tagPK IN (SELECT tagPK FROM field.metric_tag
+				JOIN field.device USING (devicePK)
+				JOIN field.type USING (typePK)
+				WHERE deviceID = $1
+				AND typeID = $2)`,
+	alertLatency: `SELECT DISTINCT receiver FROM mtr.notify
+			WHERE tagPK IN (SELECT tagPK FROM data.latency_tag
+				JOIN data.site USING (sitePK)
+				JOIN data.type USING (typePK)
+				WHERE siteID = $1
+				AND typeID = $2)`,
+	alertCompleteness: `SELECT DISTINCT receiver FROM mtr.notify
+			WHERE tagPK IN (SELECT tagPK FROM data.completeness_tag
+				JOIN data.site USING (sitePK)
+				JOIN data.completeness_type USING (typePK)
+				WHERE siteID = $1
+				AND typeID = $2)`,
+}
+
+// newNotifier returns the notifier for receiver.
+func newNotifier(receiver string) (notifier, error) {
+	if receiver == "stdout" {
+		return &fileNotifier{w: os.Stdout}, nil
+	}
+
+	u, err := url.Parse(receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("no host in receiver %s", receiver)
+		}
+		return &webhook{url: receiver}, nil
+	case "mailto":
+		if u.Opaque == "" {
+			return nil, fmt.Errorf("no address in receiver %s", receiver)
+		}
+		if smtpServer == "" {
+			return nil, fmt.Errorf("MTR_SMTP_SERVER is not set for receiver %s", receiver)
+		}
+
+		m := &mailer{server: smtpServer, from: smtpFrom, to: u.Opaque}
+		if smtpUser != "" {
+			m.auth = smtp.PlainAuth("", smtpUser, smtpPassword, strings.Split(smtpServer, ":")[0])
+		}
+		return m, nil
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("no path in receiver %s", receiver)
+		}
+
+		p, err := notifyPath(u.Path)
+		if err != nil {
+			return nil, fmt.Errorf("receiver %s: %s", receiver, err)
+		}
+		return &fileNotifier{path: p}, nil
+	default:
+		return nil, fmt.Errorf("unknown receiver %s", receiver)
+	}
+}
+
+/*
+notifyPath returns the clean path for a file receiver.  The path must resolve (following any
+symlinks in its directory) to inside notifyDir.
+*/
+func notifyPath(path string) (string, error) {
+	if notifyDir == "" {
+		return "", fmt.Errorf("MTR_NOTIFY_DIR is not set")
+	}
+
+	dir, err := filepath.EvalSymlinks(notifyDir)
+	if err != nil {
+		return "", err
+	}
+
+	path = filepath.Clean(path)
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(dir, filepath.Join(parent, filepath.Base(path)))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not in MTR_NOTIFY_DIR", path)
+	}
+
+	return filepath.Join(dir, rel), nil
+}
+
+// alertState is opened or closed for a.
+func alertState(a *mtrpb.Alert) string {
+	if a.Closed == 0 {
+		return "opened"
+	}
+
+	return "closed"
+}
+
+// alertLine formats a as a single line of text e.g.,
+// 2016-09-20T01:02:00Z opened data.latency TAUP latency.strong value 10000
+func alertLine(a *mtrpb.Alert) string {
+	t := a.Opened
+	if a.Closed != 0 {
+		t = a.Closed
+	}
+
+	return fmt.Sprintf("%s %s %s %s %s value %g", time.Unix(t, 0).UTC().Format(time.RFC3339),
+		alertState(a), a.Source, a.ID, a.TypeID, a.Value)
+}
+
+func (w *webhook) notify(a *mtrpb.Alert) error {
+	by, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	res, err := notifyClient.Post(w.url, "application/json", bytes.NewReader(by))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("non 2xx code from webhook %s: %d", w.url, res.StatusCode)
+	}
+
+	return nil
+}
+
+func (m *mailer) notify(a *mtrpb.Alert) error {
+	var msg bytes.Buffer
+
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", m.to)
+	fmt.Fprintf(&msg, "Subject: mtr alert %s %s %s %s\r\n", alertState(a), a.Source, a.ID, a.TypeID)
+	fmt.Fprintf(&msg, "\r\n%s\r\n", alertLine(a))
+
+	return smtp.SendMail(m.server, m.auth, m.from, []string{m.to}, msg.Bytes())
+}
+
+func (f *fileNotifier) notify(a *mtrpb.Alert) error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	if f.w != nil {
+		_, err := fmt.Fprintln(f.w, alertLine(a))
+		return err
+	}
+
+	// don't follow a symlink out of MTR_NOTIFY_DIR.
+	if fi, err := os.Lstat(f.path); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%s is a symlink", f.path)
+	}
+
+	fi, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintln(fi, alertLine(a)); err != nil {
+		fi.Close()
+		return err
+	}
+
+	return fi.Close()
+}
+
+/*
+notifyAlert sends a to the receivers for any tags on the metric for k.
+Delivery is in the background.
+*/
+func notifyAlert(k alertKey, a *mtrpb.Alert) error {
+	q, ok := receivers[k.source]
+	if !ok {
+		return fmt.Errorf("unknown alert source %s", k.source)
+	}
+
+	var err error
+	var rows *sql.Rows
+
+	if rows, err = db.Query(q, k.id, k.typeID); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var rcv []string
+
+	for rows.Next() {
+		var r string
+
+		if err = rows.Scan(&r); err != nil {
+			return err
+		}
+
+		rcv = append(rcv, r)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range rcv {
+		n, err := newNotifier(r)
+		if err != nil {
+			log.Printf("skipping receiver: %s", err)
+			continue
+		}
+
+		if !dedupe(r, a) {
+			continue
+		}
+
+		go deliver(r, n, a)
+	}
+
+	return nil
+}
+
+// sentKey is the key in sent for a sent to receiver.
+func sentKey(receiver string, a *mtrpb.Alert) string {
+	return strings.Join([]string{receiver, a.Source, a.ID, a.TypeID}, " ")
+}
+
+/*
+dedupe returns true if a has not already been sent to receiver.  a is recorded as sent so that
+it is not delivered again while it is being delivered.  It is removed with unsend if delivery fails.
+*/
+func dedupe(receiver string, a *mtrpb.Alert) bool {
+	key := sentKey(receiver, a)
+
+	now := time.Now().UTC()
+
+	sent.Lock()
+	defer sent.Unlock()
+
+	if now.Sub(sent.pruned) > time.Minute {
+		pruneSent(now)
+	}
+
+	if s, ok := sent.alert[key]; ok && s.alert.Opened == a.Opened && s.alert.Closed == a.Closed {
+		return false
+	}
+
+	sent.alert[key] = sentAlert{alert: *a, at: now}
+
+	return true
+}
+
+// unsend removes a from sent for receiver so that it can be sent again.
+func unsend(receiver string, a *mtrpb.Alert) {
+	key := sentKey(receiver, a)
+
+	sent.Lock()
+	defer sent.Unlock()
+
+	if s, ok := sent.alert[key]; ok && s.alert.Opened == a.Opened && s.alert.Closed == a.Closed {
+		delete(sent.alert, key)
+	}
+}
+
+// pruneSent evicts alerts sent more than dedupeWindow before now.  sent must be locked.
+func pruneSent(now time.Time) {
+	for k, v := range sent.alert {
+		if now.Sub(v.at) > dedupeWindow {
+			delete(sent.alert, k)
+		}
+	}
+
+	sent.pruned = now
+}
+
+/*
+deliver sends a with n.  Failed deliveries are retried with backoff until notifyTimeout.  If delivery
+fails a is removed from sent so that it is not deduped.
+*/
+func deliver(receiver string, n notifier, a *mtrpb.Alert) error {
+	var err error
+
+	deadline := time.Now().Add(notifyTimeout)
+
+	for tries := 0; time.Now().Before(deadline); tries++ {
+		if err = n.notify(a); err == nil {
+			return nil
+		}
+		log.Printf("notifying %s failed (%s); backing off and retrying...", receiver, err)
+		time.Sleep(notifyBackoff << uint(tries))
+	}
+
+	log.Printf("giving up notifying %s: %s", receiver, err)
+
+	unsend(receiver, a)
+
+	return err
+}
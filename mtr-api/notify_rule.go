@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"net/http"
+)
+
+func notifyRulePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	if _, err := newNotifier(v.Get("receiver")); err != nil {
+		return weft.BadRequest(err.Error())
+	}
+
+	var err error
+	var result sql.Result
+
+	if result, err = db.Exec(`INSERT INTO mtr.notify(tagPK, receiver)
+				SELECT tagPK, $2
+				FROM mtr.tag
+				WHERE tag = $1`,
+		v.Get("tag"), v.Get("receiver")); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			// ignore unique constraint errors
+			return &weft.StatusOK
+		} else {
+			return weft.InternalServerError(err)
+		}
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return weft.InternalServerError(err)
+	}
+	if i != 1 {
+		return weft.BadRequest("Didn't create row, check your query parameters exist")
+	}
+
+	return &weft.StatusOK
+}
+
+func notifyRuleDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	if _, err := db.Exec(`DELETE FROM mtr.notify
+			WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
+			AND receiver = $2`,
+		v.Get("tag"), v.Get("receiver")); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func notifyRuleProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	var err error
+	var rows *sql.Rows
+
+	switch tag := r.URL.Query().Get("tag"); tag {
+	case "":
+		rows, err = dbR.Query(`SELECT tag, receiver FROM mtr.notify
+				JOIN mtr.tag USING (tagPK)
+				ORDER BY tag ASC, receiver ASC`)
+	default:
+		rows, err = dbR.Query(`SELECT tag, receiver FROM mtr.notify
+				JOIN mtr.tag USING (tagPK)
+				WHERE tag = $1
+				ORDER BY receiver ASC`, tag)
+	}
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	var nr mtrpb.NotifyRuleResult
+
+	for rows.Next() {
+		var n mtrpb.NotifyRule
+
+		if err = rows.Scan(&n.Tag, &n.Receiver); err != nil {
+			return weft.InternalServerError(err)
+		}
+
+		nr.Result = append(nr.Result, &n)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var by []byte
+	if by, err = proto.Marshal(&nr); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
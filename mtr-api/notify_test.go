@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/GeoNet/mtr/mtrpb"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Alerts are POSTed as JSON to a webhook.  Failed deliveries are retried.
+func TestNotifyWebhook(t *testing.T) {
+	notifyBackoff = time.Millisecond
+
+	var mu sync.Mutex
+	var got []mtrpb.Alert
+	var tries int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		tries++
+
+		// fail the first try.
+		if tries == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Method != "POST" {
+			t.Errorf("expected POST got %s", r.Method)
+		}
+
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected Content-Type application/json got %s", r.Header.Get("Content-Type"))
+		}
+
+		var a mtrpb.Alert
+
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Error(err)
+		}
+
+		got = append(got, a)
+	}))
+	defer ts.Close()
+
+	n, err := newNotifier(ts.URL + "/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := mtrpb.Alert{Source: alertLatency, ID: "TAUP", TypeID: "latency.strong", Opened: 1463262030, Value: 10000}
+
+	if err = deliver(ts.URL, n, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if tries != 2 {
+		t.Errorf("expected 2 tries got %d", tries)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 alert got %d", len(got))
+	}
+
+	if got[0] != a {
+		t.Errorf("expected %v got %v", a, got[0])
+	}
+}
+
+func TestNotifyFile(t *testing.T) {
+	var b bytes.Buffer
+
+	n := &fileNotifier{w: &b}
+
+	if err := n.notify(&mtrpb.Alert{Source: alertLatency, ID: "TAUP", TypeID: "latency.strong", Opened: 1463262030, Value: 10000}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.notify(&mtrpb.Alert{Source: alertLatency, ID: "TAUP", TypeID: "latency.strong", Opened: 1463262030, Closed: 1463262090, Value: 10000}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "2016-05-14T21:40:30Z opened data.latency TAUP latency.strong value 10000\n" +
+		"2016-05-14T21:41:30Z closed data.latency TAUP latency.strong value 10000\n"
+
+	if b.String() != expected {
+		t.Errorf("expected %q got %q", expected, b.String())
+	}
+
+	dir, err := ioutil.TempDir("", "mtr-notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	notifyDir = dir
+	defer func() { notifyDir = "" }()
+
+	f, err := ioutil.TempFile(dir, "mtr-alert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if n, err := newNotifier("file://" + f.Name()); err != nil {
+		t.Fatal(err)
+	} else if err = n.notify(&mtrpb.Alert{Source: alertField, ID: "gps-taupoairport", TypeID: "voltage", Opened: 1463262030, Value: 12000}); err != nil {
+		t.Fatal(err)
+	}
+
+	var by []byte
+	if by, err = ioutil.ReadFile(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(by), "opened field.metric gps-taupoairport voltage value 12000") {
+		t.Errorf("unexpected file content %q", string(by))
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtr-notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = os.Symlink("/tmp", filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	in := []struct {
+		receiver string
+		ok       bool
+	}{
+		{"stdout", true},
+		{"http://localhost:8080/hook", true},
+		{"https://example.com/hook", true},
+		{"file://" + dir + "/mtr-alert.log", true},
+		{"file://" + dir + "/../mtr-alert.log", false},
+		{"file://" + dir, false},
+		{"file://" + dir + "/escape/mtr-alert.log", false},
+		{"file:///etc/passwd", false},
+		{"https:///hook", false},
+		{"ftp://example.com", false},
+		{"not a url", false},
+		{"", false},
+	}
+
+	notifyDir = dir
+	defer func() { notifyDir = "" }()
+
+	for _, v := range in {
+		if _, err := newNotifier(v.receiver); (err == nil) != v.ok {
+			t.Errorf("%s: expected ok %t got %v", v.receiver, v.ok, err)
+		}
+	}
+
+	// file receivers are rejected unless MTR_NOTIFY_DIR is set.
+	notifyDir = ""
+
+	if _, err := newNotifier("file://" + dir + "/mtr-alert.log"); err == nil {
+		t.Error("expected error for a file receiver without MTR_NOTIFY_DIR")
+	}
+}
+
+// The same alert transition is only sent once to each receiver.
+func TestNotifyDedupe(t *testing.T) {
+	a := mtrpb.Alert{Source: alertLatency, ID: "DEDUPE", TypeID: "latency.strong", Opened: 1463262030}
+
+	if !dedupe("stdout", &a) {
+		t.Error("expected first notification to be sent")
+	}
+
+	if dedupe("stdout", &a) {
+		t.Error("expected repeat notification to be deduped")
+	}
+
+	if !dedupe("https://example.com/hook", &a) {
+		t.Error("expected notification for a different receiver to be sent")
+	}
+
+	a.Closed = 1463262090
+
+	if !dedupe("stdout", &a) {
+		t.Error("expected close notification to be sent")
+	}
+
+	// an alert that fails delivery is not deduped.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	n, err := newNotifier(ts.URL + "/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := notifyTimeout
+	notifyTimeout = 10 * time.Millisecond
+	defer func() { notifyTimeout = timeout }()
+
+	if !dedupe(ts.URL+"/hook", &a) {
+		t.Error("expected first notification to be sent")
+	}
+
+	if err = deliver(ts.URL+"/hook", n, &a); err == nil {
+		t.Error("expected error for failed delivery")
+	}
+
+	if !dedupe(ts.URL+"/hook", &a) {
+		t.Error("expected notification to be sent again after failed delivery")
+	}
+
+	// sent alerts are evicted after the dedupe window.
+	sent.Lock()
+	pruneSent(time.Now().UTC().Add(dedupeWindow + time.Minute))
+	l := len(sent.alert)
+	sent.Unlock()
+
+	if l != 0 {
+		t.Errorf("expected sent alerts to be evicted got %d", l)
+	}
+}
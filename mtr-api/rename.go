@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"github.com/GeoNet/weft"
+	"github.com/lib/pq"
+	"net/http"
+)
+
+/*
+Devices and sites are renamed in place so that their metrics, thresholds, tags, attributes, and
+locations (which all use the primary key) are kept.  Alerts store the ID as text so they are
+updated to the new ID as well.
+*/
+
+var idExists = weft.Result{Ok: false, Code: http.StatusConflict, Msg: "the new ID already exists"}
+
+// rename is the tables to rename a device or site in.
+type rename struct {
+	owner   string   // the table to rename in e.g., field.device
+	id      string   // the id column in owner and the query parameter for the current id e.g., deviceID
+	newID   string   // the query parameter for the new id e.g., newDeviceID
+	sources []string // the alert sources for the id e.g., field.metric
+}
+
+var (
+	fieldDeviceRename = rename{owner: "field.device", id: "deviceID", newID: "newDeviceID",
+		sources: []string{alertField}}
+	dataSiteRename = rename{owner: "data.site", id: "siteID", newID: "newSiteID",
+		sources: []string{alertLatency, alertCompleteness}}
+)
+
+// put renames the id in the query to the new id.
+func (n rename) put(r *http.Request) *weft.Result {
+	v := r.URL.Query()
+
+	id := v.Get(n.id)
+	newID := v.Get(n.newID)
+
+	if newID == "" {
+		return weft.BadRequest("empty " + n.newID)
+	}
+
+	if newID == id {
+		return &weft.StatusOK
+	}
+
+	txn, err := db.Begin()
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	result, err := txn.Exec(`UPDATE `+n.owner+` SET `+n.id+` = $2 WHERE `+n.id+` = $1`, id, newID)
+	if err != nil {
+		txn.Rollback()
+		if err, ok := err.(*pq.Error); ok && err.Code == errorUniqueViolation {
+			return &idExists
+		}
+		return weft.InternalServerError(err)
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	if i != 1 {
+		txn.Rollback()
+		return &weft.NotFound
+	}
+
+	for _, s := range n.sources {
+		if _, err = txn.Exec(`UPDATE mtr.alert SET ID = $3 WHERE source = $1 AND ID = $2`, s, id, newID); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func fieldDeviceRenamePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return fieldDeviceRename.put(r)
+}
+
+func dataSiteRenamePut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	return dataSiteRename.put(r)
+}
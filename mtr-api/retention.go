@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/GeoNet/weft"
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/*
+Retention policies are stored in mtr.retention.  Each policy is the number of days to keep
+rows in a table.  A policy with typeID '' is the default for all types in the table and policies
+with a typeID override the default for that type.  Rows are deleted in batches of deleteRows so that
+the hot tables are not locked for long.
+*/
+
+// deleteRows is the number of rows deleted in each batch.
+const deleteRows = 10000
+
+// deletePause is the time between batches.
+const deletePause = 100 * time.Millisecond
+
+// retentionTables are the tables that can have a retention policy and the type table for each of them.
+// Policies for a typeID can't be set for tables without a type table.
+var retentionTables = map[string]string{
+	"field.metric":              "field.type",
+	"field.metric_summary":      "field.type",
+	"field.metric_hour":         "field.type",
+	"field.metric_day":          "field.type",
+	"field.state_history":       "field.state_type",
+	"data.latency":              "data.type",
+	"data.latency_summary":      "data.type",
+	"data.latency_hour":         "data.type",
+	"data.latency_day":          "data.type",
+	"data.completeness":         "data.completeness_type",
+	"data.completeness_summary": "data.completeness_type",
+	"data.completeness_hour":    "data.completeness_type",
+	"data.completeness_day":     "data.completeness_type",
+	"app.metric":                "app.type",
+	"app.counter":               "app.type",
+	"app.counter_hour":          "app.type",
+	"app.counter_day":           "app.type",
+	"app.timer":                 "",
+	"app.timer_hour":            "",
+	"app.timer_day":             "",
+}
+
+type retentionPolicy struct {
+	table, typeID string
+	days          int
+	requireRollup bool
+}
+
+// retentionStats for the last run of a policy.
+type retentionStats struct {
+	deleted int64
+	taken   time.Duration
+}
+
+// TODO delete app instance and time source that have no metrics?
+
+/*
+deleteMetrics deletes old metrics every minute using the policies in mtr.retention.
+*/
+func deleteMetrics() {
+	ticker := time.NewTicker(time.Minute).C
+	for {
+		select {
+		case <-ticker:
+			if err := applyRetention(time.Now().UTC()); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+/*
+applyRetention deletes rows older than the retention policies at now and
+saves the statistics for the run of each policy.
+*/
+func applyRetention(now time.Time) error {
+	rows, err := db.Query(`SELECT tableName, typeID, days, require_rollup FROM mtr.retention`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// policies for each table keyed by typeID.
+	policies := make(map[string]map[string]retentionPolicy)
+
+	for rows.Next() {
+		var p retentionPolicy
+
+		if err = rows.Scan(&p.table, &p.typeID, &p.days, &p.requireRollup); err != nil {
+			return err
+		}
+
+		if _, ok := retentionTables[p.table]; !ok {
+			log.Printf("skipping retention policy for unknown table %s", p.table)
+			continue
+		}
+
+		if policies[p.table] == nil {
+			policies[p.table] = make(map[string]retentionPolicy)
+		}
+		policies[p.table][p.typeID] = p
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for table, typ := range retentionTables {
+		tp, ok := policies[table]
+		if !ok {
+			continue
+		}
+
+		stats := make(map[string]*retentionStats)
+
+		if typ == "" {
+			if p, ok := tp[""]; ok {
+				s := &retentionStats{}
+				if err = p.apply(now, "", 0, s); err != nil {
+					return err
+				}
+				stats[""] = s
+			}
+		} else {
+			// apply the override or default policy for each type.
+			var types map[string]int
+			if types, err = typePKs(typ); err != nil {
+				return err
+			}
+
+			for typeID, typePK := range types {
+				p, ok := tp[typeID]
+				if !ok {
+					if p, ok = tp[""]; !ok {
+						continue
+					}
+				}
+
+				s := stats[p.typeID]
+				if s == nil {
+					s = &retentionStats{}
+					stats[p.typeID] = s
+				}
+
+				if err = p.apply(now, "typePK", typePK, s); err != nil {
+					return err
+				}
+			}
+		}
+
+		for typeID, s := range stats {
+			if _, err = db.Exec(`UPDATE mtr.retention SET last_run = $3, last_deleted = $4, last_taken = $5
+					WHERE tableName = $1
+					AND typeID = $2`, table, typeID, now, s.deleted, int64(s.taken/time.Millisecond)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// typePKs returns the typePK for each typeID in the type table typ.
+func typePKs(typ string) (map[string]int, error) {
+	rows, err := db.Query(`SELECT typeID, typePK FROM ` + typ)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make(map[string]int)
+
+	for rows.Next() {
+		var typeID string
+		var typePK int
+
+		if err = rows.Scan(&typeID, &typePK); err != nil {
+			return nil, err
+		}
+
+		types[typeID] = typePK
+	}
+	rows.Close()
+
+	return types, rows.Err()
+}
+
+/*
+apply deletes rows older than the policy in batches and adds the number of rows deleted
+and the time taken to s.  If col is not empty only rows with col = val are deleted.
+*/
+func (p retentionPolicy) apply(now time.Time, col string, val int, s *retentionStats) error {
+	start := time.Now()
+	defer func() { s.taken += time.Since(start) }()
+
+	cutoff := now.Add(time.Duration(p.days) * -24 * time.Hour)
+
+	if p.requireRollup {
+		var rolled time.Time
+
+		err := db.QueryRow(`SELECT time FROM mtr.rollup WHERE tableName = $1`, p.table).Scan(&rolled)
+		switch err {
+		case nil:
+			if rolled.Before(cutoff) {
+				cutoff = rolled
+			}
+		case sql.ErrNoRows:
+			// nothing has been rolled up.
+			return nil
+		default:
+			return err
+		}
+
+		// keep rows in hours that are waiting to be rolled up again.
+		var dirty pq.NullTime
+
+		if err = db.QueryRow(`SELECT min(time) FROM mtr.rollup_dirty WHERE tableName = $1`, p.table).Scan(&dirty); err != nil {
+			return err
+		}
+
+		if dirty.Valid && dirty.Time.Before(cutoff) {
+			cutoff = dirty.Time
+		}
+	}
+
+	q := `DELETE FROM ` + p.table + ` WHERE ctid = ANY(ARRAY(SELECT ctid FROM ` + p.table + `
+			WHERE time < $1 LIMIT $2))`
+	args := []interface{}{cutoff, deleteRows}
+
+	if col != "" {
+		q = `DELETE FROM ` + p.table + ` WHERE ctid = ANY(ARRAY(SELECT ctid FROM ` + p.table + `
+			WHERE time < $1 AND ` + col + ` = $3 LIMIT $2))`
+		args = append(args, val)
+	}
+
+	for {
+		result, err := db.Exec(q, args...)
+		if err != nil {
+			return err
+		}
+
+		var n int64
+		if n, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		s.deleted += n
+
+		if n < deleteRows {
+			return nil
+		}
+
+		time.Sleep(deletePause)
+	}
+}
+
+func retentionPut(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	table := v.Get("table")
+	typeID := v.Get("typeID")
+
+	typ, ok := retentionTables[table]
+	if !ok {
+		return weft.BadRequest("unknown table")
+	}
+
+	days, err := strconv.Atoi(v.Get("days"))
+	if err != nil || days < 1 {
+		return weft.BadRequest("invalid days")
+	}
+
+	var requireRollup bool
+	if v.Get("requireRollup") != "" {
+		if requireRollup, err = strconv.ParseBool(v.Get("requireRollup")); err != nil {
+			return weft.BadRequest("invalid requireRollup")
+		}
+	}
+
+	if typeID != "" {
+		if typ == "" {
+			return weft.BadRequest(fmt.Sprintf("%s does not have types", table))
+		}
+
+		var n int
+		if err = db.QueryRow(`SELECT count(*) FROM `+typ+` WHERE typeID = $1`, typeID).Scan(&n); err != nil {
+			return weft.InternalServerError(err)
+		}
+		if n != 1 {
+			return weft.BadRequest("unknown typeID")
+		}
+	}
+
+	var txn *sql.Tx
+	if txn, err = db.Begin(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	var result sql.Result
+	if result, err = txn.Exec(`UPDATE mtr.retention SET days = $3, require_rollup = $4
+			WHERE tableName = $1
+			AND typeID = $2`, table, typeID, days, requireRollup); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		txn.Rollback()
+		return weft.InternalServerError(err)
+	}
+
+	if i == 0 {
+		if _, err = txn.Exec(`INSERT INTO mtr.retention(tableName, typeID, days, require_rollup) VALUES($1, $2, $3, $4)`,
+			table, typeID, days, requireRollup); err != nil {
+			txn.Rollback()
+			return weft.InternalServerError(err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+func retentionDelete(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	v := r.URL.Query()
+
+	if _, err := db.Exec(`DELETE FROM mtr.retention WHERE tableName = $1 AND typeID = $2`,
+		v.Get("table"), v.Get("typeID")); err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	return &weft.StatusOK
+}
+
+// retentionPolicies returns the retention policies and statistics for the last run of each.
+func retentionPolicies() (mtrpb.RetentionPolicyResult, *weft.Result) {
+	var rr mtrpb.RetentionPolicyResult
+
+	rows, err := dbR.Query(`SELECT tableName, typeID, days, require_rollup, last_run, last_deleted, last_taken
+			FROM mtr.retention
+			ORDER BY tableName ASC, typeID ASC`)
+	if err != nil {
+		return rr, weft.InternalServerError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p mtrpb.RetentionPolicy
+		var lastRun pq.NullTime
+
+		if err = rows.Scan(&p.Table, &p.TypeID, &p.Days, &p.RequireRollup, &lastRun, &p.LastDeleted, &p.LastTaken); err != nil {
+			return rr, weft.InternalServerError(err)
+		}
+
+		if lastRun.Valid {
+			p.LastRun = lastRun.Time.Unix()
+		}
+
+		rr.Result = append(rr.Result, &p)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return rr, weft.InternalServerError(err)
+	}
+
+	return rr, &weft.StatusOK
+}
+
+func retentionProto(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	rr, res := retentionPolicies()
+	if !res.Ok {
+		return res
+	}
+
+	by, err := proto.Marshal(&rr)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
+
+func retentionJSON(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
+	rr, res := retentionPolicies()
+	if !res.Ok {
+		return res
+	}
+
+	by, err := json.Marshal(&rr)
+	if err != nil {
+		return weft.InternalServerError(err)
+	}
+
+	b.Write(by)
+
+	return &weft.StatusOK
+}
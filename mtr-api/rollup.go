@@ -0,0 +1,446 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"log"
+	"strings"
+	"time"
+)
+
+/*
+Metrics are rolled up into hourly and daily tables for long term history e.g., field.metric is rolled
+up into field.metric_hour and field.metric_day.  Each hour or day is rolled up once it is rollupDelay old
+so that late metrics are included.  The time that each rollup table has been rolled up to is stored in
+mtr.rollup along with the earliest of these for the metric table (used by retention policies
+with require_rollup).
+
+Rows in the metric table from the rolled up time have not been rolled up yet so queries for an hour
+or day resolution read the rollup table up to that time and the metric table after it (see rolledUp).
+
+Rows added to an hour after it has finished (e.g., a backfill after a comms outage) are marked in
+mtr.rollup_dirty by a trigger on the metric table.  Dirty hours, and their days, that have already
+been rolled up are rolled up again before rolling up forward (see reroll).
+*/
+
+// rollupDelay is how long after the end of an hour or day before it is rolled up.
+const rollupDelay = 10 * time.Minute
+
+// rollupBuckets is the maximum number of hours or days rolled up for each table in a run.
+// Limits the time spent catching up on a new or idle table.
+const rollupBuckets = 24
+
+// rollupResolutions are the resolutions that are rolled up.  Tables are named for them e.g., field.metric_hour
+var rollupResolutions = []string{"hour", "day"}
+
+// rollup is the SQL to roll up a metric table.
+type rollup struct {
+	table string // the metric table e.g., field.metric
+	keys  string // the key columns e.g., devicePK, typePK
+	cols  string // the rollup columns after the key and time columns.
+	aggs  string // the aggregates of the metric columns for cols.
+	// after is called in the rollup transaction after the rollup rows for the bucket from, to have
+	// been added to rt.  For aggregates that can't be done in SQL.  Optional.
+	after func(txn *sql.Tx, rt string, from, to time.Time) error
+}
+
+var rollups = []rollup{
+	{table: "field.metric", keys: "devicePK, typePK",
+		cols: "min, max, avg, count", aggs: "min(value), max(value), avg(value), count(*)"},
+	{table: "data.latency", keys: "sitePK, typePK",
+		cols: "min, max, avg, count, fifty, ninety", aggs: "min(min), max(max), avg(mean), count(*), max(fifty), max(ninety)"},
+	{table: "data.completeness", keys: "sitePK, typePK",
+		cols: "min, max, avg, count", aggs: "min(count), max(count), avg(count), count(*)"},
+	{table: "app.counter", keys: "applicationPK, instancePK, typePK",
+		cols: "min, max, avg, count", aggs: "min(count), max(count), avg(count), count(*)"},
+	{table: "app.timer", keys: "applicationPK, instancePK, sourcePK",
+		cols: "min, max, avg, count, fifty, ninety", aggs: "COALESCE(min(min), min(average)), COALESCE(max(max), max(average)), avg(average), sum(count), max(fifty), max(ninety)",
+		after: rollupTimerSketches},
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+/*
+rollupMetrics rolls up metrics every minute.
+*/
+func rollupMetrics() {
+	ticker := time.NewTicker(time.Minute).C
+	for {
+		select {
+		case <-ticker:
+			if err := rollupAll(time.Now().UTC()); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+/*
+rollupAll rolls up the hours and days for all metric tables that had finished rollupDelay before now.
+An error rolling up a table is logged and the other tables are still rolled up.  The error returned
+lists the tables that failed.
+*/
+func rollupAll(now time.Time) error {
+	var failed []string
+
+	for _, r := range rollups {
+		if err := r.rollupTable(now); err != nil {
+			log.Printf("rolling up %s: %s", r.table, err)
+			failed = append(failed, r.table)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("rollup failed for %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// rollupTable rolls up the hours and days for r that had finished rollupDelay before now.
+func (r rollup) rollupTable(now time.Time) error {
+	if err := r.reroll(); err != nil {
+		return err
+	}
+
+	for _, res := range rollupResolutions {
+		if err := r.roll(res, now); err != nil {
+			return err
+		}
+	}
+
+	// the metric table has been rolled up to the earliest of its rollups.
+	var t pq.NullTime
+
+	if err := db.QueryRow(`SELECT min(time) FROM mtr.rollup
+			WHERE tableName IN ($1, $2)
+			HAVING count(*) = $3`,
+		r.table+"_"+rollupResolutions[0], r.table+"_"+rollupResolutions[1], len(rollupResolutions)).Scan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return setRollup(db, r.table, t.Time)
+}
+
+// truncate returns t truncated to the start of the hour or day (UTC).
+func truncate(t time.Time, resolution string) time.Time {
+	t = t.UTC()
+
+	if resolution == "day" {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return t.Truncate(time.Hour)
+}
+
+// next returns the start of the hour or day after t.
+func next(t time.Time, resolution string) time.Time {
+	if resolution == "day" {
+		return t.AddDate(0, 0, 1)
+	}
+
+	return t.Add(time.Hour)
+}
+
+/*
+roll rolls up to rollupBuckets hours or days (resolution) for r that had finished rollupDelay before now.
+If the table has not been rolled up before it is rolled up from the earliest metric.
+*/
+func (r rollup) roll(resolution string, now time.Time) error {
+	rt := r.table + "_" + resolution
+
+	var from time.Time
+
+	err := db.QueryRow(`SELECT time FROM mtr.rollup WHERE tableName = $1`, rt).Scan(&from)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		var first pq.NullTime
+
+		if err = db.QueryRow(`SELECT min(time) FROM ` + r.table).Scan(&first); err != nil {
+			return err
+		}
+
+		if !first.Valid {
+			// nothing to roll up.
+			return nil
+		}
+
+		from = truncate(first.Time, resolution)
+	default:
+		return err
+	}
+
+	to := truncate(now.Add(-rollupDelay), resolution)
+
+	for i := 0; i < rollupBuckets && from.Before(to); i++ {
+		t := next(from, resolution)
+
+		var done bool
+		if done, err = r.bucket(rt, from, t); err != nil {
+			return err
+		}
+
+		// another instance is rolling up this table.
+		if !done {
+			return nil
+		}
+
+		from = t
+	}
+
+	return nil
+}
+
+/*
+bucket rolls up metrics from the time from until to into the rollup table rt and saves to as the time
+rt has been rolled up to.  Returns false if the bucket was rolled up at the same time by another instance.
+*/
+func (r rollup) bucket(rt string, from, to time.Time) (bool, error) {
+	txn, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.aggregate(txn, rt, from, to); err != nil {
+		txn.Rollback()
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err = setRollup(txn, rt, to); err != nil {
+		txn.Rollback()
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err = txn.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// aggregate replaces the rows in the rollup table rt for the bucket from until to with the rollup of the metrics.
+func (r rollup) aggregate(txn *sql.Tx, rt string, from, to time.Time) error {
+	if _, err := txn.Exec(`DELETE FROM `+rt+` WHERE time = $1`, from); err != nil {
+		return err
+	}
+
+	if _, err := txn.Exec(`INSERT INTO `+rt+`(`+r.keys+`, time, `+r.cols+`)
+			SELECT `+r.keys+`, $1, `+r.aggs+`
+			FROM `+r.table+`
+			WHERE time >= $1 AND time < $2
+			GROUP BY `+r.keys, from, to); err != nil {
+		return err
+	}
+
+	if r.after != nil {
+		return r.after(txn, rt, from, to)
+	}
+
+	return nil
+}
+
+/*
+reroll rolls up again up to rollupBuckets of the dirty hours for r (see mtr.rollup_dirty) along with
+their days.  Hours and days that have not been rolled up yet are left for roll.
+*/
+func (r rollup) reroll() error {
+	rows, err := db.Query(`SELECT time FROM mtr.rollup_dirty WHERE tableName = $1 ORDER BY time ASC LIMIT $2`,
+		r.table, rollupBuckets)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var hours []time.Time
+
+	for rows.Next() {
+		var h time.Time
+
+		if err = rows.Scan(&h); err != nil {
+			return err
+		}
+
+		hours = append(hours, h)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// the buckets rolled up again in this run.  A day with more than one dirty hour is only
+	// rolled up once as rows for all the dirty hours had been added before the run.
+	done := make(map[string]bool)
+
+	for _, h := range hours {
+		if err = r.rerollHour(h, done); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+rerollHour rolls up the dirty hour h, and its day, again if they have already been rolled up and
+clears h from mtr.rollup_dirty.  The dirty hour is cleared before the metrics are read so that any
+rows added while it is being rolled up mark it dirty again.
+*/
+func (r rollup) rerollHour(h time.Time, done map[string]bool) error {
+	txn, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	result, err := txn.Exec(`DELETE FROM mtr.rollup_dirty WHERE tableName = $1 AND time = $2`, r.table, h)
+	if err != nil {
+		txn.Rollback()
+		return err
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		txn.Rollback()
+		return err
+	}
+
+	// another instance is rolling up this hour.
+	if i == 0 {
+		txn.Rollback()
+		return nil
+	}
+
+	for _, res := range rollupResolutions {
+		rt := r.table + "_" + res
+
+		var rolled time.Time
+
+		switch err = txn.QueryRow(`SELECT time FROM mtr.rollup WHERE tableName = $1`, rt).Scan(&rolled); err {
+		case nil:
+		case sql.ErrNoRows:
+			continue
+		default:
+			txn.Rollback()
+			return err
+		}
+
+		from := truncate(h, res)
+		to := next(from, res)
+
+		if to.After(rolled) || done[rt+from.String()] {
+			continue
+		}
+
+		if err = r.aggregate(txn, rt, from, to); err != nil {
+			txn.Rollback()
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == errorUniqueViolation {
+				// rolled up at the same time by another instance, the hour is still dirty.
+				return nil
+			}
+			return err
+		}
+
+		done[rt+from.String()] = true
+	}
+
+	return txn.Commit()
+}
+
+/*
+rollupTimerSketches merges the app.timer sketches for each application, instance, and source from
+the time from until to and saves the merged sketch and percentiles in the rollup table rt.  If any
+timer does not have a sketch the rollup row is left with the max of the timer percentiles.
+*/
+func rollupTimerSketches(txn *sql.Tx, rt string, from, to time.Time) error {
+	rows, err := txn.Query(`SELECT applicationPK, instancePK, sourcePK, sketch
+			FROM app.timer
+			WHERE time >= $1 AND time < $2`, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type key struct {
+		applicationPK, instancePK, sourcePK int
+	}
+
+	merged := make(map[key]*timerMerge)
+
+	for rows.Next() {
+		var k key
+		var sketch []byte
+
+		if err = rows.Scan(&k.applicationPK, &k.instancePK, &k.sourcePK, &sketch); err != nil {
+			return err
+		}
+
+		m, ok := merged[k]
+		if !ok {
+			m = newTimerMerge()
+			merged[k] = m
+		}
+
+		m.add(0, 0, 0, 0, 0, sketch)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for k, m := range merged {
+		if !m.sketches {
+			continue
+		}
+
+		if _, err = txn.Exec(`UPDATE `+rt+` SET fifty = $5, ninety = $6, ninetynine = $7, sketch = $8
+				WHERE applicationPK = $1 AND instancePK = $2 AND sourcePK = $3 AND time = $4`,
+			k.applicationPK, k.instancePK, k.sourcePK, from,
+			m.sketch.Quantile(0.5), m.sketch.Quantile(0.9), m.sketch.Quantile(0.99), m.sketch.Marshal()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setRollup saves t as the time that table has been rolled up to.
+func setRollup(e execer, table string, t time.Time) error {
+	result, err := e.Exec(`UPDATE mtr.rollup SET time = $2 WHERE tableName = $1`, table, t)
+	if err != nil {
+		return err
+	}
+
+	var i int64
+	if i, err = result.RowsAffected(); err != nil {
+		return err
+	}
+
+	if i == 0 {
+		_, err = e.Exec(`INSERT INTO mtr.rollup(tableName, time) VALUES($1, $2)`, table, t)
+	}
+
+	return err
+}
+
+/*
+rolledUp returns SQL for the time that table has been rolled up to at resolution.  Metrics from this
+time have not been rolled up.  The time is -infinity if the table has not been rolled up.
+*/
+func rolledUp(table, resolution string) string {
+	return `(SELECT COALESCE(max(time), '-infinity') FROM mtr.rollup WHERE tableName = '` + table + `_` + resolution + `')`
+}
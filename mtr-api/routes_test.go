@@ -1,11 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/GeoNet/mtr/mtrpb"
 	wt "github.com/GeoNet/weft/wefttest"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"io/ioutil"
+	"math"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -41,11 +46,25 @@ var routes = wt.Requests{
 	// Add a timer value.
 	{ID: wt.L(), URL: "/application/timer?applicationID=test-app&instanceID=test-instance&sourceID=func-name&count=10&average=12&fifty=13&ninety=14&time=2015-05-14T21:40:30Z", Method: "PUT"},
 
+	// Add a timer value with a sketch (a single timer of 12 ms) so that percentiles can be merged across instances.
+	{ID: wt.L(), URL: "/application/timer?applicationID=test-app&instanceID=test-instance&sourceID=func-name&count=1&average=12&fifty=12&ninety=12&ninetynine=12&min=12&max=12&sketch=GBgYAPwBAg%3D%3D&time=2015-05-14T21:41:30Z", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/timer?applicationID=test-app&instanceID=test-instance&sourceID=func-name&count=1&average=12&fifty=12&ninety=12&ninetynine=12&min=12&max=12&sketch=fred&time=2015-05-14T21:42:30Z", Method: "PUT", Status: http.StatusBadRequest},
+
 	// add an object value
 	{ID: wt.L(), URL: "/application/metric?applicationID=test-app&instanceID=test-instance&typeID=1003&value=3400&time=2015-05-14T21:40:35Z", Method: "PUT"},
 
 	// add a routine value
 	{ID: wt.L(), URL: "/application/metric?applicationID=test-app&instanceID=test-instance&typeID=1100&value=1234&time=2015-05-14T21:40:40Z", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/metric?applicationID=test-app&instanceID=test-instance&typeID=1102&value=250&time=2015-05-14T21:40:40Z", Method: "PUT"},
+
+	// types for application counters and gauges.  Repeated requests noop.
+	{ID: wt.L(), URL: "/application/type?typeID=test.published&description=test+published", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/type?typeID=test.published", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/type?typeID=test.depth&unit=n", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/type?typeID=1000", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/application/counter?applicationID=test-app&instanceID=test-instance&typeID=test.published&count=3&time=2015-05-14T21:40:30Z", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/metric?applicationID=test-app&instanceID=test-instance&typeID=test.depth&value=7&time=2015-05-14T21:40:30Z", Method: "PUT"},
+	{ID: wt.L(), URL: "/application/metric?applicationID=test-app&instanceID=test-instance&typeID=test.fred&value=7&time=2015-05-14T21:40:30Z", Method: "PUT", Status: http.StatusBadRequest},
 
 	// a list of all application IDs
 	{ID: wt.L(), URL: "/app", Accept: "application/x-protobuf"},
@@ -56,6 +75,17 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=memory"},
 	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=objects"},
 	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=routines"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=gauges"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=runtime"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=runtime&resolution=full"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=gc_pauses"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=runtime_memory"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=cpu"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=timers&resolution=day"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=timers&sourceID=func-name&resolution=week"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=counters&resolution=day"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=counters&resolution=week"},
+	{ID: wt.L(), URL: "/app/metric?applicationID=test-app&group=memory&resolution=week"},
 
 	// field metrics
 
@@ -72,6 +102,28 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport", Method: "DELETE"},
 	{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100", Method: "PUT"},
 
+	// Devices have a lifecycle status and key value attributes.
+	{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100&status=maintenance", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100&status=active", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100&status=broken", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/device/attribute?deviceID=gps-taupoairport&key=owner&value=GNS", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/attribute?deviceID=gps-taupoairport&key=owner&value=GeoNet", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/attribute?deviceID=gps-taupoairport&key=installed&value=2015-05-14", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/attribute?deviceID=gps-taupoairport&key=installed", Method: "DELETE"},
+	{ID: wt.L(), URL: "/field/device/attribute?deviceID=gps-nodevice&key=owner&value=GeoNet", Method: "PUT", Status: http.StatusNotFound},
+
+	// Devices keep a location history.  A move can be recorded from any time.  See TestDeviceLocation.
+	{ID: wt.L(), URL: "/field/device/location?deviceID=gps-taupoairport&latitude=-38.74270&longitude=176.08100", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/location?deviceID=gps-taupoairport&latitude=-38.74270&longitude=176.08100&time=2015-05-14T21:40:30", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/device/location?deviceID=gps-nodevice&latitude=-38.74270&longitude=176.08100", Method: "PUT", Status: http.StatusNotFound},
+	{ID: wt.L(), URL: "/field/device/location?deviceID=gps-taupoairport", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/device/location?deviceID=gps-nodevice", Accept: "application/x-protobuf", Status: http.StatusNotFound},
+
+	// Devices are renamed keeping their history.  See TestDeviceRename.
+	{ID: wt.L(), URL: "/field/device/rename?deviceID=gps-taupoairport&newDeviceID=gps-taupo", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/rename?deviceID=gps-taupo&newDeviceID=gps-taupoairport", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/device/rename?deviceID=gps-nodevice&newDeviceID=gps-taupo", Method: "PUT", Status: http.StatusNotFound},
+
 	// Delete all metrics typeID for a device
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage", Method: "DELETE"},
 
@@ -94,6 +146,14 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/field/state?deviceID=gps-taupoairport&typeID=mains", Method: "DELETE"},
 	{ID: wt.L(), URL: "/field/state?deviceID=gps-taupoairport&typeID=mains&time=2015-05-14T21:40:30Z&value=true", Method: "PUT"},
 
+	// state history
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&startDate=2015-05-14T21:00:00Z&endDate=2015-05-15T00:00:00Z", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&startDate=2015-05-14T21:00:00Z&endDate=2015-05-15T00:00:00Z", Accept: "text/csv"},
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&startDate=2015-05-14T21:00:00Z&endDate=2015-05-15T00:00:00Z"},
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&resolution=day"},
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&startDate=2015-05-14", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/state/history?deviceID=gps-nope&typeID=mains", Status: http.StatusNotFound},
+
 	// Tags
 	{ID: wt.L(), URL: "/tag/LINZ", Method: "DELETE"},
 
@@ -108,6 +168,14 @@ var routes = wt.Requests{
 	// Delete a tag on a metric
 	{ID: wt.L(), URL: "/field/metric/tag?deviceID=gps-taupoairport&typeID=voltage&tag=LINZ", Method: "DELETE"},
 
+	// Expected reporting interval (seconds) for a field metric type, overridden for a device.
+	{ID: wt.L(), URL: "/field/metric/interval?typeID=voltage&interval=0", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/metric/interval?deviceID=gps-taupoairport&typeID=voltage&interval=600", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/metric/interval?deviceID=gps-taupoairport&typeID=voltage", Method: "DELETE"},
+	{ID: wt.L(), URL: "/field/metric/interval?deviceID=gps-taupoairport&typeID=fred&interval=600", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/field/metric/interval", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/metric/interval?typeID=voltage", Accept: "application/x-protobuf"},
+
 	// Tags
 	{ID: wt.L(), URL: "/tag/LINZ", Method: "DELETE"},
 
@@ -157,6 +225,8 @@ var routes = wt.Requests{
 	// Device
 	// protobuf version
 	{ID: wt.L(), URL: "/field/device", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-taupoairport", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-nodevice", Accept: "application/x-protobuf", Status: http.StatusNotFound},
 
 	// Metrics.  Resolution is optional on plots.  Resolution is fixed for sparks.
 	// Options for the plot parameter:
@@ -168,13 +238,17 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=hour", Content: "image/svg+xml"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=minute", Content: "image/svg+xml"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=hour", Content: "image/svg+xml"},
-	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=day", Status: http.StatusBadRequest, Surrogate: "max-age=86400"},
+	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=day", Content: "image/svg+xml"},
+	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=week", Content: "image/svg+xml"},
+	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=fred", Status: http.StatusBadRequest, Surrogate: "max-age=86400"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&plot=spark", Content: "image/svg+xml"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=minute&plot=scatter", Content: "image/svg+xml"},
 	// field metric history data
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=minute", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=five_minutes", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=hour", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=day", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=week", Accept: "application/x-protobuf"},
 
 	// Latest metrics as SVG map
 	//  These only pass with the map180 data in the DB.
@@ -196,14 +270,48 @@ var routes = wt.Requests{
 	// Same as above, but returned as GeoJSON
 	{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage", Accept: "application/vnd.geo+json"},
 
+	// With device locations at a time.
+	{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage&time=2015-05-14T21:40:30Z", Accept: "application/vnd.geo+json"},
+	{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage&time=2015-05-14", Accept: "application/vnd.geo+json", Status: http.StatusBadRequest},
+
 	// Thresholds
 
 	// All field metric thresholds as protobuf
+	// Threshold templates for a model.  Devices without a threshold inherit it.  See TestThresholdTemplate.
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=10&upper=100", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=20&upper=100", Method: "PUT"},
+	// Warning thresholds must be inside lower and upper.  See TestThresholdWarning.
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=10&upper=100&warningLower=20&warningUpper=90&hysteresis=5", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=10&upper=100&warningLower=5&warningUpper=90", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=10&upper=100&hysteresis=-1", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock", Method: "DELETE"},
+	{ID: wt.L(), URL: "/field/metric/threshold?modelID=NoSuchModel&typeID=clock&lower=10&upper=100", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/metric/threshold?typeID=clock&lower=10&upper=100", Method: "PUT", Status: http.StatusBadRequest},
 	{ID: wt.L(), URL: "/field/metric/threshold", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/metric/threshold/missing", Accept: "application/x-protobuf"},
 
 	// Metric types
 	{ID: wt.L(), URL: "/field/type", Accept: "application/x-protobuf"},
 
+	// add, update, and delete types.  Deleting a type that doesn't exist is not an error.
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp&description=temperature&unit=mC&scale=0.001&display=C", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp&description=air+temperature&unit=mC&scale=0.001&display=C", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/type?typeID=1&scale=1&display=C", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp&scale=0&display=C", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp&scale=1", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp", Method: "DELETE"},
+	{ID: wt.L(), URL: "/field/type?typeID=test.temp", Method: "DELETE"},
+	{ID: wt.L(), URL: "/field/state/type?typeID=test.door", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/state/type?typeID=test.door", Method: "PUT"},
+	{ID: wt.L(), URL: "/field/state/type", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/field/state/type?typeID=test.door", Method: "DELETE"},
+	{ID: wt.L(), URL: "/data/type?typeID=test.latency&description=test+latency&unit=ms&scale=1&display=ms", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/type?typeID=test.latency", Method: "DELETE"},
+	{ID: wt.L(), URL: "/data/completeness/type?typeID=test.completeness&expected=8640", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/completeness/type?typeID=test.completeness&expected=0", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/data/completeness/type?typeID=test.completeness", Method: "DELETE"},
+	{ID: wt.L(), URL: "/application/type?typeID=Requests", Method: "DELETE", Status: http.StatusBadRequest},
+
 	// Data latency
 
 	// Delete site - cascades to latency values
@@ -220,6 +328,24 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/data/site?siteID=TAUP", Method: "DELETE"},
 	{ID: wt.L(), URL: "/data/site?siteID=TAUP&latitude=-38.74270&longitude=176.08100", Method: "PUT"},
 
+	// Sites have a lifecycle status and key value attributes.
+	{ID: wt.L(), URL: "/data/site?siteID=TAUP&latitude=-38.74270&longitude=176.08100&status=planned", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site?siteID=TAUP&latitude=-38.74270&longitude=176.08100&status=active", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site?siteID=TAUP&latitude=-38.74270&longitude=176.08100&status=broken", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/data/site/attribute?siteID=TAUP&key=owner&value=GeoNet", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site/attribute?siteID=TAUP&key=installed&value=2015-05-14", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site/attribute?siteID=TAUP&key=installed", Method: "DELETE"},
+	{ID: wt.L(), URL: "/data/site/attribute?siteID=NOSITE&key=owner&value=GeoNet", Method: "PUT", Status: http.StatusNotFound},
+
+	// Sites keep a location history and are renamed keeping their history.
+	{ID: wt.L(), URL: "/data/site/location?siteID=TAUP&latitude=-38.74270&longitude=176.08100&time=2015-05-14T21:40:30Z", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site/location?siteID=NOSITE&latitude=-38.74270&longitude=176.08100", Method: "PUT", Status: http.StatusNotFound},
+	{ID: wt.L(), URL: "/data/site/location?siteID=TAUP", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/site/location?siteID=NOSITE", Accept: "application/x-protobuf", Status: http.StatusNotFound},
+	{ID: wt.L(), URL: "/data/site/rename?siteID=TAUP&newSiteID=TAUP2", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site/rename?siteID=TAUP2&newSiteID=TAUP", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/site/rename?siteID=NOSITE&newSiteID=TAUP2", Method: "PUT", Status: http.StatusNotFound},
+
 	// Should get a rate limit error for sends in the same minute
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&time=2015-05-14T21:40:30Z&mean=10000", Method: "PUT"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&time=2015-05-14T21:40:30Z&mean=14100", Status: http.StatusTooManyRequests, Method: "PUT"},
@@ -230,6 +356,8 @@ var routes = wt.Requests{
 
 	// All data sites as protobuf
 	{ID: wt.L(), URL: "/data/site", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/site/detail?siteID=TAUP", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/site/detail?siteID=NOSITE", Accept: "application/x-protobuf", Status: http.StatusNotFound},
 	{ID: wt.L(), URL: "/data/type", Accept: "application/x-protobuf"},
 
 	// min, max, fifty, ninety are optional latency values
@@ -250,11 +378,35 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=12000&upper=15000", Method: "PUT"},
 
 	// protobuf of all latency thresholds
+	// Threshold template for a type.  Sites without a threshold inherit it.  See TestThresholdTemplate.
+	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak&lower=0&upper=30000", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak&lower=0&upper=20000", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak&lower=0&upper=30000&warningUpper=20000&hysteresis=1000", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak&lower=0&upper=30000&warningUpper=40000", Method: "PUT", Status: http.StatusBadRequest},
+	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak", Method: "DELETE"},
 	{ID: wt.L(), URL: "/data/latency/threshold", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/latency/threshold/missing", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.strong&siteID=TAUP", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.strong&typeID=latency.strong", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.strong&siteID=TAUP&typeID=latency.strong", Accept: "application/x-protobuf"},
 
+	// Expected reporting interval (seconds) for a type, overridden for a site.  0 means never late.
+	// The TAUP latency data is old so it will be late.
+	{ID: wt.L(), URL: "/data/latency/interval?typeID=latency.strong&interval=0", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval?siteID=TAUP&typeID=latency.strong", Method: "DELETE"},
+	{ID: wt.L(), URL: "/data/latency/interval?siteID=TAUP&typeID=latency.strong&interval=600", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval?siteID=TAUP&typeID=latency.strong&interval=300", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval?siteID=TAUP&typeID=latency.strong&interval=-1", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval?siteID=FRED&typeID=latency.strong&interval=300", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval?typeID=latency.fred&interval=300", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/data/latency/interval", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/latency/interval?typeID=latency.strong", Accept: "application/x-protobuf"},
+
+	{ID: wt.L(), URL: "/data/completeness/interval?typeID=completeness.gnss.1hz&interval=0", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/completeness/interval?siteID=TAUP&typeID=completeness.gnss.1hz&interval=300", Method: "PUT"},
+	{ID: wt.L(), URL: "/data/completeness/interval?siteID=TAUP&typeID=completeness.gnss.1hz", Method: "DELETE"},
+	{ID: wt.L(), URL: "/data/completeness/interval", Accept: "application/x-protobuf"},
+
 	// Delete data.completeness
 	{ID: wt.L(), URL: "/data/completeness?siteID=WGTN&typeID=completeness.gnss.1hz&time=2015-05-14T23:40:30Z&count=300", Method: "PUT"},
 	{ID: wt.L(), URL: "/data/completeness?siteID=WGTN&typeID=completeness.gnss.1hz", Method: "DELETE"},
@@ -295,6 +447,8 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=hour"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=minute"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=hour"},
+	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=day"},
+	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=week"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&plot=spark"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=minute&plot=scatter"},
 
@@ -302,11 +456,15 @@ var routes = wt.Requests{
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=minute", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=five_minutes", Accept: "application/x-protobuf"},
 	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=hour", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=day", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=week", Accept: "application/x-protobuf"},
 
 	// Completeness plots.
 	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=five_minutes"},
 	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=hour"},
 	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=twelve_hours"},
+	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=day"},
+	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=week"},
 	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&plot=spark"},
 	{ID: wt.L(), URL: "/data/completeness?siteID=TAUP&typeID=completeness.gnss.1hz&resolution=five_minutes&plot=scatter"},
 
@@ -326,6 +484,48 @@ var routes = wt.Requests{
 	// Delete a tag on a metric
 	{ID: wt.L(), URL: "/field/metric/tag?deviceID=gps-taupoairport&typeID=voltage&tag=LINZ", Method: "DELETE"},
 
+	// alerts
+	{ID: wt.L(), URL: "/alert"},
+	{ID: wt.L(), URL: "/alert", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/alert?startDate=2015-05-14T00:00:00Z&endDate=2015-05-15T00:00:00Z", Accept: "application/x-protobuf"},
+
+	// alert notification routing.  The tag must exist and the receiver must be valid.
+	{ID: wt.L(), URL: "/alert/notify?tag=FRED&receiver=stdout", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP&receiver=ftp://example.com", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP&receiver=stdout", Method: "PUT"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP&receiver=stdout", Method: "PUT"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP&receiver=stdout", Method: "DELETE"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP&receiver=stdout", Method: "PUT"},
+	{ID: wt.L(), URL: "/alert/notify", Accept: "application/x-protobuf"},
+	{ID: wt.L(), URL: "/alert/notify?tag=TAUP", Accept: "application/x-protobuf"},
+
+	// retention policies.  A policy for a typeID overrides the default for the table.
+	{ID: wt.L(), URL: "/retention?table=data.latency&days=40", Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.strong&days=30&requireRollup=false", Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.strong&days=20", Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.strong", Method: "DELETE"},
+	{ID: wt.L(), URL: "/retention?table=data.fred&days=40", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&days=0", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.fred&days=40", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=app.timer&typeID=latency.strong&days=40", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/retention?table=data.latency&days=40&requireRollup=fred", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/retention"},
+	{ID: wt.L(), URL: "/retention", Accept: "application/x-protobuf"},
+
+	// ingest maps for Influx line protocol and Prometheus remote write.
+	{ID: wt.L(), URL: "/ingest/map?name=voltage&table=field.metric&typeID=voltage&label=host&scale=1000", Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map?name=latency&table=data.latency&typeID=latency.strong&label=station", Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map?name=fred&table=field.metric&typeID=voltage&label=host", Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map?name=fred", Method: "DELETE"},
+	{ID: wt.L(), URL: "/ingest/map?name=fred&table=data.fred&typeID=voltage&label=host", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map?name=fred&table=data.latency&typeID=voltage&label=host", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map?name=fred&table=field.metric&typeID=voltage&label=host&scale=0", Status: http.StatusBadRequest, Method: "PUT"},
+	{ID: wt.L(), URL: "/ingest/map"},
+	{ID: wt.L(), URL: "/ingest/map", Accept: "application/x-protobuf"},
+
+	// latest metrics and thresholds in the Prometheus text format.
+	{ID: wt.L(), URL: "/metrics", Content: "text/plain; version=0.0.4"},
+
 	// soh routes
 	{ID: wt.L(), URL: "/soh"},
 	{ID: wt.L(), URL: "/soh/up"},
@@ -487,12 +687,12 @@ func TestFieldMetricHistoryLog(t *testing.T) {
 		t.Errorf("expected voltage got %s", f.TypeID)
 	}
 
-	if f.Upper != 45000 {
-		t.Errorf("expected 45000 got %d", f.Upper)
+	if f.FloatUpper != 45000 {
+		t.Errorf("expected 45000 got %g", f.FloatUpper)
 	}
 
-	if f.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", f.Lower)
+	if f.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", f.FloatLower)
 	}
 
 	if f.Scale != 0.001 {
@@ -502,8 +702,8 @@ func TestFieldMetricHistoryLog(t *testing.T) {
 	// Not testing number of latency log
 }
 
-// All field metric tags as a protobuf.
-func TestFieldMetricTag(t *testing.T) {
+// Batch upload of field metrics as protobuf and CSV.
+func TestFieldMetricBatch(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -512,70 +712,82 @@ func TestFieldMetricTag(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/metric/tag", Accept: "application/x-protobuf"}
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	f := mtrpb.FieldMetricBatch{Row: []*mtrpb.FieldMetricBatchRow{
+		{DeviceID: "gps-taupoairport", TypeID: "voltage", Seconds: now.Add(time.Minute * -3).Unix(), Value: 14000},
+		{DeviceID: "gps-taupoairport", TypeID: "voltage", Seconds: now.Add(time.Minute * -2).Unix(), Value: 14100},
+		// same minute as the previous row.
+		{DeviceID: "gps-taupoairport", TypeID: "voltage", Seconds: now.Add(time.Minute*-2 + time.Second).Unix(), Value: 14200},
+		{DeviceID: "gps-nodevice", TypeID: "voltage", Seconds: now.Unix(), Value: 14300},
+	}}
 
 	var b []byte
 	var err error
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if b, err = proto.Marshal(&f); err != nil {
+		t.Fatal(err)
 	}
 
-	var tr mtrpb.FieldMetricTagResult
-
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	if b, err = postBatch("/field/metric/batch", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result == nil {
-		t.Error("got nil for /field/metric/tag protobuf")
-	}
+	var res mtrpb.BatchResult
 
-	if len(tr.Result) != 1 {
-		t.Errorf("expected 1 tag result got %d", len(tr.Result))
+	if err = proto.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result[0].Tag != "TAUP" {
-		t.Errorf("expected TAUP as the first tag got %s", tr.Result[0].Tag)
+	if len(res.Result) != 4 {
+		t.Fatalf("expected 4 results got %d", len(res.Result))
 	}
 
-	if tr.Result[0].DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].DeviceID)
-	}
+	for i, c := range []int32{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusBadRequest} {
+		if res.Result[i].Row != int32(i) {
+			t.Errorf("expected row %d got %d", i, res.Result[i].Row)
+		}
 
-	if tr.Result[0].TypeID != "voltage" {
-		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+		if res.Result[i].Code != c {
+			t.Errorf("row %d expected code %d got %d", i, c, res.Result[i].Code)
+		}
 	}
 
-	// Test tag result for parameter with deviceID+typeID
-	r = wt.Request{ID: wt.L(), URL: "/field/metric/tag?deviceID=gps-taupoairport&typeID=voltage", Accept: "application/x-protobuf"}
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	// the summary has the newest value from the batch.
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result == nil {
-		t.Error("got nil for /field/metric/tag protobuf")
+	var s mtrpb.FieldMetricSummaryResult
+
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(tr.Result) != 1 {
-		t.Errorf("expected 1 tag result got %d", len(tr.Result))
+	if len(s.Result) != 1 {
+		t.Fatalf("expected 1 summary got %d", len(s.Result))
 	}
 
-	if tr.Result[0].Tag != "TAUP" {
-		t.Errorf("expected TAUP as the first tag got %s", tr.Result[0].Tag)
+	if s.Result[0].FloatValue != 14100 {
+		t.Errorf("expected 14100 got %g", s.Result[0].FloatValue)
 	}
 
-	if tr.Result[0].DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].DeviceID)
+	c := fmt.Sprintf("gps-taupoairport,voltage,%s,14400\ngps-taupoairport,voltage,not-a-time,14500\n",
+		now.Add(time.Minute*-1).Format(time.RFC3339))
+
+	if b, err = postBatch("/field/metric/batch", "text/csv", []byte(c)); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result[0].TypeID != "voltage" {
-		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+	if string(b) != "row,code,msg\n0,200,\n1,400,invalid time\n" {
+		t.Errorf("unexpected csv result %s", string(b))
 	}
 }
 
-// protobuf of field metrics and latencies for a single tag.
-func TestTag(t *testing.T) {
+// fractional and wide range values and thresholds.
+func TestFieldMetricFloat(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -584,56 +796,105 @@ func TestTag(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/tag/TAUP", Accept: "application/x-protobuf"}
+	now := time.Now().UTC().Truncate(time.Minute)
 
-	var b []byte
-	var err error
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&value=14100.5&time=" +
+			now.Add(time.Minute*-2).Format(time.RFC3339), Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&value=NaN&time=" +
+			now.Add(time.Minute*-2).Format(time.RFC3339), Method: "PUT", User: userW, Password: keyW, Status: http.StatusBadRequest},
+		{ID: wt.L(), URL: "/field/metric/threshold?deviceID=gps-taupoairport&typeID=voltage&lower=0.5&upper=3000000000.5", Method: "PUT", User: userW, Password: keyW},
+	} {
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=minute" +
+		"&startDate=" + now.Add(time.Minute*-3).Format(time.RFC3339) + "&endDate=" + now.Format(time.RFC3339), Accept: "text/csv"}
+
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), ",14.1005\n") {
+		t.Errorf("expected 14.1005 in csv got %s", string(b))
+	}
+
+	// a value bigger than an int32 as a float in a batch.
+	f := mtrpb.FieldMetricBatch{Row: []*mtrpb.FieldMetricBatchRow{
+		{DeviceID: "gps-taupoairport", TypeID: "voltage", Seconds: now.Add(time.Minute * -1).Unix(), FloatValue: 3000000000.25},
+	}}
+
+	if b, err = proto.Marshal(&f); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = postBatch("/field/metric/batch", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage", Accept: "application/x-protobuf"}
 
 	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	var tr mtrpb.TagSearchResult
+	var s mtrpb.FieldMetricSummaryResult
 
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.FieldMetric == nil {
-		t.Error("Got nil FieldMetric")
+	if len(s.Result) != 1 {
+		t.Fatalf("expected 1 summary got %d", len(s.Result))
 	}
 
-	if tr.DataLatency == nil {
-		t.Error("Got nil DataLatency")
+	if s.Result[0].FloatValue != 3000000000.25 {
+		t.Errorf("expected 3000000000.25 got %f", s.Result[0].FloatValue)
 	}
 
-	if tr.FieldState == nil {
-		t.Error("Got nil FieldState")
+	if s.Result[0].FloatLower != 0.5 || s.Result[0].FloatUpper != 3000000000.5 {
+		t.Errorf("expected threshold 0.5 3000000000.5 got %f %f", s.Result[0].FloatLower, s.Result[0].FloatUpper)
 	}
 
-	if tr.DataCompleteness == nil {
-		t.Error("Got nil DataCompleteness")
+	// the integer fields are kept for existing clients.
+	if s.Result[0].Value != math.MaxInt32 || s.Result[0].Lower != 1 || s.Result[0].Upper != math.MaxInt32 {
+		t.Errorf("expected integer value and threshold %d 1 %d got %d %d %d", math.MaxInt32, math.MaxInt32,
+			s.Result[0].Value, s.Result[0].Lower, s.Result[0].Upper)
 	}
+}
 
-	if tr.FieldMetric[0].DeviceID != "gps-taupoairport" {
-		t.Errorf("expected deviceID gps-taupoairport got %s", tr.FieldMetric[0].DeviceID)
+// postBatch posts body to the test server and returns the response body.
+func postBatch(url, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest("POST", testServer.URL+url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
 	}
 
-	if tr.DataLatency[0].SiteID != "TAUP" {
-		t.Errorf("expected siteID TAUP got %s", tr.DataLatency[0].SiteID)
+	req.Header.Set("Content-Type", contentType)
+	req.SetBasicAuth(userW, keyW)
+
+	res, err := wt.Client.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer res.Body.Close()
 
-	if tr.FieldState[0].DeviceID != "gps-taupoairport" {
-		t.Errorf("expected deviceID gps-taupoairport got %s", tr.FieldState[0].DeviceID)
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	if tr.DataCompleteness[0].SiteID != "TAUP" {
-		t.Errorf("expected siteID TAUP got %s", tr.DataCompleteness[0].SiteID)
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s got status %d expected %d: %s", url, res.StatusCode, http.StatusOK, string(b))
 	}
+
+	return b, nil
 }
 
-// all tags as a protobuf
-func TestTagAll(t *testing.T) {
+func TestApplicationBatch(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -642,32 +903,72 @@ func TestTagAll(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/tag", Accept: "application/x-protobuf"}
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	a := mtrpb.ApplicationBatch{
+		ApplicationID: "test-app",
+		InstanceID:    "test-instance",
+		Metric: []*mtrpb.ApplicationMetric{
+			{TypeID: 1000, Seconds: now.Unix(), Value: 10000},
+			// already added by the routes.
+			{TypeID: 1000, Seconds: time.Date(2015, 5, 14, 21, 40, 30, 0, time.UTC).Unix(), Value: 10000},
+			{TypeID: 9999, Seconds: now.Unix(), Value: 1},
+		},
+		Counter: []*mtrpb.ApplicationCounter{
+			{TypeID: 1201, Seconds: now.Unix(), Count: 3},
+			// same type and time as the previous row.
+			{TypeID: 1201, Seconds: now.Unix(), Count: 4},
+		},
+		Timer: []*mtrpb.ApplicationTimer{
+			{SourceID: "a-new-func", Seconds: now.Unix(), Count: 10, Average: 12, Fifty: 13, Ninety: 14},
+		},
+	}
 
 	var b []byte
 	var err error
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if b, err = proto.Marshal(&a); err != nil {
+		t.Fatal(err)
 	}
 
-	var tr mtrpb.TagResult
+	if b, err = postBatch("/application/batch", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
+	}
 
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	var res mtrpb.BatchResult
+
+	if err = proto.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(tr.Result) != 6 {
-		t.Errorf("expected 6 tags got %d", len(tr.Result))
+	if len(res.Result) != 6 {
+		t.Fatalf("expected 6 results got %d", len(res.Result))
 	}
 
-	if tr.Result[0].Tag != "DAGG" {
-		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
+	for i, c := range []int32{http.StatusOK, http.StatusTooManyRequests, http.StatusBadRequest,
+		http.StatusOK, http.StatusTooManyRequests, http.StatusOK} {
+		if res.Result[i].Code != c {
+			t.Errorf("row %d expected code %d got %d", i, c, res.Result[i].Code)
+		}
+	}
+
+	// sending the same batch again is not an error.
+	if b, err = proto.Marshal(&a); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = postBatch("/application/batch", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = postBatch("/application/batch", "text/csv", b); err == nil {
+		t.Error("expected error for text/csv")
 	}
 }
 
-// protobuf of latency summary info.
-func TestDataLatencySummary(t *testing.T) {
+// Metrics ingested from Influx line protocol and Prometheus remote write are mapped onto
+// field metrics and data latencies using the ingest maps.
+func TestIngest(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -676,82 +977,110 @@ func TestDataLatencySummary(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/latency/summary", Accept: "application/x-protobuf"}
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	c := fmt.Sprintf(`voltage,host=gps-taupoairport value=14.2 %d
+latency,station=TAUP value=250 %d
+current,host=gps-taupoairport value=2
+voltage value=14.2
+voltage,host=gps-taupoairport value=
+`, now.Add(time.Minute*-1).UnixNano(), now.Add(time.Minute*-1).UnixNano())
 
 	var b []byte
 	var err error
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if b, err = postBatch("/ingest/influx", "text/plain", []byte(c)); err != nil {
+		t.Fatal(err)
 	}
 
-	var f mtrpb.DataLatencySummaryResult
-
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if string(b) != "row,code,msg\n0,200,\n1,200,\n2,404,no ingest map for the metric name\n"+
+		"3,400,missing the label for the deviceID or siteID\n4,400,invalid field\n" {
+		t.Errorf("unexpected csv result %s", string(b))
 	}
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	d := f.Result[0]
+	var s mtrpb.FieldMetricSummaryResult
 
-	if d.SiteID != "TAUP" {
-		t.Errorf("expected TAUP got %s", d.SiteID)
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.TypeID != "latency.strong" {
-		t.Errorf("expected latency.strong got %s", d.TypeID)
+	if len(s.Result) != 1 {
+		t.Fatalf("expected 1 summary got %d", len(s.Result))
 	}
 
-	if d.Mean != 10000 {
-		t.Errorf("expected 10000 got %d", d.Mean)
+	// scaled from V to mV by the ingest map.
+	if s.Result[0].FloatValue != 14200 {
+		t.Errorf("expected 14200 got %g", s.Result[0].FloatValue)
 	}
 
-	if d.Fifty != 0 {
-		t.Errorf("expected 0 got %d", d.Fifty)
-	}
+	w := mtrpb.PromWriteRequest{Timeseries: []*mtrpb.PromTimeSeries{
+		{
+			Labels: []*mtrpb.PromLabel{{Name: "__name__", Value: "latency"}, {Name: "station", Value: "TAUP"}},
+			Samples: []*mtrpb.PromSample{
+				{Value: 260, Timestamp: now.UnixNano() / int64(time.Millisecond)},
+				// same minute as the previous sample.
+				{Value: 270, Timestamp: now.Add(time.Second).UnixNano() / int64(time.Millisecond)},
+			},
+		},
+		{
+			Labels:  []*mtrpb.PromLabel{{Name: "__name__", Value: "latency"}, {Name: "station", Value: "NOSITE"}},
+			Samples: []*mtrpb.PromSample{{Value: 260, Timestamp: now.UnixNano() / int64(time.Millisecond)}},
+		},
+	}}
 
-	if d.Ninety != 0 {
-		t.Errorf("expected 0 got %d", d.Ninety)
+	if b, err = proto.Marshal(&w); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Seconds == 0 {
-		t.Error("unexpected zero seconds")
+	if b, err = postBatch("/ingest/prometheus", "application/x-protobuf", snappy.Encode(nil, b)); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Upper != 15000 {
-		t.Errorf("expected 15000 got %d", d.Upper)
+	var res mtrpb.BatchResult
+
+	if err = proto.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", d.Lower)
+	if len(res.Result) != 3 {
+		t.Fatalf("expected 3 results got %d", len(res.Result))
 	}
 
-	if d.Scale != 1.0 {
-		t.Errorf("expected 1.0 got %f", d.Scale)
+	for i, c := range []int32{http.StatusOK, http.StatusTooManyRequests, http.StatusBadRequest} {
+		if res.Result[i].Code != c {
+			t.Errorf("row %d expected code %d got %d", i, c, res.Result[i].Code)
+		}
 	}
 
-	r.URL = "/data/latency/summary?typeID=latency.strong"
+	r = wt.Request{ID: wt.L(), URL: "/data/latency/summary", Accept: "application/x-protobuf"}
 
 	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	f.Reset()
+	var l mtrpb.DataLatencySummaryResult
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if err = proto.Unmarshal(b, &l); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	if len(l.Result) != 1 {
+		t.Fatalf("expected 1 summary got %d", len(l.Result))
+	}
+
+	if l.Result[0].FloatMean != 260 {
+		t.Errorf("expected 260 got %g", l.Result[0].FloatMean)
 	}
 }
 
-// protobuf of latency history log info.
-func TestDataLatencyHistoryLog(t *testing.T) {
+// Old TAUP latency rows are deleted by a retention policy for latency.strong.
+func TestRetention(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -760,105 +1089,89 @@ func TestDataLatencyHistoryLog(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=minute", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
+	count := func() (n int) {
+		if err := db.QueryRow(`SELECT count(*) FROM data.latency JOIN data.site USING (sitePK) WHERE siteID = 'TAUP'`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if count() == 0 {
+		t.Fatal("expected TAUP latency test data")
 	}
 
-	var f mtrpb.DataLatencyResult
+	// nothing has been rolled up so nothing should be deleted.
+	r := wt.Request{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.strong&days=1&requireRollup=true", Method: "PUT",
+		User: userW, Password: keyW}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if f.SiteID != "TAUP" {
-		t.Errorf("expected TAUP got %s", f.SiteID)
+	if err := applyRetention(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if f.TypeID != "latency.strong" {
-		t.Errorf("expected latency.strong got %s", f.TypeID)
+	if count() == 0 {
+		t.Error("expected TAUP latency to be kept until it has been rolled up")
 	}
 
-	if f.Upper != 15000 {
-		t.Errorf("expected 15000 got %d", f.Upper)
-	}
+	r.URL = "/retention?table=data.latency&typeID=latency.strong&days=1"
 
-	if f.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", f.Lower)
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if f.Scale != 1.0 {
-		t.Errorf("expected 1.0 got %f", f.Scale)
+	if err := applyRetention(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
-	// Not testing number of latency log
-}
-
-// protobuf of latency summary info.
-func TestDataCompletenessSummary(t *testing.T) {
-	setup(t)
-	defer teardown()
 
-	// Load test data.
-	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
-		t.Error(err)
+	if n := count(); n != 0 {
+		t.Errorf("expected old TAUP latency to be deleted got %d rows", n)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/completeness/summary", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
+	r = wt.Request{ID: wt.L(), URL: "/retention", Accept: "application/x-protobuf"}
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	var f mtrpb.DataCompletenessSummaryResult
-
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
-	}
+	var rr mtrpb.RetentionPolicyResult
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	if err = proto.Unmarshal(b, &rr); err != nil {
+		t.Fatal(err)
 	}
 
-	d := f.Result[0]
+	var found bool
 
-	if d.SiteID != "TAUP" {
-		t.Errorf("expected TAUP got %s", d.SiteID)
-	}
+	for _, v := range rr.Result {
+		if v.Table == "data.latency" && v.TypeID == "latency.strong" {
+			found = true
 
-	if d.TypeID != "completeness.gnss.1hz" {
-		t.Errorf("expected gnss.1hz got %s", d.TypeID)
-	}
+			if v.LastRun == 0 {
+				t.Error("expected non zero LastRun")
+			}
 
-	if d.Seconds == 0 {
-		t.Error("unexpected zero seconds")
+			if v.LastDeleted == 0 {
+				t.Error("expected non zero LastDeleted")
+			}
+		}
 	}
 
-	r.URL = "/data/completeness/summary?typeID=completeness.gnss.1hz"
-
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if !found {
+		t.Error("didn't find retention policy for data.latency latency.strong")
 	}
 
-	f.Reset()
+	r = wt.Request{ID: wt.L(), URL: "/retention?table=data.latency&typeID=latency.strong", Method: "DELETE", User: userW, Password: keyW}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
+	if _, err = r.Do(testServer.URL); err != nil {
 		t.Error(err)
 	}
-
-	if len(f.Result) != 1 {
-		t.Errorf("expected 1 result got %d results", len(f.Result))
-	}
 }
 
-// protobuf of field metric summary info.
-func TestFieldMetricsSummary(t *testing.T) {
+// Metrics are rolled up by hour and day and the rollups are read for day resolution.
+func TestRollup(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -867,79 +1180,97 @@ func TestFieldMetricsSummary(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/metric/summary", Accept: "application/x-protobuf"}
+	// The test data is for 2015-05-14.
+	now := time.Date(2015, 5, 15, 0, 30, 0, 0, time.UTC)
+	rolled := time.Date(2015, 5, 15, 0, 0, 0, 0, time.UTC)
 
-	var b []byte
-	var err error
+	// a timer with min and max times.
+	tr := wt.Request{ID: wt.L(), URL: "/application/timer?applicationID=test-app&instanceID=test-instance&sourceID=func-name&count=3&average=12&fifty=12&ninety=80&min=2&max=90&time=2015-05-14T21:43:30Z", Method: "PUT", User: userW, Password: keyW}
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if _, err := tr.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	var f mtrpb.FieldMetricSummaryResult
-
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if err := rollupAll(now); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	// rolling up again is a noop.
+	if err := rollupAll(now); err != nil {
+		t.Fatal(err)
 	}
 
-	d := f.Result[0]
+	for _, table := range []string{"field.metric", "field.metric_hour", "field.metric_day", "data.latency", "app.timer"} {
+		var r time.Time
 
-	if d.DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
-	}
+		if err := db.QueryRow(`SELECT time FROM mtr.rollup WHERE tableName = $1`, table).Scan(&r); err != nil {
+			t.Fatal(err)
+		}
 
-	if d.ModelID != "Trimble NetR9" {
-		t.Errorf("expected Trimble NetR9 got %s", d.ModelID)
+		if !r.Equal(rolled) {
+			t.Errorf("%s expected rolled up to %s got %s", table, rolled, r)
+		}
 	}
 
-	if d.TypeID != "voltage" {
-		t.Errorf("expected voltage got %s", d.TypeID)
-	}
+	var value, n int
+	var avg float64
 
-	if d.Value != 14100 {
-		t.Errorf("expected 14100 got %d", d.Value)
+	if err := db.QueryRow(`SELECT value FROM field.metric
+			JOIN field.device USING (devicePK)
+			JOIN field.type USING (typePK)
+			WHERE deviceID = 'gps-taupoairport' AND typeID = 'voltage'`).Scan(&value); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Seconds == 0 {
-		t.Error("unexpected zero seconds")
-	}
+	for _, table := range []string{"field.metric_hour", "field.metric_day"} {
+		if err := db.QueryRow(`SELECT avg, count FROM `+table+`
+			JOIN field.device USING (devicePK)
+			JOIN field.type USING (typePK)
+			WHERE deviceID = 'gps-taupoairport' AND typeID = 'voltage'`).Scan(&avg, &n); err != nil {
+			t.Fatal(err)
+		}
 
-	if d.Upper != 45000 {
-		t.Errorf("expected 45000 got %d", d.Upper)
+		if avg != float64(value) || n != 1 {
+			t.Errorf("%s expected avg %d count 1 got %f %d", table, value, avg, n)
+		}
 	}
 
-	if d.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", d.Lower)
-	}
+	// timers roll up the min and max times, not the min and max averages.
+	for _, table := range []string{"app.timer_hour", "app.timer_day"} {
+		var min, max float64
 
-	if d.Scale != 0.001 {
-		t.Errorf("expected 0.001 got %f", d.Scale)
-	}
+		if err := db.QueryRow(`SELECT min, max FROM `+table+`
+			JOIN app.source USING (sourcePK)
+			WHERE sourceID = 'func-name'`).Scan(&min, &max); err != nil {
+			t.Fatal(err)
+		}
 
-	// should be no errors and empty result for typeID=conn
-	r.URL = "/field/metric/summary?typeID=conn"
+		if min != 2 || max != 90 {
+			t.Errorf("%s expected min 2 max 90 got %f %f", table, min, max)
+		}
+	}
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	// change the rollup so we can tell it is read instead of the metrics.
+	if _, err := db.Exec(`UPDATE field.metric_day SET avg = avg * 2`); err != nil {
+		t.Fatal(err)
 	}
 
-	f.Reset()
+	r := wt.Request{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=day" +
+		"&startDate=2015-05-14T00:00:00Z&endDate=2015-05-15T00:00:00Z", Accept: "text/csv"}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if len(f.Result) != 0 {
-		t.Error("expected 0 results.")
-	}
+	compareCsvData(b, [][]string{
+		{""}, // header line, ignored in test.
+		{time.Date(2015, 5, 14, 0, 0, 0, 0, time.UTC).Format(DYGRAPH_TIME_FORMAT), formatValue(float64(value*2)*0.001)},
+	}, t)
 }
 
-// protobuf of field metric threshold info.
-func TestFieldMetricsThreshold(t *testing.T) {
+// Metrics added to an hour that has already been rolled up are rolled up again.
+func TestRollupLate(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -948,54 +1279,62 @@ func TestFieldMetricsThreshold(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/metric/threshold", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
+	// The test data is for 2015-05-14.
+	now := time.Date(2015, 5, 15, 0, 30, 0, 0, time.UTC)
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if err := rollupAll(now); err != nil {
+		t.Fatal(err)
 	}
 
-	var f mtrpb.FieldMetricThresholdResult
+	// a late metric e.g., from a backfill, in the 21:00 hour which has been rolled up.
+	r := wt.Request{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&time=2015-05-14T21:10:00Z&value=14000",
+		Method: "PUT", User: userW, Password: keyW}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if f.Result == nil {
-		t.Error("got nil for /field/metric/threshold protobuf")
-	}
+	var dirty bool
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	if err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM mtr.rollup_dirty WHERE tableName = 'field.metric' AND time = $1)`,
+		time.Date(2015, 5, 14, 21, 0, 0, 0, time.UTC)).Scan(&dirty); err != nil {
+		t.Fatal(err)
 	}
 
-	d := f.Result[0]
-
-	if d.DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
+	if !dirty {
+		t.Error("expected the 21:00 hour to be dirty")
 	}
 
-	if d.TypeID != "voltage" {
-		t.Errorf("expected voltage got %s", d.TypeID)
+	if err := rollupAll(now); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Upper != 45000 {
-		t.Errorf("expected 45000 got %d", d.Upper)
+	for _, table := range []string{"field.metric_hour", "field.metric_day"} {
+		var n int
+
+		if err := db.QueryRow(`SELECT count FROM `+table+`
+			JOIN field.device USING (devicePK)
+			JOIN field.type USING (typePK)
+			WHERE deviceID = 'gps-taupoairport' AND typeID = 'voltage'`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 2 {
+			t.Errorf("%s expected count 2 after the late metric got %d", table, n)
+		}
 	}
 
-	if d.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", d.Lower)
+	if err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM mtr.rollup_dirty WHERE tableName = 'field.metric')`).Scan(&dirty); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Scale != 0.001 {
-		t.Errorf("expected 0.001 got %f", d.Scale)
+	if dirty {
+		t.Error("expected no dirty hours after rolling up again")
 	}
 }
 
-// protobuf of data sites.
-func TestDataSites(t *testing.T) {
+// Metrics and thresholds are exported as labelled gauges.
+func TestMetrics(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1004,52 +1343,40 @@ func TestDataSites(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/site", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
-
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
-	}
+	r := wt.Request{ID: wt.L(), URL: "/metrics", Content: "text/plain; version=0.0.4"}
 
-	var f mtrpb.DataSiteResult
-
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if f.Result == nil {
-		t.Error("got nil for /data/site protobuf")
-	}
+	m := string(b)
 
-	if len(f.Result) != 2 {
-		t.Error("expected 2 results.")
+	for _, l := range []string{
+		"# TYPE mtr_field_metric gauge\n",
+		`,typeID="voltage",tags="LINZ,TAUP"} 12000` + "\n",
+		`,typeID="voltage",tags="LINZ,TAUP"} 45000` + "\n",
+		`,typeID="mains",tags="TAUP"} 1` + "\n",
+		`mtr_data_latency_threshold_lower{siteID="TAUP",typeID="latency.strong",`,
+	} {
+		if !strings.Contains(m, l) {
+			t.Errorf("expected %q in metrics", l)
+		}
 	}
 
-	var found bool
-
-	for _, v := range f.Result {
-		if v.SiteID == "TAUP" {
-			found = true
-
-			if v.Latitude != -38.74270 {
-				t.Errorf("Data site TAUP got expected latitude -38.74270 got %f", v.Latitude)
-			}
-
-			if v.Longitude != 176.08100 {
-				t.Errorf("Data site TAUP got expected longitude 176.08100 got %f", v.Longitude)
-			}
+	for _, l := range strings.Split(strings.TrimSpace(m), "\n") {
+		if strings.HasPrefix(l, "#") {
+			continue
 		}
-	}
 
-	if !found {
-		t.Error("Didn't find site TAUP")
+		if !strings.HasPrefix(l, "mtr_") || !strings.Contains(l, "} ") {
+			t.Errorf("badly formatted metric %q", l)
+		}
 	}
 }
 
-// All data latency tags as a protobuf.
-func TestDataLatencyTag(t *testing.T) {
+// Alerts are opened and closed as the TAUP latency crosses its threshold.
+func TestAlert(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1058,70 +1385,64 @@ func TestDataLatencyTag(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/latency/tag", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
-
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if _, err := db.Exec(`DELETE FROM mtr.alert WHERE ID = 'TAUP'`); err != nil {
+		t.Fatal(err)
 	}
 
-	var tr mtrpb.DataLatencyTagResult
-
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	// the TAUP latency mean is 10000 which is below the threshold lower of 12000.
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result == nil {
-		t.Error("got nil for /data/latency/tag protobuf")
+	a := taupAlert(t)
+	if a == nil {
+		t.Fatal("expected an alert for TAUP latency.strong")
 	}
 
-	if len(tr.Result) != 3 {
-		t.Errorf("expected 3 tag results got %d", len(tr.Result))
+	if a.Closed != 0 {
+		t.Errorf("expected an open alert got closed %d", a.Closed)
 	}
 
-	if tr.Result[0].Tag != "DAGG" {
-		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
+	if a.Value != 10000 {
+		t.Errorf("expected value 10000 got %f", a.Value)
 	}
 
-	if tr.Result[0].SiteID != "TAUP" {
-		t.Errorf("expected TAUP as the first siteID got %s", tr.Result[0].Tag)
+	// checking again should not open another alert.
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result[0].TypeID != "latency.strong" {
-		t.Errorf("expected latency.stronge as the first typeID got %s", tr.Result[0].TypeID)
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM mtr.alert WHERE ID = 'TAUP'`).Scan(&n); err != nil {
+		t.Fatal(err)
 	}
 
-	// Test tag result for parameter with siteID+typeID
-	r = wt.Request{ID: wt.L(), URL: "/data/latency/tag?siteID=TAUP&typeID=latency.strong", Accept: "application/x-protobuf"}
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Error(err)
+	if n != 1 {
+		t.Errorf("expected 1 alert for TAUP got %d", n)
 	}
 
-	if tr.Result == nil {
-		t.Error("got nil for /data/latency/tag protobuf")
-	}
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=9000&upper=15000", Method: "PUT",
+		User: userW, Password: keyW}
 
-	if len(tr.Result) != 3 {
-		t.Errorf("expected 3 tag result got %d", len(tr.Result))
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result[0].Tag != "DAGG" {
-		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if tr.Result[0].SiteID != "TAUP" {
-		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].SiteID)
+	if a = taupAlert(t); a == nil {
+		t.Fatal("expected an alert for TAUP latency.strong")
 	}
 
-	if tr.Result[0].TypeID != "latency.strong" {
-		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+	if a.Closed == 0 {
+		t.Error("expected the alert to be closed")
 	}
 }
 
-// protobuf of data latency threshold
-func TestDataLatencyThreshold(t *testing.T) {
+// An alert stays open until the TAUP latency is back inside its threshold by the hysteresis.
+func TestAlertHysteresis(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1130,90 +1451,101 @@ func TestDataLatencyThreshold(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/latency/threshold", Accept: "application/x-protobuf"}
-
-	var b []byte
-	var err error
+	if _, err := db.Exec(`DELETE FROM mtr.alert WHERE ID = 'TAUP'`); err != nil {
+		t.Fatal(err)
+	}
 
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	// the TAUP latency mean is 10000 which is below the threshold lower of 12000.
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	var f mtrpb.DataLatencyThresholdResult
+	// the mean is inside the threshold by 1000, less than the hysteresis.
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=9000&upper=15000&hysteresis=2000", Method: "PUT",
+		User: userW, Password: keyW}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if f.Result == nil {
-		t.Error("got nil for /data/latency/threshold protobuf")
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	a := taupAlert(t)
+	if a == nil {
+		t.Fatal("expected an alert for TAUP latency.strong")
 	}
 
-	d := f.Result[0]
+	if a.Closed != 0 {
+		t.Errorf("expected the alert to stay open got closed %d", a.Closed)
+	}
 
-	if d.SiteID != "TAUP" {
-		t.Errorf("expected TAUP got %s", d.SiteID)
+	r.URL = "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=9000&upper=15000&hysteresis=500"
+
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.TypeID != "latency.strong" {
-		t.Errorf("expected latency.strong got %s", d.TypeID)
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Upper != 15000 {
-		t.Errorf("expected 15000 got %d", d.Upper)
+	if a = taupAlert(t); a == nil {
+		t.Fatal("expected an alert for TAUP latency.strong")
 	}
 
-	if d.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", d.Lower)
+	if a.Closed == 0 {
+		t.Error("expected the alert to be closed")
 	}
 
-	if d.Scale != 1.0 {
-		t.Errorf("expected 1.0 got %f", d.Scale)
+	// inside the threshold by less than the hysteresis doesn't open a new alert.
+	r.URL = "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=9500&upper=15000&hysteresis=1000"
+
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
 	}
 
-	// test again with siteID and typeID
-	r = wt.Request{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.strong&siteID=TAUP", Accept: "application/x-protobuf"}
-	if b, err = r.Do(testServer.URL); err != nil {
-		t.Error(err)
+	if err := checkAlerts(time.Now().UTC()); err != nil {
+		t.Fatal(err)
 	}
 
-	if err = proto.Unmarshal(b, &f); err != nil {
-		t.Error(err)
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM mtr.alert WHERE ID = 'TAUP' AND closed IS NULL`).Scan(&n); err != nil {
+		t.Fatal(err)
 	}
 
-	if len(f.Result) != 1 {
-		t.Error("expected 1 result.")
+	if n != 0 {
+		t.Errorf("expected no open alerts for TAUP got %d", n)
 	}
+}
 
-	d = f.Result[0]
+// taupAlert returns the alert for TAUP latency.strong from /alert or nil if there isn't one.
+func taupAlert(t *testing.T) *mtrpb.Alert {
+	r := wt.Request{ID: wt.L(), URL: "/alert", Accept: "application/x-protobuf"}
 
-	if d.SiteID != "TAUP" {
-		t.Errorf("expected TAUP got %s", d.SiteID)
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if d.TypeID != "latency.strong" {
-		t.Errorf("expected latency.strong got %s", d.TypeID)
-	}
+	var ar mtrpb.AlertResult
 
-	if d.Upper != 15000 {
-		t.Errorf("expected 15000 got %d", d.Upper)
+	if err = proto.Unmarshal(b, &ar); err != nil {
+		t.Fatal(err)
 	}
 
-	if d.Lower != 12000 {
-		t.Errorf("expected 12000 got %d", d.Lower)
+	for _, v := range ar.Result {
+		if v.Source == "data.latency" && v.ID == "TAUP" && v.TypeID == "latency.strong" {
+			return v
+		}
 	}
 
-	if d.Scale != 1.0 {
-		t.Errorf("expected 1.0 got %f", d.Scale)
-	}
+	return nil
 }
 
-// protobuf of /field/model endpoint
-func TestFieldModel(t *testing.T) {
+// All field metric tags as a protobuf.
+func TestFieldMetricTag(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1222,7 +1554,7 @@ func TestFieldModel(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/model", Accept: "application/x-protobuf"}
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/tag", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
@@ -1231,39 +1563,70 @@ func TestFieldModel(t *testing.T) {
 		t.Error(err)
 	}
 
-	var ftr mtrpb.FieldModelResult
+	var tr mtrpb.FieldMetricTagResult
 
-	if err = proto.Unmarshal(b, &ftr); err != nil {
+	if err = proto.Unmarshal(b, &tr); err != nil {
 		t.Error(err)
 	}
 
-	// ftr.Result should be a slice of struct pointers
-	if ftr.Result == nil {
-		t.Error("got nil for /field/model protobuf")
+	if tr.Result == nil {
+		t.Error("got nil for /field/metric/tag protobuf")
 	}
 
-	if len(ftr.Result) != 1 {
-		t.Error("expected 1 result.")
+	if len(tr.Result) != 1 {
+		t.Errorf("expected 1 tag result got %d", len(tr.Result))
 	}
 
-	m := ftr.Result[0]
+	if tr.Result[0].Tag != "TAUP" {
+		t.Errorf("expected TAUP as the first tag got %s", tr.Result[0].Tag)
+	}
 
-	if m.ModelID != "Trimble NetR9" {
-		t.Errorf("expected Trimble NetR9 got %s", m.ModelID)
+	if tr.Result[0].DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].DeviceID)
 	}
-}
 
-// protobuf of /model/device endpoint
-func TestFieldDevice(t *testing.T) {
-	setup(t)
-	defer teardown()
+	if tr.Result[0].TypeID != "voltage" {
+		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+	}
 
-	// Load test data.
-	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+	// Test tag result for parameter with deviceID+typeID
+	r = wt.Request{ID: wt.L(), URL: "/field/metric/tag?deviceID=gps-taupoairport&typeID=voltage", Accept: "application/x-protobuf"}
+	if err = proto.Unmarshal(b, &tr); err != nil {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/device", Accept: "application/x-protobuf"}
+	if tr.Result == nil {
+		t.Error("got nil for /field/metric/tag protobuf")
+	}
+
+	if len(tr.Result) != 1 {
+		t.Errorf("expected 1 tag result got %d", len(tr.Result))
+	}
+
+	if tr.Result[0].Tag != "TAUP" {
+		t.Errorf("expected TAUP as the first tag got %s", tr.Result[0].Tag)
+	}
+
+	if tr.Result[0].DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].DeviceID)
+	}
+
+	if tr.Result[0].TypeID != "voltage" {
+		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+	}
+}
+
+// protobuf of field metrics and latencies for a single tag.
+func TestTag(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/tag/TAUP", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
@@ -1272,42 +1635,46 @@ func TestFieldDevice(t *testing.T) {
 		t.Error(err)
 	}
 
-	var fdr mtrpb.FieldDeviceResult
+	var tr mtrpb.TagSearchResult
 
-	if err = proto.Unmarshal(b, &fdr); err != nil {
+	if err = proto.Unmarshal(b, &tr); err != nil {
 		t.Error(err)
 	}
 
-	// ftr.Result should be a slice of struct pointers
-	if fdr.Result == nil {
-		t.Error("got nil for /field/device protobuf")
+	if tr.FieldMetric == nil {
+		t.Error("Got nil FieldMetric")
 	}
 
-	if len(fdr.Result) != 1 {
-		t.Error("expected 1 result.")
+	if tr.DataLatency == nil {
+		t.Error("Got nil DataLatency")
 	}
 
-	d := fdr.Result[0]
+	if tr.FieldState == nil {
+		t.Error("Got nil FieldState")
+	}
 
-	if d.ModelID != "Trimble NetR9" {
-		t.Errorf("expected Trimble NetR9 got %s", d.ModelID)
+	if tr.DataCompleteness == nil {
+		t.Error("Got nil DataCompleteness")
 	}
 
-	if d.DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
+	if tr.FieldMetric[0].DeviceID != "gps-taupoairport" {
+		t.Errorf("expected deviceID gps-taupoairport got %s", tr.FieldMetric[0].DeviceID)
 	}
 
-	if d.Latitude != -38.7427 {
-		t.Errorf("expected -38.7427 got %s", d.Latitude)
+	if tr.DataLatency[0].SiteID != "TAUP" {
+		t.Errorf("expected siteID TAUP got %s", tr.DataLatency[0].SiteID)
 	}
 
-	if d.Longitude != 176.081 {
-		t.Errorf("expected 176.081 got %s", d.Longitude)
+	if tr.FieldState[0].DeviceID != "gps-taupoairport" {
+		t.Errorf("expected deviceID gps-taupoairport got %s", tr.FieldState[0].DeviceID)
+	}
+
+	if tr.DataCompleteness[0].SiteID != "TAUP" {
+		t.Errorf("expected siteID TAUP got %s", tr.DataCompleteness[0].SiteID)
 	}
 }
 
-// protobuf of /model/type endpoint
-func TestFieldType(t *testing.T) {
+func TestFieldDeviceDetail(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1316,7 +1683,7 @@ func TestFieldType(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/type", Accept: "application/x-protobuf"}
+	r := wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-taupoairport", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
@@ -1325,31 +1692,36 @@ func TestFieldType(t *testing.T) {
 		t.Error(err)
 	}
 
-	var ftr mtrpb.FieldTypeResult
+	var d mtrpb.FieldDeviceDetail
 
-	if err = proto.Unmarshal(b, &ftr); err != nil {
+	if err = proto.Unmarshal(b, &d); err != nil {
 		t.Error(err)
 	}
 
-	// ftr.Result should be a slice of struct pointers
-	if ftr.Result == nil {
-		t.Error("got nil for /field/type protobuf")
+	if d.Device == nil || d.Device.ModelID != "Trimble NetR9" {
+		t.Errorf("expected device with model Trimble NetR9 got %v", d.Device)
 	}
 
-	if len(ftr.Result) != 12 {
-		t.Error("expected 12 results.")
+	if d.Metric == nil {
+		t.Error("Got nil Metric")
 	}
 
-	if ftr.Result[0].TypeID != "centre" {
-		t.Errorf("expected centre got %s", ftr.Result[0].TypeID)
+	if d.State == nil {
+		t.Error("Got nil State")
 	}
 
-	if ftr.Result[0].Display != "mV" {
-		t.Errorf("expected mV got %s", ftr.Result[0].Display)
+	if d.MetricTag == nil {
+		t.Error("Got nil MetricTag")
+	}
+
+	for _, m := range d.Metric {
+		if m.DeviceID != "gps-taupoairport" {
+			t.Errorf("expected deviceID gps-taupoairport got %s", m.DeviceID)
+		}
 	}
 }
 
-func TestDataTypes(t *testing.T) {
+func TestDataSiteDetail(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1358,7 +1730,7 @@ func TestDataTypes(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/data/type", Accept: "application/x-protobuf"}
+	r := wt.Request{ID: wt.L(), URL: "/data/site/detail?siteID=TAUP", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
@@ -1367,31 +1739,32 @@ func TestDataTypes(t *testing.T) {
 		t.Error(err)
 	}
 
-	var dtr mtrpb.DataTypeResult
+	var d mtrpb.DataSiteDetail
 
-	if err = proto.Unmarshal(b, &dtr); err != nil {
+	if err = proto.Unmarshal(b, &d); err != nil {
 		t.Error(err)
 	}
 
-	if dtr.Result == nil {
-		t.Error("got nil for /data/type protobuf")
+	if d.Site == nil || d.Site.SiteID != "TAUP" {
+		t.Errorf("expected site TAUP got %v", d.Site)
 	}
 
-	if len(dtr.Result) != 5 {
-		t.Errorf("expected 5 results, got %d.", len(dtr.Result))
+	if d.Latency == nil {
+		t.Error("Got nil Latency")
 	}
 
-	if dtr.Result[0].TypeID != "latency.files.gnss" {
-		t.Errorf("expected latency.files.gnss got %s", dtr.Result[0].TypeID)
+	if d.Completeness == nil {
+		t.Error("Got nil Completeness")
 	}
 
-	if dtr.Result[0].Display != "ms" {
-		t.Errorf("expected ms got %s", dtr.Result[0].Display)
+	if d.LatencyTag == nil {
+		t.Error("Got nil LatencyTag")
 	}
 }
 
-// protobuf for /app endpoint
-func TestAppIDs(t *testing.T) {
+// TestDetailID checks that devices and sites with the same ID as an endpoint under /field/device
+// or /data/site can be fetched.
+func TestDetailID(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1400,7 +1773,62 @@ func TestAppIDs(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/app", Accept: "application/x-protobuf"}
+	for _, id := range []string{"attribute", "location", "rename", "detail"} {
+		r := wt.Requests{
+			{ID: wt.L(), URL: "/field/device?deviceID=" + id + "&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100", Method: "PUT", User: userW, Password: keyW},
+			{ID: wt.L(), URL: "/data/site?siteID=" + id + "&latitude=-38.74270&longitude=176.08100", Method: "PUT", User: userW, Password: keyW},
+		}
+
+		if err := r.DoAllStatusOk(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		fr := wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=" + id, Accept: "application/x-protobuf"}
+
+		b, err := fr.Do(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var f mtrpb.FieldDeviceDetail
+
+		if err = proto.Unmarshal(b, &f); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.Device == nil || f.Device.DeviceID != id {
+			t.Errorf("expected device %s got %v", id, f.Device)
+		}
+
+		dr := wt.Request{ID: wt.L(), URL: "/data/site/detail?siteID=" + id, Accept: "application/x-protobuf"}
+
+		if b, err = dr.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		var d mtrpb.DataSiteDetail
+
+		if err = proto.Unmarshal(b, &d); err != nil {
+			t.Fatal(err)
+		}
+
+		if d.Site == nil || d.Site.SiteID != id {
+			t.Errorf("expected site %s got %v", id, d.Site)
+		}
+	}
+}
+
+// all tags as a protobuf
+func TestTagAll(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/tag", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
@@ -1409,27 +1837,23 @@ func TestAppIDs(t *testing.T) {
 		t.Error(err)
 	}
 
-	var dtr mtrpb.AppIDSummaryResult
+	var tr mtrpb.TagResult
 
-	if err = proto.Unmarshal(b, &dtr); err != nil {
+	if err = proto.Unmarshal(b, &tr); err != nil {
 		t.Error(err)
 	}
 
-	if dtr.Result == nil {
-		t.Error("got nil for /app protobuf")
-	}
-
-	if len(dtr.Result) != 1 {
-		t.Errorf("expected 1 result, got %d.", len(dtr.Result))
+	if len(tr.Result) != 6 {
+		t.Errorf("expected 6 tags got %d", len(tr.Result))
 	}
 
-	if dtr.Result[0].ApplicationID != "test-app" {
-		t.Errorf("expected test-app got %s", dtr.Result[0].ApplicationID)
+	if tr.Result[0].Tag != "DAGG" {
+		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
 	}
 }
 
-// protobuf for /field/state endpoint
-func TestFieldState(t *testing.T) {
+// protobuf of latency summary info.
+func TestDataLatencySummary(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1438,51 +1862,87 @@ func TestFieldState(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/state", Accept: "application/x-protobuf"}
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/summary", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
 
-	// get the protobuf data back
 	if b, err = r.Do(testServer.URL); err != nil {
-		t.Fatal(err)
+		t.Error(err)
 	}
 
-	var dtr mtrpb.FieldStateResult
+	var f mtrpb.DataLatencySummaryResult
 
-	if err = proto.Unmarshal(b, &dtr); err != nil {
-		t.Fatal(err)
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
 	}
 
-	if dtr.Result == nil {
-		t.Fatal("got nil for /field/state protobuf")
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
 	}
 
-	if len(dtr.Result) != 1 {
-		t.Fatalf("expected 1 result, got %d.", len(dtr.Result))
+	d := f.Result[0]
+
+	if d.SiteID != "TAUP" {
+		t.Errorf("expected TAUP got %s", d.SiteID)
 	}
 
-	res := dtr.Result[0]
+	if d.TypeID != "latency.strong" {
+		t.Errorf("expected latency.strong got %s", d.TypeID)
+	}
 
-	if res.DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport got %s", res.DeviceID)
+	if d.FloatMean != 10000 {
+		t.Errorf("expected 10000 got %g", d.FloatMean)
 	}
 
-	if res.TypeID != "mains" {
-		t.Errorf("expected mains got %s", res.TypeID)
+	if d.FloatFifty != 0 {
+		t.Errorf("expected 0 got %g", d.FloatFifty)
 	}
 
-	if res.Seconds != 1431639630 {
-		t.Errorf("expected 1431639630 got %d", res.Seconds)
+	if d.FloatNinety != 0 {
+		t.Errorf("expected 0 got %g", d.FloatNinety)
 	}
 
-	if res.Value != true {
-		t.Errorf("expected true got %s", res.Value)
+	if d.Seconds == 0 {
+		t.Error("unexpected zero seconds")
+	}
+
+	if d.FloatUpper != 15000 {
+		t.Errorf("expected 15000 got %g", d.FloatUpper)
+	}
+
+	if d.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", d.FloatLower)
+	}
+
+	if d.Scale != 1.0 {
+		t.Errorf("expected 1.0 got %f", d.Scale)
+	}
+
+	// the TAUP latency is older than the 300s interval for the site.
+	if !d.Late {
+		t.Error("expected late")
+	}
+
+	r.URL = "/data/latency/summary?typeID=latency.strong"
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	f.Reset()
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
 	}
 }
 
-// protobuf for /field/state/tag endpoint
-func TestFieldStateTag(t *testing.T) {
+// protobuf of latency history log info.
+func TestDataLatencyHistoryLog(t *testing.T) {
 	setup(t)
 	defer teardown()
 
@@ -1491,41 +1951,1580 @@ func TestFieldStateTag(t *testing.T) {
 		t.Error(err)
 	}
 
-	r := wt.Request{ID: wt.L(), URL: "/field/state/tag", Method: "GET", Accept: "application/x-protobuf"}
+	r := wt.Request{ID: wt.L(), URL: "/data/latency?siteID=TAUP&typeID=latency.strong&resolution=minute", Accept: "application/x-protobuf"}
 
 	var b []byte
 	var err error
 
-	// get the protobuf data back
 	if b, err = r.Do(testServer.URL); err != nil {
-		t.Fatal(err)
+		t.Error(err)
 	}
 
-	var tr mtrpb.FieldStateTagResult
+	var f mtrpb.DataLatencyResult
 
-	if err = proto.Unmarshal(b, &tr); err != nil {
-		t.Fatal(err)
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
 	}
 
-	if tr.Result == nil {
-		t.Fatal("got nil for /field/state protobuf")
+	if f.SiteID != "TAUP" {
+		t.Errorf("expected TAUP got %s", f.SiteID)
 	}
 
-	if len(tr.Result) != 1 {
-		t.Fatalf("expected 1 result, got %d.", len(tr.Result))
+	if f.TypeID != "latency.strong" {
+		t.Errorf("expected latency.strong got %s", f.TypeID)
 	}
 
-	res := tr.Result[0]
+	if f.FloatUpper != 15000 {
+		t.Errorf("expected 15000 got %g", f.FloatUpper)
+	}
 
-	if res.DeviceID != "gps-taupoairport" {
-		t.Errorf("expected gps-taupoairport got %s", res.DeviceID)
+	if f.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", f.FloatLower)
 	}
 
-	if res.TypeID != "mains" {
-		t.Errorf("expected mains got %s", res.TypeID)
+	if f.Scale != 1.0 {
+		t.Errorf("expected 1.0 got %f", f.Scale)
 	}
+	// Not testing number of latency log
+}
 
-	if res.Tag != "TAUP" {
-		t.Errorf("expected TAUP got %s", res.Tag)
+// Batch upload of data latency and completeness as protobuf.
+func TestDataBatch(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	l := mtrpb.DataLatencyBatch{Row: []*mtrpb.DataLatencyBatchRow{
+		{SiteID: "TAUP", TypeID: "latency.strong", Seconds: now.Add(time.Minute * -2).Unix(), Mean: 11000, Fifty: 10000, Ninety: 13000},
+		{SiteID: "TAUP", TypeID: "latency.strong", Seconds: now.Add(time.Minute * -1).Unix(), Mean: 12000, Fifty: 11000, Ninety: 14000},
+		// same minute as the previous row.
+		{SiteID: "TAUP", TypeID: "latency.strong", Seconds: now.Add(time.Minute*-1 + time.Second).Unix(), Mean: 13000},
+		{SiteID: "NOSITE", TypeID: "latency.strong", Seconds: now.Unix(), Mean: 14000},
+	}}
+
+	var b []byte
+	var err error
+
+	if b, err = proto.Marshal(&l); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = postBatch("/data/latency", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
+	}
+
+	var res mtrpb.BatchResult
+
+	if err = proto.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Result) != 4 {
+		t.Fatalf("expected 4 results got %d", len(res.Result))
+	}
+
+	for i, c := range []int32{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusBadRequest} {
+		if res.Result[i].Code != c {
+			t.Errorf("latency row %d expected code %d got %d", i, c, res.Result[i].Code)
+		}
+	}
+
+	// the summary has the newest values from the batch.
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/summary", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var s mtrpb.DataLatencySummaryResult
+
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Result) != 1 {
+		t.Fatalf("expected 1 summary got %d", len(s.Result))
+	}
+
+	if s.Result[0].FloatMean != 12000 {
+		t.Errorf("expected 12000 got %g", s.Result[0].FloatMean)
+	}
+
+	if s.Result[0].FloatNinety != 14000 {
+		t.Errorf("expected 14000 got %g", s.Result[0].FloatNinety)
+	}
+
+	c := mtrpb.DataCompletenessBatch{Row: []*mtrpb.DataCompletenessBatchRow{
+		{SiteID: "TAUP", TypeID: "completeness.gnss.1hz", Seconds: now.Add(time.Minute * -5).Unix(), Count: 300},
+		{SiteID: "TAUP", TypeID: "completeness.nosuchtype", Seconds: now.Unix(), Count: 300},
+	}}
+
+	if b, err = proto.Marshal(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = postBatch("/data/completeness", "application/x-protobuf", b); err != nil {
+		t.Fatal(err)
+	}
+
+	res.Reset()
+
+	if err = proto.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Result) != 2 {
+		t.Fatalf("expected 2 results got %d", len(res.Result))
+	}
+
+	for i, c := range []int32{http.StatusOK, http.StatusBadRequest} {
+		if res.Result[i].Code != c {
+			t.Errorf("completeness row %d expected code %d got %d", i, c, res.Result[i].Code)
+		}
+	}
+}
+
+// protobuf of latency summary info.
+func TestDataCompletenessSummary(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/data/completeness/summary", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var f mtrpb.DataCompletenessSummaryResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d := f.Result[0]
+
+	if d.SiteID != "TAUP" {
+		t.Errorf("expected TAUP got %s", d.SiteID)
+	}
+
+	if d.TypeID != "completeness.gnss.1hz" {
+		t.Errorf("expected gnss.1hz got %s", d.TypeID)
+	}
+
+	if d.Seconds == 0 {
+		t.Error("unexpected zero seconds")
+	}
+
+	r.URL = "/data/completeness/summary?typeID=completeness.gnss.1hz"
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	f.Reset()
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 1 {
+		t.Errorf("expected 1 result got %d results", len(f.Result))
+	}
+}
+
+// thresholds are inherited from the template for the model or type unless they are overridden.
+func TestThresholdTemplate(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=clock&lower=10&upper=100", Method: "PUT"},
+		{ID: wt.L(), URL: "/field/metric/threshold?modelID=Trimble+NetR9&typeID=voltage&lower=1&upper=2", Method: "PUT"},
+		{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak&lower=0&upper=30000", Method: "PUT"},
+		{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=clock&time=2015-05-14T21:40:30Z&value=50", Method: "PUT"},
+		{ID: wt.L(), URL: "/data/latency?siteID=WGTN&typeID=latency.weak&time=2015-05-14T23:40:30Z&mean=10000", Method: "PUT"},
+	} {
+		r.User = userW
+		r.Password = keyW
+
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/threshold", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var f mtrpb.FieldMetricThresholdResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Template) != 2 {
+		t.Errorf("expected 2 templates got %d", len(f.Template))
+	}
+
+	for _, th := range f.Result {
+		switch th.TypeID {
+		case "voltage":
+			if th.Inherited || th.FloatUpper != 45000 {
+				t.Errorf("expected voltage overridden for the device got %v", th)
+			}
+		case "clock":
+			if !th.Inherited || th.FloatLower != 10 || th.FloatUpper != 100 || th.ModelID != "Trimble NetR9" {
+				t.Errorf("expected clock inherited from the model got %v", th)
+			}
+		}
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=clock", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var s mtrpb.FieldMetricSummaryResult
+
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Result) != 1 || s.Result[0].Unknown || s.Result[0].FloatUpper != 100 {
+		t.Errorf("expected clock summary with the model threshold got %v", s.Result)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.weak", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var d mtrpb.DataLatencyThresholdResult
+
+	if err = proto.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.Template) != 1 || d.Template[0].FloatUpper != 30000 {
+		t.Errorf("expected the latency.weak template got %v", d.Template)
+	}
+
+	var found bool
+
+	for _, th := range d.Result {
+		if th.SiteID == "WGTN" {
+			found = true
+			if !th.Inherited || th.FloatUpper != 30000 {
+				t.Errorf("expected WGTN inherited from the type got %v", th)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected a threshold for WGTN")
+	}
+}
+
+// metrics without a threshold are in the summaries as unknown and in the missing threshold lists.
+func TestThresholdMissing(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=conn&time=2015-05-14T21:40:30Z&value=25000", Method: "PUT",
+		User: userW, Password: keyW}
+
+	var b []byte
+	var err error
+
+	if _, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var f mtrpb.FieldMetricSummaryResult
+
+	for _, u := range []string{"/field/metric/summary", "/field/metric/threshold/missing"} {
+		r = wt.Request{ID: wt.L(), URL: u, Accept: "application/x-protobuf"}
+
+		if b, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		f.Reset()
+
+		if err = proto.Unmarshal(b, &f); err != nil {
+			t.Fatal(err)
+		}
+
+		var found bool
+
+		for _, m := range f.Result {
+			switch m.TypeID {
+			case "conn":
+				found = true
+				if !m.Unknown || m.FloatLower != 0 || m.FloatUpper != 0 {
+					t.Errorf("%s expected conn to be unknown got %v", u, m)
+				}
+			case "voltage":
+				if u == "/field/metric/threshold/missing" {
+					t.Errorf("%s voltage has a threshold", u)
+				}
+				if m.Unknown {
+					t.Errorf("%s expected voltage not unknown", u)
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("%s expected conn without a threshold", u)
+		}
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/data/latency?siteID=WGTN&typeID=latency.strong&time=2015-05-14T23:40:30Z&mean=10000", Method: "PUT",
+		User: userW, Password: keyW}
+
+	if _, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var d mtrpb.DataLatencySummaryResult
+
+	for _, u := range []string{"/data/latency/summary", "/data/latency/threshold/missing"} {
+		r = wt.Request{ID: wt.L(), URL: u, Accept: "application/x-protobuf"}
+
+		if b, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		d.Reset()
+
+		if err = proto.Unmarshal(b, &d); err != nil {
+			t.Fatal(err)
+		}
+
+		var found bool
+
+		for _, l := range d.Result {
+			if l.SiteID == "WGTN" {
+				found = true
+				if !l.Unknown {
+					t.Errorf("%s expected WGTN to be unknown", u)
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("%s expected WGTN without a threshold", u)
+		}
+	}
+}
+
+// protobuf of field metric summary info.
+func TestFieldMetricsSummary(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/summary", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var f mtrpb.FieldMetricSummaryResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d := f.Result[0]
+
+	if d.DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
+	}
+
+	if d.ModelID != "Trimble NetR9" {
+		t.Errorf("expected Trimble NetR9 got %s", d.ModelID)
+	}
+
+	if d.TypeID != "voltage" {
+		t.Errorf("expected voltage got %s", d.TypeID)
+	}
+
+	if d.FloatValue != 14100 {
+		t.Errorf("expected 14100 got %g", d.FloatValue)
+	}
+
+	if d.Seconds == 0 {
+		t.Error("unexpected zero seconds")
+	}
+
+	if d.FloatUpper != 45000 {
+		t.Errorf("expected 45000 got %g", d.FloatUpper)
+	}
+
+	if d.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", d.FloatLower)
+	}
+
+	if d.Scale != 0.001 {
+		t.Errorf("expected 0.001 got %f", d.Scale)
+	}
+
+	// should be no errors and empty result for typeID=conn
+	r.URL = "/field/metric/summary?typeID=conn"
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	f.Reset()
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 0 {
+		t.Error("expected 0 results.")
+	}
+}
+
+// protobuf of field metric threshold info.
+func TestFieldMetricsThreshold(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/threshold", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var f mtrpb.FieldMetricThresholdResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if f.Result == nil {
+		t.Error("got nil for /field/metric/threshold protobuf")
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d := f.Result[0]
+
+	if d.DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
+	}
+
+	if d.TypeID != "voltage" {
+		t.Errorf("expected voltage got %s", d.TypeID)
+	}
+
+	if d.FloatUpper != 45000 {
+		t.Errorf("expected 45000 got %g", d.FloatUpper)
+	}
+
+	if d.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", d.FloatLower)
+	}
+
+	if d.Scale != 0.001 {
+		t.Errorf("expected 0.001 got %f", d.Scale)
+	}
+}
+
+// protobuf of data sites.
+func TestDataSites(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/data/site", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var f mtrpb.DataSiteResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if f.Result == nil {
+		t.Error("got nil for /data/site protobuf")
+	}
+
+	if len(f.Result) != 2 {
+		t.Error("expected 2 results.")
+	}
+
+	var found bool
+
+	for _, v := range f.Result {
+		if v.SiteID == "TAUP" {
+			found = true
+
+			if v.Latitude != -38.74270 {
+				t.Errorf("Data site TAUP got expected latitude -38.74270 got %f", v.Latitude)
+			}
+
+			if v.Longitude != 176.08100 {
+				t.Errorf("Data site TAUP got expected longitude 176.08100 got %f", v.Longitude)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("Didn't find site TAUP")
+	}
+}
+
+// All data latency tags as a protobuf.
+func TestDataLatencyTag(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/tag", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tr mtrpb.DataLatencyTagResult
+
+	if err = proto.Unmarshal(b, &tr); err != nil {
+		t.Error(err)
+	}
+
+	if tr.Result == nil {
+		t.Error("got nil for /data/latency/tag protobuf")
+	}
+
+	if len(tr.Result) != 3 {
+		t.Errorf("expected 3 tag results got %d", len(tr.Result))
+	}
+
+	if tr.Result[0].Tag != "DAGG" {
+		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
+	}
+
+	if tr.Result[0].SiteID != "TAUP" {
+		t.Errorf("expected TAUP as the first siteID got %s", tr.Result[0].Tag)
+	}
+
+	if tr.Result[0].TypeID != "latency.strong" {
+		t.Errorf("expected latency.stronge as the first typeID got %s", tr.Result[0].TypeID)
+	}
+
+	// Test tag result for parameter with siteID+typeID
+	r = wt.Request{ID: wt.L(), URL: "/data/latency/tag?siteID=TAUP&typeID=latency.strong", Accept: "application/x-protobuf"}
+	if err = proto.Unmarshal(b, &tr); err != nil {
+		t.Error(err)
+	}
+
+	if tr.Result == nil {
+		t.Error("got nil for /data/latency/tag protobuf")
+	}
+
+	if len(tr.Result) != 3 {
+		t.Errorf("expected 3 tag result got %d", len(tr.Result))
+	}
+
+	if tr.Result[0].Tag != "DAGG" {
+		t.Errorf("expected DAGG as the first tag got %s", tr.Result[0].Tag)
+	}
+
+	if tr.Result[0].SiteID != "TAUP" {
+		t.Errorf("expected gps-taupoairport as the first deviceID got %s", tr.Result[0].SiteID)
+	}
+
+	if tr.Result[0].TypeID != "latency.strong" {
+		t.Errorf("expected voltage as the first typeID got %s", tr.Result[0].TypeID)
+	}
+}
+
+// protobuf of data latency threshold
+func TestDataLatencyThreshold(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/data/latency/threshold", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var f mtrpb.DataLatencyThresholdResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if f.Result == nil {
+		t.Error("got nil for /data/latency/threshold protobuf")
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d := f.Result[0]
+
+	if d.SiteID != "TAUP" {
+		t.Errorf("expected TAUP got %s", d.SiteID)
+	}
+
+	if d.TypeID != "latency.strong" {
+		t.Errorf("expected latency.strong got %s", d.TypeID)
+	}
+
+	if d.FloatUpper != 15000 {
+		t.Errorf("expected 15000 got %g", d.FloatUpper)
+	}
+
+	if d.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", d.FloatLower)
+	}
+
+	if d.Scale != 1.0 {
+		t.Errorf("expected 1.0 got %f", d.Scale)
+	}
+
+	// test again with siteID and typeID
+	r = wt.Request{ID: wt.L(), URL: "/data/latency/threshold?typeID=latency.strong&siteID=TAUP", Accept: "application/x-protobuf"}
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Error(err)
+	}
+
+	if len(f.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d = f.Result[0]
+
+	if d.SiteID != "TAUP" {
+		t.Errorf("expected TAUP got %s", d.SiteID)
+	}
+
+	if d.TypeID != "latency.strong" {
+		t.Errorf("expected latency.strong got %s", d.TypeID)
+	}
+
+	if d.FloatUpper != 15000 {
+		t.Errorf("expected 15000 got %g", d.FloatUpper)
+	}
+
+	if d.FloatLower != 12000 {
+		t.Errorf("expected 12000 got %g", d.FloatLower)
+	}
+
+	if d.Scale != 1.0 {
+		t.Errorf("expected 1.0 got %f", d.Scale)
+	}
+}
+
+// protobuf of /field/model endpoint
+func TestFieldModel(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/model", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var ftr mtrpb.FieldModelResult
+
+	if err = proto.Unmarshal(b, &ftr); err != nil {
+		t.Error(err)
+	}
+
+	// ftr.Result should be a slice of struct pointers
+	if ftr.Result == nil {
+		t.Error("got nil for /field/model protobuf")
+	}
+
+	if len(ftr.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	m := ftr.Result[0]
+
+	if m.ModelID != "Trimble NetR9" {
+		t.Errorf("expected Trimble NetR9 got %s", m.ModelID)
+	}
+}
+
+// protobuf of /model/device endpoint
+func TestFieldDevice(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/device", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var fdr mtrpb.FieldDeviceResult
+
+	if err = proto.Unmarshal(b, &fdr); err != nil {
+		t.Error(err)
+	}
+
+	// ftr.Result should be a slice of struct pointers
+	if fdr.Result == nil {
+		t.Error("got nil for /field/device protobuf")
+	}
+
+	if len(fdr.Result) != 1 {
+		t.Error("expected 1 result.")
+	}
+
+	d := fdr.Result[0]
+
+	if d.ModelID != "Trimble NetR9" {
+		t.Errorf("expected Trimble NetR9 got %s", d.ModelID)
+	}
+
+	if d.DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport got %s", d.DeviceID)
+	}
+
+	if d.Latitude != -38.7427 {
+		t.Errorf("expected -38.7427 got %s", d.Latitude)
+	}
+
+	if d.Longitude != 176.081 {
+		t.Errorf("expected 176.081 got %s", d.Longitude)
+	}
+}
+
+// protobuf of /model/type endpoint
+func TestFieldType(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/type", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var ftr mtrpb.FieldTypeResult
+
+	if err = proto.Unmarshal(b, &ftr); err != nil {
+		t.Error(err)
+	}
+
+	// ftr.Result should be a slice of struct pointers
+	if ftr.Result == nil {
+		t.Error("got nil for /field/type protobuf")
+	}
+
+	if len(ftr.Result) != 12 {
+		t.Error("expected 12 results.")
+	}
+
+	if ftr.Result[0].TypeID != "centre" {
+		t.Errorf("expected centre got %s", ftr.Result[0].TypeID)
+	}
+
+	if ftr.Result[0].Display != "mV" {
+		t.Errorf("expected mV got %s", ftr.Result[0].Display)
+	}
+}
+
+// types with data are only deleted if forced.
+func TestTypeDelete(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/type?typeID=test.temp&description=temperature&unit=mC&scale=0.001&display=C", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=test.temp&time=2015-05-14T21:40:30Z&value=14100", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/type?typeID=test.temp", Method: "DELETE", User: userW, Password: keyW, Status: http.StatusConflict},
+	} {
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	types := func() map[string]*mtrpb.FieldType {
+		r := wt.Request{ID: wt.L(), URL: "/field/type", Accept: "application/x-protobuf"}
+
+		b, err := r.Do(testServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ftr mtrpb.FieldTypeResult
+
+		if err = proto.Unmarshal(b, &ftr); err != nil {
+			t.Fatal(err)
+		}
+
+		m := make(map[string]*mtrpb.FieldType)
+
+		for _, v := range ftr.Result {
+			m[v.TypeID] = v
+		}
+
+		return m
+	}
+
+	ft, ok := types()["test.temp"]
+	if !ok {
+		t.Fatal("expected test.temp after an unforced delete")
+	}
+
+	if ft.Description != "temperature" || ft.Unit != "mC" || ft.Scale != 0.001 || ft.Display != "C" {
+		t.Errorf("unexpected type %+v", ft)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/type?typeID=test.temp&force=true", Method: "DELETE", User: userW, Password: keyW}
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok = types()["test.temp"]; ok {
+		t.Error("expected test.temp to be deleted")
+	}
+}
+
+func TestDataTypes(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/data/type", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var dtr mtrpb.DataTypeResult
+
+	if err = proto.Unmarshal(b, &dtr); err != nil {
+		t.Error(err)
+	}
+
+	if dtr.Result == nil {
+		t.Error("got nil for /data/type protobuf")
+	}
+
+	if len(dtr.Result) != 5 {
+		t.Errorf("expected 5 results, got %d.", len(dtr.Result))
+	}
+
+	if dtr.Result[0].TypeID != "latency.files.gnss" {
+		t.Errorf("expected latency.files.gnss got %s", dtr.Result[0].TypeID)
+	}
+
+	if dtr.Result[0].Display != "ms" {
+		t.Errorf("expected ms got %s", dtr.Result[0].Display)
+	}
+}
+
+// protobuf for /app endpoint
+func TestAppIDs(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/app", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	var dtr mtrpb.AppIDSummaryResult
+
+	if err = proto.Unmarshal(b, &dtr); err != nil {
+		t.Error(err)
+	}
+
+	if dtr.Result == nil {
+		t.Error("got nil for /app protobuf")
+	}
+
+	if len(dtr.Result) != 1 {
+		t.Errorf("expected 1 result, got %d.", len(dtr.Result))
+	}
+
+	if dtr.Result[0].ApplicationID != "test-app" {
+		t.Errorf("expected test-app got %s", dtr.Result[0].ApplicationID)
+	}
+}
+
+// protobuf for /field/state endpoint
+func TestFieldState(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/state", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	// get the protobuf data back
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var dtr mtrpb.FieldStateResult
+
+	if err = proto.Unmarshal(b, &dtr); err != nil {
+		t.Fatal(err)
+	}
+
+	if dtr.Result == nil {
+		t.Fatal("got nil for /field/state protobuf")
+	}
+
+	if len(dtr.Result) != 1 {
+		t.Fatalf("expected 1 result, got %d.", len(dtr.Result))
+	}
+
+	res := dtr.Result[0]
+
+	if res.DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport got %s", res.DeviceID)
+	}
+
+	if res.TypeID != "mains" {
+		t.Errorf("expected mains got %s", res.TypeID)
+	}
+
+	if res.Seconds != 1431639630 {
+		t.Errorf("expected 1431639630 got %d", res.Seconds)
+	}
+
+	if res.Value != true {
+		t.Errorf("expected true got %s", res.Value)
+	}
+}
+
+// changes of state for /field/state/history
+func TestFieldStateHistory(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	// a mains outage, repeated states are not changes.
+	for _, v := range []string{
+		"time=2015-05-14T22:40:30Z&value=false",
+		"time=2015-05-14T22:50:30Z&value=false",
+		"time=2015-05-14T23:40:30Z&value=true",
+	} {
+		r := wt.Request{ID: wt.L(), URL: "/field/state?deviceID=gps-taupoairport&typeID=mains&" + v, Method: "PUT", User: userW, Password: keyW}
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Error(err)
+		}
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/state/history?deviceID=gps-taupoairport&typeID=mains&startDate=2015-05-14T22:00:00Z&endDate=2015-05-15T00:00:00Z", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var fr mtrpb.FieldStateResult
+
+	if err = proto.Unmarshal(b, &fr); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first result is the state at the start of the range.
+	expected := []struct {
+		seconds int64
+		value   bool
+	}{
+		{1431639630, true},
+		{1431643230, false},
+		{1431646830, true},
+	}
+
+	if len(fr.Result) != len(expected) {
+		t.Fatalf("expected %d results got %d", len(expected), len(fr.Result))
+	}
+
+	for i, e := range expected {
+		if fr.Result[i].Seconds != e.seconds || fr.Result[i].Value != e.value {
+			t.Errorf("result %d expected %d %t got %d %t", i, e.seconds, e.value, fr.Result[i].Seconds, fr.Result[i].Value)
+		}
+	}
+}
+
+// protobuf for /field/state/tag endpoint
+func TestFieldStateTag(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/state/tag", Method: "GET", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	// get the protobuf data back
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var tr mtrpb.FieldStateTagResult
+
+	if err = proto.Unmarshal(b, &tr); err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Result == nil {
+		t.Fatal("got nil for /field/state protobuf")
+	}
+
+	if len(tr.Result) != 1 {
+		t.Fatalf("expected 1 result, got %d.", len(tr.Result))
+	}
+
+	res := tr.Result[0]
+
+	if res.DeviceID != "gps-taupoairport" {
+		t.Errorf("expected gps-taupoairport got %s", res.DeviceID)
+	}
+
+	if res.TypeID != "mains" {
+		t.Errorf("expected mains got %s", res.TypeID)
+	}
+
+	if res.Tag != "TAUP" {
+		t.Errorf("expected TAUP got %s", res.Tag)
+	}
+}
+
+// warning thresholds are in the thresholds and summaries.
+func TestThresholdWarning(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/metric/threshold?deviceID=gps-taupoairport&typeID=voltage&lower=12000&upper=45000&warningLower=13000&warningUpper=40000&hysteresis=100", Method: "PUT"},
+		{ID: wt.L(), URL: "/data/latency/threshold?siteID=TAUP&typeID=latency.strong&lower=9000&upper=15000&warningLower=11000&warningUpper=14000", Method: "PUT"},
+	} {
+		r.User = userW
+		r.Password = keyW
+
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/metric/threshold", Accept: "application/x-protobuf"}
+
+	var b []byte
+	var err error
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var f mtrpb.FieldMetricThresholdResult
+
+	if err = proto.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+
+	for _, th := range f.Result {
+		if th.DeviceID == "gps-taupoairport" && th.TypeID == "voltage" {
+			found = true
+			if th.FloatWarningLower != 13000 || th.FloatWarningUpper != 40000 || th.FloatHysteresis != 100 {
+				t.Errorf("expected voltage warning threshold got %v", th)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected a voltage threshold for gps-taupoairport")
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var s mtrpb.FieldMetricSummaryResult
+
+	if err = proto.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Result) != 1 || s.Result[0].FloatWarningLower != 13000 || s.Result[0].FloatWarningUpper != 40000 {
+		t.Errorf("expected voltage summary with the warning threshold got %v", s.Result)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/data/latency/summary?typeID=latency.strong", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var d mtrpb.DataLatencySummaryResult
+
+	if err = proto.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	found = false
+
+	for _, l := range d.Result {
+		if l.SiteID == "TAUP" {
+			found = true
+			if l.FloatWarningLower != 11000 || l.FloatWarningUpper != 14000 {
+				t.Errorf("expected TAUP latency summary with the warning threshold got %v", l)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected a latency summary for TAUP")
+	}
+}
+
+func TestDeviceStatus(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-taupoairport", Accept: "application/x-protobuf"}
+
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d mtrpb.FieldDeviceDetail
+
+	if err = proto.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Device == nil || d.Device.Status != "active" {
+		t.Fatalf("expected active device got %v", d.Device)
+	}
+
+	if len(d.Device.Attribute) != 1 || d.Device.Attribute[0].Key != "owner" || d.Device.Attribute[0].Value != "GeoNet" {
+		t.Errorf("expected attribute owner=GeoNet got %v", d.Device.Attribute)
+	}
+
+	// a device put without a status keeps its status.
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100&status=retired", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/device?deviceID=gps-taupoairport&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100", Method: "PUT", User: userW, Password: keyW},
+	} {
+		if _, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/device", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var fdr mtrpb.FieldDeviceResult
+
+	if err = proto.Unmarshal(b, &fdr); err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+
+	for _, v := range fdr.Result {
+		if v.DeviceID == "gps-taupoairport" {
+			found = true
+
+			if v.Status != "retired" {
+				t.Errorf("expected retired got %s", v.Status)
+			}
+
+			if len(v.Attribute) != 1 {
+				t.Errorf("expected 1 attribute got %v", v.Attribute)
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected retired device in /field/device")
+	}
+
+	// retired devices are not in the summary.
+	r = wt.Request{ID: wt.L(), URL: "/field/metric/summary", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var fmsr mtrpb.FieldMetricSummaryResult
+
+	if err = proto.Unmarshal(b, &fmsr); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range fmsr.Result {
+		if v.DeviceID == "gps-taupoairport" {
+			t.Errorf("expected no summary for a retired device got %v", v)
+		}
+	}
+
+	// the history is kept.
+	r = wt.Request{ID: wt.L(), URL: "/field/metric?deviceID=gps-taupoairport&typeID=voltage&resolution=minute", Content: "image/svg+xml"}
+
+	if _, err = r.Do(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	// a device called attribute has its attributes in its detail.
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/device?deviceID=attribute&modelID=Trimble+NetR9&latitude=-38.74270&longitude=176.08100", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/device/attribute?deviceID=attribute&key=owner&value=GeoNet", Method: "PUT", User: userW, Password: keyW},
+	} {
+		if _, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=attribute", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	d = mtrpb.FieldDeviceDetail{}
+
+	if err = proto.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Device == nil || d.Device.DeviceID != "attribute" || len(d.Device.Attribute) != 1 {
+		t.Errorf("expected device attribute with 1 attribute got %v", d.Device)
+	}
+}
+
+func TestDeviceRename(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	for _, r := range []wt.Request{
+		{ID: wt.L(), URL: "/field/device?deviceID=gps-wellington&modelID=Trimble+NetR9&latitude=-41.28&longitude=174.77", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/device/rename?deviceID=gps-taupoairport&newDeviceID=gps-wellington", Method: "PUT", User: userW, Password: keyW, Status: http.StatusConflict},
+		{ID: wt.L(), URL: "/field/device/rename?deviceID=gps-taupoairport&newDeviceID=gps-taupo", Method: "PUT", User: userW, Password: keyW},
+		{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-taupoairport", Accept: "application/x-protobuf", Status: http.StatusNotFound},
+	} {
+		if _, err := r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=gps-taupo", Accept: "application/x-protobuf"}
+
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d mtrpb.FieldDeviceDetail
+
+	if err = proto.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.Metric) == 0 {
+		t.Error("expected metrics to be kept after rename")
+	}
+
+	if len(d.Device.Attribute) != 1 {
+		t.Errorf("expected attributes to be kept after rename got %v", d.Device.Attribute)
+	}
+
+	// a device renamed to the name of an endpoint can still be fetched.
+	for _, id := range []string{"rename", "location"} {
+		r = wt.Request{ID: wt.L(), URL: "/field/device/rename?deviceID=" + d.Device.DeviceID + "&newDeviceID=" + id, Method: "PUT", User: userW, Password: keyW}
+
+		if _, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		r = wt.Request{ID: wt.L(), URL: "/field/device/detail?deviceID=" + id, Accept: "application/x-protobuf"}
+
+		if b, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		d = mtrpb.FieldDeviceDetail{}
+
+		if err = proto.Unmarshal(b, &d); err != nil {
+			t.Fatal(err)
+		}
+
+		if d.Device == nil || d.Device.DeviceID != id {
+			t.Fatalf("expected device %s got %v", id, d.Device)
+		}
+	}
+}
+
+func TestDeviceLocation(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	// Load test data.
+	if err := routes.DoAllStatusOk(testServer.URL); err != nil {
+		t.Error(err)
+	}
+
+	// a move in the past doesn't change the latest location.
+	r := wt.Request{ID: wt.L(), URL: "/field/device/location?deviceID=gps-taupoairport&latitude=-38.5&longitude=176.2&time=2015-01-01T00:00:00Z", Method: "PUT", User: userW, Password: keyW}
+
+	if _, err := r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/field/device/location?deviceID=gps-taupoairport", Accept: "application/x-protobuf"}
+
+	b, err := r.Do(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lr mtrpb.FieldDeviceLocationResult
+
+	if err = proto.Unmarshal(b, &lr); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lr.Result) != 2 {
+		t.Fatalf("expected 2 locations got %d", len(lr.Result))
+	}
+
+	if lr.Result[0].Latitude != -38.5 || lr.Result[0].Longitude != 176.2 {
+		t.Errorf("expected the first location to be -38.5 176.2 got %v", lr.Result[0])
+	}
+
+	if lr.Result[1].Latitude != -38.7427 || lr.Result[1].Longitude != 176.081 {
+		t.Errorf("expected the latest location to be -38.7427 176.081 got %v", lr.Result[1])
+	}
+
+	// maps show the location at a time.
+	for _, v := range []struct {
+		time, coordinates string
+	}{
+		{"2015-05-14T21:40:30Z", "[176.2,-38.5]"},
+		{"", "[176.081,-38.7427]"},
+	} {
+		r = wt.Request{ID: wt.L(), URL: "/field/metric/summary?typeID=voltage&time=" + v.time, Accept: "application/vnd.geo+json"}
+
+		if b, err = r.Do(testServer.URL); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(b), v.coordinates) {
+			t.Errorf("expected %s at %s got %s", v.coordinates, v.time, string(b))
+		}
+	}
+
+	// a site added before there was a location history has its location recorded from the epoch when it moves.
+	if _, err = db.Exec(`DELETE FROM data.site_location WHERE sitePK = (SELECT sitePK FROM data.site WHERE siteID = 'TAUP')`); err != nil {
+		t.Fatal(err)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/data/site?siteID=TAUP&latitude=-38.8&longitude=176.1", Method: "PUT", User: userW, Password: keyW}
+
+	if _, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	r = wt.Request{ID: wt.L(), URL: "/data/site/location?siteID=TAUP", Accept: "application/x-protobuf"}
+
+	if b, err = r.Do(testServer.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var dr mtrpb.DataSiteLocationResult
+
+	if err = proto.Unmarshal(b, &dr); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dr.Result) != 2 {
+		t.Fatalf("expected 2 locations got %d", len(dr.Result))
+	}
+
+	if dr.Result[0].Seconds != 0 || dr.Result[0].Latitude != -38.6427 || dr.Result[0].Longitude != 176.081 {
+		t.Errorf("expected the first location to be -38.6427 176.081 from the epoch got %v", dr.Result[0])
+	}
+
+	if dr.Result[1].Latitude != -38.8 || dr.Result[1].Longitude != 176.1 {
+		t.Errorf("expected the latest location to be -38.8 176.1 got %v", dr.Result[1])
 	}
 }
@@ -10,7 +10,6 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"time"
 )
 
 // the handler wiring and majority of mux routing is generated from weft.toml
@@ -32,6 +31,16 @@ func init() {
 	mux.HandleFunc("/", weft.MakeHandlerAPI(home))
 	mux.HandleFunc("/health", health)
 
+	// batch uploads return a body for POST requests so are not generated from weft.toml.
+	// See batch.go
+	batchMux.HandleFunc("/application/batch", makeHandlerBatch("applicationbatchHandler", applicationbatchHandler))
+	batchMux.HandleFunc("/data/completeness", makeHandlerBatch("datacompletenessbatchHandler", datacompletenessbatchHandler))
+	batchMux.HandleFunc("/data/latency", makeHandlerBatch("datalatencybatchHandler", datalatencybatchHandler))
+	batchMux.HandleFunc("/field/metric/batch", makeHandlerBatch("fieldmetricbatchHandler", fieldmetricbatchHandler))
+	batchMux.HandleFunc("/ingest/influx", makeHandlerBatch("ingestinfluxHandler", ingestinfluxHandler))
+	batchMux.HandleFunc("/ingest/influx/write", makeHandlerBatch("ingestinfluxHandler", ingestinfluxHandler))
+	batchMux.HandleFunc("/ingest/prometheus", makeHandlerBatch("ingestprometheusHandler", ingestprometheusHandler))
+
 	// routes for balancers and probes.
 	mux.HandleFunc("/soh/up", http.HandlerFunc(up))
 	mux.HandleFunc("/soh", http.HandlerFunc(soh))
@@ -76,6 +85,8 @@ func main() {
 	}
 
 	go deleteMetrics()
+	go rollupMetrics()
+	go evaluateAlerts()
 
 	log.Println("starting server")
 	log.Fatal(http.ListenAndServe(":8080", inbound(mux)))
@@ -90,11 +101,16 @@ func home(r *http.Request, h http.Header, b *bytes.Buffer) *weft.Result {
 }
 
 // inbound wraps the mux and adds basic auth.
+// POST requests are batch uploads and are served by batchMux.
 func inbound(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case "PUT", "DELETE", "POST":
 			if user, password, ok := r.BasicAuth(); ok && userW == user && keyW == password {
+				if r.Method == "POST" {
+					batchMux.ServeHTTP(w, r)
+					return
+				}
 				h.ServeHTTP(w, r)
 			} else {
 				http.Error(w, "Access denied", http.StatusUnauthorized)
@@ -118,48 +134,6 @@ func health(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok"))
 }
 
-// TODO delete app instance and time source that have no metrics?
-
-/*
-deleteMetrics deletes old metrics.
-*/
-func deleteMetrics() {
-	ticker := time.NewTicker(time.Minute).C
-	var err error
-	for {
-		select {
-		case <-ticker:
-			if _, err = db.Exec(`DELETE FROM field.metric WHERE time < now() - interval '40 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM field.metric_summary WHERE time < now() - interval '40 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM data.latency WHERE time < now() - interval '40 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM data.latency_summary WHERE time < now() - interval '40 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM app.metric WHERE time < now() - interval '28 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM app.counter WHERE time < now() - interval '28 days'`); err != nil {
-				log.Println(err)
-			}
-
-			if _, err = db.Exec(`DELETE FROM app.timer WHERE time < now() - interval '28 days'`); err != nil {
-				log.Println(err)
-			}
-		}
-	}
-}
-
 // up is for testing that the app has started e.g., for with load balancers.
 // It indicates the app is started.  It may still be serving errors.
 // Not useful for inclusion in app metrics so weft not used.
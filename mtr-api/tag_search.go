@@ -98,15 +98,17 @@ func (a *tagSearch) fieldMetric() <-chan *weft.Result {
 		var err error
 		var rows *sql.Rows
 
-		if rows, err = dbR.Query(`SELECT deviceID, modelID, typeid, time, value, lower, upper
+		if rows, err = dbR.Query(`SELECT deviceID, modelID, typeid, time, value, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), `+fieldMetricInterval.late()+`
 	 			  FROM field.metric_tag
 	 			  JOIN field.metric_summary USING (devicepk, typepk)
 	 			  JOIN field.device USING (devicePK)
 	 			  JOIN field.type USING (typePK)
 	 			  JOIN field.model USING (modelPK)
-	 			  JOIN field.threshold using (devicePK, typePK)
-			          WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
-			          OR deviceID LIKE $2`, a.tag, "%"+a.tag); err != nil {
+	 			  LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
+	 			  LEFT JOIN field.metric_interval USING (devicePK, typePK)
+			          WHERE (tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
+			          OR deviceID LIKE $2)
+			          AND status != 'retired'`, a.tag, "%"+a.tag); err != nil {
 			out <- weft.InternalServerError(err)
 			return
 		}
@@ -117,13 +119,16 @@ func (a *tagSearch) fieldMetric() <-chan *weft.Result {
 		for rows.Next() {
 			var fmr mtrpb.FieldMetricSummary
 
-			if err = rows.Scan(&fmr.DeviceID, &fmr.ModelID, &fmr.TypeID, &tm, &fmr.Value,
-				&fmr.Lower, &fmr.Upper); err != nil {
+			if err = rows.Scan(&fmr.DeviceID, &fmr.ModelID, &fmr.TypeID, &tm, &fmr.FloatValue,
+				&fmr.FloatLower, &fmr.FloatUpper, &fmr.FloatWarningLower, &fmr.FloatWarningUpper, &fmr.Late); err != nil {
 				out <- weft.InternalServerError(err)
 				return
 			}
 
 			fmr.Seconds = tm.Unix()
+			fmr.Unknown = fmr.FloatLower == 0 && fmr.FloatUpper == 0
+
+			roundFieldMetricSummary(&fmr)
 
 			a.tagResult.FieldMetric = append(a.tagResult.FieldMetric, &fmr)
 		}
@@ -146,7 +151,8 @@ func (a *tagSearch) fieldState() <-chan *weft.Result {
 					JOIN field.state USING (devicePK, typePK)
 					JOIN field.device USING (devicePK)
 					JOIN field.state_type USING (typePK)
-					WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)`, a.tag); err != nil {
+					WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
+					AND status != 'retired'`, a.tag); err != nil {
 			out <- weft.InternalServerError(err)
 			return
 		}
@@ -180,14 +186,16 @@ func (a *tagSearch) dataLatency() <-chan *weft.Result {
 		var err error
 		var rows *sql.Rows
 
-		if rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, lower, upper
+		if rows, err = dbR.Query(`SELECT siteID, typeID, time, mean, fifty, ninety, COALESCE(lower, 0), COALESCE(upper, 0), COALESCE(warning_lower, 0), COALESCE(warning_upper, 0), `+dataLatencyInterval.late()+`
 	 			  FROM data.latency_tag
 	 			  JOIN data.latency_summary USING (sitePK, typePK)
-	 			  JOIN data.latency_threshold USING (sitePK, typePK)
+	 			  LEFT JOIN `+dataLatencyThreshold+` AS threshold USING (sitePK, typePK)
 	 			  JOIN data.site USING (sitePK)
 				  JOIN data.type USING (typePK)
-			          WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
-			          OR siteID = $2`, a.tag, a.tag); err != nil {
+				  LEFT JOIN data.latency_interval USING (sitePK, typePK)
+			          WHERE (tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
+			          OR siteID = $2)
+			          AND status != 'retired'`, a.tag, a.tag); err != nil {
 			out <- weft.InternalServerError(err)
 			return
 		}
@@ -199,13 +207,16 @@ func (a *tagSearch) dataLatency() <-chan *weft.Result {
 
 			var dls mtrpb.DataLatencySummary
 
-			if err = rows.Scan(&dls.SiteID, &dls.TypeID, &tm, &dls.Mean, &dls.Fifty, &dls.Ninety,
-				&dls.Lower, &dls.Upper); err != nil {
+			if err = rows.Scan(&dls.SiteID, &dls.TypeID, &tm, &dls.FloatMean, &dls.FloatFifty, &dls.FloatNinety,
+				&dls.FloatLower, &dls.FloatUpper, &dls.FloatWarningLower, &dls.FloatWarningUpper, &dls.Late); err != nil {
 				out <- weft.InternalServerError(err)
 				return
 			}
 
 			dls.Seconds = tm.Unix()
+			dls.Unknown = dls.FloatLower == 0 && dls.FloatUpper == 0
+			roundDataLatencySummary(&dls)
+
 			a.tagResult.DataLatency = append(a.tagResult.DataLatency, &dls)
 		}
 
@@ -225,12 +236,14 @@ func (a *tagSearch) dataCompleteness() <-chan *weft.Result {
 		// Returns the last 5 minutes count for all completeness with given tag.
 		// Could be empty if the siteid+typeid has no data in 5 minutes.
 		if rows, err = dbR.Query(
-			`SELECT siteID, typeID, time, count, expected
+			`SELECT siteID, typeID, time, count, expected, `+dataCompletenessInterval.late()+`
 	 			  FROM data.completeness_tag
 	 			  JOIN data.completeness_summary USING (sitePK, typePK)
 	 			  JOIN data.site USING (sitePK)
 				  JOIN data.completeness_type USING (typePK)
-			          WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)`, a.tag); err != nil {
+				  LEFT JOIN data.completeness_interval USING (sitePK, typePK)
+			          WHERE tagPK = (SELECT tagPK FROM mtr.tag WHERE tag = $1)
+			          AND status != 'retired'`, a.tag); err != nil {
 			out <- weft.InternalServerError(err)
 			return
 		}
@@ -244,7 +257,7 @@ func (a *tagSearch) dataCompleteness() <-chan *weft.Result {
 			var ts sql.NullString
 			var count sql.NullInt64
 
-			if err = rows.Scan(&dls.SiteID, &dls.TypeID, &ts, &count, &expected); err != nil {
+			if err = rows.Scan(&dls.SiteID, &dls.TypeID, &ts, &count, &expected, &dls.Late); err != nil {
 				out <- weft.InternalServerError(err)
 				return
 			}
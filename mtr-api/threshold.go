@@ -0,0 +1,112 @@
+package main
+
+import (
+	"github.com/GeoNet/weft"
+	"net/url"
+)
+
+/*
+Thresholds are set as templates; for a model and type for field metrics and for a type for data latency.
+A threshold set for a device or site overrides the template.  Thresholds with lower and upper 0 mean
+there is no threshold.
+
+Values outside lower and upper are critical (bad).  Values inside lower and upper but outside
+warning_lower and warning_upper are warning.  warning_lower and warning_upper 0 means there is no
+warning threshold.  Alerts are opened for critical values and are not closed until the value is
+back inside lower and upper by at least hysteresis.
+*/
+
+// fieldThreshold selects the threshold in effect for each device and type with the columns
+// devicePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis, and inherited
+// (true if it is from the model template).
+// It is a subquery so it must be aliased e.g.,
+// LEFT JOIN `+fieldThreshold+` AS threshold USING (devicePK, typePK)
+const fieldThreshold = `(SELECT devicePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis, false AS inherited
+		FROM field.threshold
+		UNION ALL
+		SELECT devicePK, typePK, t.lower, t.upper, t.warning_lower, t.warning_upper, t.hysteresis, true
+		FROM field.model_threshold t
+		JOIN field.device USING (modelPK)
+		WHERE NOT EXISTS (SELECT 1 FROM field.threshold o
+			WHERE o.devicePK = field.device.devicePK AND o.typePK = t.typePK))`
+
+// dataLatencyThreshold selects the threshold in effect for each site and latency type with the columns
+// sitePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis, and inherited
+// (true if it is from the type template).
+// It is a subquery so it must be aliased e.g.,
+// LEFT JOIN `+dataLatencyThreshold+` AS threshold USING (sitePK, typePK)
+const dataLatencyThreshold = `(SELECT sitePK, typePK, lower, upper, warning_lower, warning_upper, hysteresis, false AS inherited
+		FROM data.latency_threshold
+		UNION ALL
+		SELECT sitePK, typePK, t.lower, t.upper, t.warning_lower, t.warning_upper, t.hysteresis, true
+		FROM data.latency_type_threshold t
+		CROSS JOIN data.site
+		WHERE NOT EXISTS (SELECT 1 FROM data.latency_threshold o
+			WHERE o.sitePK = data.site.sitePK AND o.typePK = t.typePK))`
+
+// threshold is the critical (lower, upper) and warning bands, and the hysteresis, for a metric.
+type threshold struct {
+	lower, upper               float64
+	warningLower, warningUpper float64
+	hysteresis                 float64
+}
+
+/*
+parseThreshold reads the threshold from the query parameters lower and upper (required) and
+warningLower, warningUpper, and hysteresis (optional, default 0).  The warning band must be inside
+lower and upper.
+*/
+func parseThreshold(v url.Values) (threshold, *weft.Result) {
+	var t threshold
+	var err error
+
+	if t.lower, err = parseValue(v.Get("lower")); err != nil {
+		return t, weft.BadRequest("invalid lower")
+	}
+
+	if t.upper, err = parseValue(v.Get("upper")); err != nil {
+		return t, weft.BadRequest("invalid upper")
+	}
+
+	for _, o := range []struct {
+		name string
+		f    *float64
+	}{
+		{"warningLower", &t.warningLower},
+		{"warningUpper", &t.warningUpper},
+		{"hysteresis", &t.hysteresis},
+	} {
+		if v.Get(o.name) == "" {
+			continue
+		}
+
+		if *o.f, err = parseValue(v.Get(o.name)); err != nil {
+			return t, weft.BadRequest("invalid " + o.name)
+		}
+	}
+
+	if t.hysteresis < 0 {
+		return t, weft.BadRequest("invalid hysteresis")
+	}
+
+	if !(t.warningLower == 0 && t.warningUpper == 0) &&
+		(t.warningLower < t.lower || t.warningUpper > t.upper || t.warningLower > t.warningUpper) {
+		return t, weft.BadRequest("warningLower and warningUpper must be inside lower and upper")
+	}
+
+	return t, &weft.StatusOK
+}
+
+// critical returns true if v is outside lower and upper.
+func (t threshold) critical(v float64) bool {
+	return v < t.lower || v > t.upper
+}
+
+// warning returns true if v is inside lower and upper but outside the warning band.
+func (t threshold) warning(v float64) bool {
+	if t.warningLower == 0 && t.warningUpper == 0 {
+		return false
+	}
+
+	return !t.critical(v) && (v < t.warningLower || v > t.warningUpper)
+}
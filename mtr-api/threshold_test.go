@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseThreshold(t *testing.T) {
+	in := []struct {
+		id    string
+		query string
+		ok    bool
+		th    threshold
+	}{
+		{id: "critical only", query: "lower=10&upper=100", ok: true, th: threshold{lower: 10, upper: 100}},
+		{id: "warning", query: "lower=10&upper=100&warningLower=20&warningUpper=90&hysteresis=5", ok: true,
+			th: threshold{lower: 10, upper: 100, warningLower: 20, warningUpper: 90, hysteresis: 5}},
+		{id: "warning upper only", query: "lower=0&upper=100&warningUpper=90", ok: true, th: threshold{upper: 100, warningUpper: 90}},
+		{id: "fractional", query: "lower=-0.5&upper=0.5&warningLower=-0.25&warningUpper=0.25&hysteresis=0.05", ok: true,
+			th: threshold{lower: -0.5, upper: 0.5, warningLower: -0.25, warningUpper: 0.25, hysteresis: 0.05}},
+		{id: "missing upper", query: "lower=10"},
+		{id: "NaN upper", query: "lower=10&upper=NaN"},
+		{id: "invalid warningLower", query: "lower=10&upper=100&warningLower=fred"},
+		{id: "warning below lower", query: "lower=10&upper=100&warningLower=5&warningUpper=90"},
+		{id: "warning above upper", query: "lower=10&upper=100&warningLower=20&warningUpper=110"},
+		{id: "warning inverted", query: "lower=10&upper=100&warningLower=90&warningUpper=20"},
+		{id: "negative hysteresis", query: "lower=10&upper=100&hysteresis=-1"},
+	}
+
+	for _, v := range in {
+		q, err := url.ParseQuery(v.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		th, res := parseThreshold(q)
+
+		if res.Ok != v.ok {
+			t.Errorf("%s: expected ok %t got %t", v.id, v.ok, res.Ok)
+			continue
+		}
+
+		if v.ok && th != v.th {
+			t.Errorf("%s: expected %+v got %+v", v.id, v.th, th)
+		}
+	}
+}
+
+func TestThresholdStatus(t *testing.T) {
+	th := threshold{lower: 10, upper: 100, warningLower: 20, warningUpper: 90}
+
+	in := []struct {
+		v                 float64
+		critical, warning bool
+	}{
+		{v: 5, critical: true},
+		{v: 10, warning: true},
+		{v: 15, warning: true},
+		{v: 20},
+		{v: 50},
+		{v: 90},
+		{v: 95, warning: true},
+		{v: 100.5, critical: true},
+		{v: 101, critical: true},
+	}
+
+	for _, v := range in {
+		if th.critical(v.v) != v.critical {
+			t.Errorf("%g: expected critical %t", v.v, v.critical)
+		}
+		if th.warning(v.v) != v.warning {
+			t.Errorf("%g: expected warning %t", v.v, v.warning)
+		}
+	}
+
+	// no warning band.
+	th = threshold{lower: 10, upper: 100}
+
+	if th.warning(15) {
+		t.Error("expected no warning without a warning threshold")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/GeoNet/mtr/internal"
+	"time"
+)
+
+/*
+timerMerge merges app.timer rows for a time, and source, across instances and minutes.  When
+all the rows have a sketch the percentiles are from the merged sketch so they are correct for
+the application.  Otherwise, for rows from older versions of mtrapp, they are the max of the row
+percentiles.
+*/
+type timerMerge struct {
+	sketch   *internal.Sketch
+	sketches bool // false if any row did not have a sketch.
+
+	// for rows without sketches.
+	sum                       float64 // of the averages.
+	rows, count               int
+	fifty, ninety, ninetyNine int
+}
+
+func newTimerMerge() *timerMerge {
+	return &timerMerge{sketch: internal.NewSketch(), sketches: true}
+}
+
+// add adds a row.  sketch is nil for rows without a sketch.  Rows with an invalid sketch
+// are merged as if they had no sketch.
+func (m *timerMerge) add(average float64, count, fifty, ninety, ninetyNine int, sketch []byte) {
+	m.rows++
+	m.sum += average
+	m.count += count
+
+	if fifty > m.fifty {
+		m.fifty = fifty
+	}
+	if ninety > m.ninety {
+		m.ninety = ninety
+	}
+	if ninetyNine > m.ninetyNine {
+		m.ninetyNine = ninetyNine
+	}
+
+	if sketch == nil {
+		m.sketches = false
+		return
+	}
+
+	s, err := internal.UnmarshalSketch(sketch)
+	if err != nil {
+		m.sketches = false
+		return
+	}
+
+	m.sketch.Merge(s)
+}
+
+// average returns the mean time.
+func (m *timerMerge) average() float64 {
+	if m.sketches && m.sketch.Count > 0 {
+		return float64(m.sketch.Sum) / float64(m.sketch.Count)
+	}
+
+	return m.sum / float64(m.rows)
+}
+
+// quantile returns the time at quantile q (0.5, 0.9, or 0.99).  ok is false if there is no
+// value for q e.g., 0.99 for rows without sketches.
+func (m *timerMerge) quantile(q float64) (v int, ok bool) {
+	if m.sketches {
+		return int(m.sketch.Quantile(q)), true
+	}
+
+	switch q {
+	case 0.5:
+		return m.fifty, true
+	case 0.9:
+		return m.ninety, true
+	}
+
+	return 0, false
+}
+
+/*
+timerRows queries the app.timer rows for applicationID, and sourceID if it is not empty, with the
+time truncated to resolution.  The columns are sourcePK, t, average, count, fifty, ninety, ninetynine,
+and sketch.  For hour, day, and week resolutions the rows are read from the rollups and the timers that
+haven't been rolled up yet.
+*/
+func timerRows(applicationID, sourceID, resolution string, timeRange []time.Time) (*sql.Rows, error) {
+	where := `WHERE applicationPK = (SELECT applicationPK from app.application WHERE applicationID = $1)
+		AND ($4 = '' OR sourcePK = (SELECT sourcePK from app.source WHERE sourceID = $4))
+		AND time >= $2 AND time <= $3`
+
+	timer := func(t string) string {
+		return `SELECT sourcePK, ` + t + ` AS t, average::DOUBLE PRECISION, count::BIGINT, fifty, ninety,
+		COALESCE(ninetynine, 0), sketch
+		FROM app.timer ` + where
+	}
+
+	rolled := func(t, table string) string {
+		return `SELECT sourcePK, ` + t + ` AS t, avg, count, fifty, ninety, COALESCE(ninetynine, 0), sketch
+		FROM ` + table + ` ` + where
+	}
+
+	var q string
+
+	switch resolution {
+	case "minute":
+		q = timer(`date_trunc('minute', time)`)
+	case "five_minutes":
+		q = timer(`date_trunc('hour', time) + extract(minute from time)::int / 5 * interval '5 min'`)
+	case "hour", "day":
+		q = rolled(`time`, `app.timer_`+resolution) + ` UNION ALL ` +
+			timer(`date_trunc('`+resolution+`', time)`) + ` AND time >= ` + rolledUp("app.timer", resolution)
+	case "week":
+		q = rolled(`date_trunc('week', time)`, `app.timer_day`) + ` UNION ALL ` +
+			timer(`date_trunc('week', time)`) + ` AND time >= ` + rolledUp("app.timer", "day")
+	case "full":
+		q = timer(`time`)
+	default:
+		return nil, fmt.Errorf("invalid resolution: %s", resolution)
+	}
+
+	return dbR.Query(q+` ORDER BY t ASC`, applicationID, timeRange[0], timeRange[1], sourceID)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/GeoNet/mtr/internal"
+	"testing"
+)
+
+// With sketches the application percentiles are for all the timers not the max of the instances.
+func TestTimerMerge(t *testing.T) {
+	fast := internal.NewSketch()
+	for i := 0; i < 990; i++ {
+		fast.Add(10)
+	}
+
+	slow := internal.NewSketch()
+	for i := 0; i < 10; i++ {
+		slow.Add(1000)
+	}
+
+	m := newTimerMerge()
+	m.add(float64(fast.Mean()), 990, 10, 10, 10, fast.Marshal())
+	m.add(float64(slow.Mean()), 10, 1000, 1000, 1000, slow.Marshal())
+
+	if v, ok := m.quantile(0.9); !ok || v != 10 {
+		t.Errorf("expected ninety 10 got %d", v)
+	}
+
+	if v, ok := m.quantile(0.99); !ok || v < 10 || v > 1000 {
+		t.Errorf("expected ninety nine between 10 and 1000 got %d", v)
+	}
+
+	if m.average() != 19.9 {
+		t.Errorf("expected average 19.9 got %f", m.average())
+	}
+
+	if m.count != 1000 {
+		t.Errorf("expected count 1000 got %d", m.count)
+	}
+
+	// a row without a sketch e.g., from an older mtrapp.
+	m.add(500, 10, 400, 500, 0, nil)
+
+	if v, ok := m.quantile(0.9); !ok || v != 1000 {
+		t.Errorf("expected the max ninety 1000 got %d", v)
+	}
+
+	if _, ok := m.quantile(0.99); ok {
+		t.Error("expected no ninety nine without sketches")
+	}
+
+	if m.average() != (10+1000+500)/3.0 {
+		t.Errorf("expected the average of the averages got %f", m.average())
+	}
+}
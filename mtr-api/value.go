@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"github.com/GeoNet/mtr/mtrpb"
+	"math"
+	"strconv"
+)
+
+/*
+Field metric and data latency values are stored as float64 in the unit for their type
+e.g., mV.  Senders that scale values to integers for the type still work; the type scale
+is applied for display.
+*/
+
+var errInvalidValue = errors.New("invalid value")
+
+// parseValue parses s as a metric value.  NaN and Inf are not valid values.
+func parseValue(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0.0, errInvalidValue
+	}
+
+	return f, nil
+}
+
+// formatValue formats v for CSV.  v is rounded to 12 significant digits to remove
+// the noise from scaling e.g., 14100 * 0.001 is 14.1 not 14.100000000000001.
+func formatValue(v float64) string {
+	r, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	if err != nil {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+/*
+The protobufs keep the integer (and float32) fields they had before values were float64 so that
+existing clients still work.  The round functions set them from the float fields.
+*/
+
+// round32 rounds v to the nearest int32.  Values outside the int32 range are clamped.
+func round32(v float64) int32 {
+	switch {
+	case v >= math.MaxInt32:
+		return math.MaxInt32
+	case v <= math.MinInt32:
+		return math.MinInt32
+	}
+
+	return int32(math.Floor(v + 0.5))
+}
+
+func roundFieldMetricSummary(m *mtrpb.FieldMetricSummary) {
+	m.Value = round32(m.FloatValue)
+	m.Upper = round32(m.FloatUpper)
+	m.Lower = round32(m.FloatLower)
+	m.WarningUpper = round32(m.FloatWarningUpper)
+	m.WarningLower = round32(m.FloatWarningLower)
+}
+
+func roundFieldMetricThreshold(m *mtrpb.FieldMetricThreshold) {
+	m.Lower = round32(m.FloatLower)
+	m.Upper = round32(m.FloatUpper)
+	m.WarningLower = round32(m.FloatWarningLower)
+	m.WarningUpper = round32(m.FloatWarningUpper)
+	m.Hysteresis = round32(m.FloatHysteresis)
+}
+
+func roundFieldMetric(m *mtrpb.FieldMetric) {
+	m.Value = float32(m.FloatValue)
+}
+
+func roundFieldMetricResult(m *mtrpb.FieldMetricResult) {
+	m.Value = round32(m.FloatValue)
+	m.Upper = round32(m.FloatUpper)
+	m.Lower = round32(m.FloatLower)
+}
+
+func roundDataLatencySummary(m *mtrpb.DataLatencySummary) {
+	m.Mean = round32(m.FloatMean)
+	m.Fifty = round32(m.FloatFifty)
+	m.Ninety = round32(m.FloatNinety)
+	m.Upper = round32(m.FloatUpper)
+	m.Lower = round32(m.FloatLower)
+	m.WarningUpper = round32(m.FloatWarningUpper)
+	m.WarningLower = round32(m.FloatWarningLower)
+}
+
+func roundDataLatencyThreshold(m *mtrpb.DataLatencyThreshold) {
+	m.Lower = round32(m.FloatLower)
+	m.Upper = round32(m.FloatUpper)
+	m.WarningLower = round32(m.FloatWarningLower)
+	m.WarningUpper = round32(m.FloatWarningUpper)
+	m.Hysteresis = round32(m.FloatHysteresis)
+}
+
+func roundDataLatency(m *mtrpb.DataLatency) {
+	m.Mean = float32(m.FloatMean)
+	m.Fifty = round32(m.FloatFifty)
+	m.Ninety = round32(m.FloatNinety)
+}
+
+func roundDataLatencyResult(m *mtrpb.DataLatencyResult) {
+	m.Upper = round32(m.FloatUpper)
+	m.Lower = round32(m.FloatLower)
+}
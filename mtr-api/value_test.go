@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseValue(t *testing.T) {
+	in := []struct {
+		s  string
+		ok bool
+		v  float64
+	}{
+		{s: "14100", ok: true, v: 14100},
+		{s: "-0.0012", ok: true, v: -0.0012},
+		{s: "3000000000", ok: true, v: 3000000000},
+		{s: "1e3", ok: true, v: 1000},
+		{s: ""},
+		{s: "fred"},
+		{s: "NaN"},
+		{s: "+Inf"},
+	}
+
+	for _, v := range in {
+		f, err := parseValue(v.s)
+
+		if (err == nil) != v.ok {
+			t.Errorf("%q: expected ok %t got %v", v.s, v.ok, err)
+			continue
+		}
+
+		if v.ok && f != v.v {
+			t.Errorf("%q: expected %g got %g", v.s, v.v, f)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	in := []struct {
+		v float64
+		s string
+	}{
+		{v: 14100 * 0.001, s: "14.1"},
+		{v: 0.0012, s: "0.0012"},
+		{v: -12.5, s: "-12.5"},
+		{v: 3000000000, s: "3000000000"},
+		{v: 0, s: "0"},
+	}
+
+	for _, v := range in {
+		if s := formatValue(v.v); s != v.s {
+			t.Errorf("%g: expected %s got %s", v.v, v.s, s)
+		}
+	}
+}
+
+func TestRound32(t *testing.T) {
+	in := []struct {
+		v        float64
+		expected int32
+	}{
+		{v: 14100, expected: 14100},
+		{v: 0.5, expected: 1},
+		{v: 0.49, expected: 0},
+		{v: -0.5, expected: 0},
+		{v: -1.6, expected: -2},
+		{v: 3000000000.25, expected: math.MaxInt32},
+		{v: -3000000000, expected: math.MinInt32},
+	}
+
+	for _, v := range in {
+		if r := round32(v.v); r != v.expected {
+			t.Errorf("%g: expected %d got %d", v.v, v.expected, r)
+		}
+	}
+}
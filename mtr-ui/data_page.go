@@ -169,7 +169,7 @@ func dataPlotPageHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft.
 
 	if f.Result != nil && len(f.Result) >= 1 {
 		thresholds := f.Result[0]
-		p.Plt.Thresholds = []float64{float64(thresholds.Lower) * thresholds.Scale, float64(thresholds.Upper) * thresholds.Scale}
+		p.Plt.Thresholds = []float64{thresholds.FloatLower * thresholds.Scale, thresholds.FloatUpper * thresholds.Scale}
 	} else {
 		p.Plt.Thresholds = []float64{0.0, 0.0}
 	}
@@ -537,26 +537,27 @@ func incDataCount(m map[string]idCount, r *mtrpb.DataLatencySummary) {
 
 func dataStatusString(r *mtrpb.DataLatencySummary) string {
 	switch {
-	case r.Upper == 0 && r.Lower == 0:
+	case r.Late:
+		return "late"
+	case r.Unknown, r.FloatUpper == 0 && r.FloatLower == 0:
 		return "unknown"
-	case allGood(r):
-		return "good"
-		// TBD: late
+	case !allInside(r, r.FloatLower, r.FloatUpper):
+		return "bad"
+	case !(r.FloatWarningUpper == 0 && r.FloatWarningLower == 0) && !allInside(r, r.FloatWarningLower, r.FloatWarningUpper):
+		return "warning"
 	}
-	return "bad"
+	return "good"
 }
 
-func allGood(r *mtrpb.DataLatencySummary) bool {
-	if r.Upper == 0 && r.Lower == 0 {
-		return false
-	}
-	if r.Mean < r.Lower || r.Mean > r.Upper {
+// allInside returns true if the mean, and fifty and ninety if they are known, are inside lower and upper.
+func allInside(r *mtrpb.DataLatencySummary, lower, upper float64) bool {
+	if r.FloatMean < lower || r.FloatMean > upper {
 		return false
 	}
-	if r.Fifty != 0 && (r.Fifty < r.Lower || r.Fifty > r.Upper) {
+	if r.FloatFifty != 0 && (r.FloatFifty < lower || r.FloatFifty > upper) {
 		return false
 	}
-	if r.Ninety != 0 && (r.Ninety < r.Lower || r.Ninety > r.Upper) {
+	if r.FloatNinety != 0 && (r.FloatNinety < lower || r.FloatNinety > upper) {
 		return false
 	}
 	return true
@@ -572,6 +573,8 @@ func removeTypeIDPrefix(typeID string) string {
 
 func completenessStatusString(r *mtrpb.DataCompletenessSummary) string {
 	switch {
+	case r.Late:
+		return "late"
 	case r.Completeness >= 1.0:
 		return "good"
 	}
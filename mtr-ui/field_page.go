@@ -164,7 +164,7 @@ func fieldPlotPageHandler(r *http.Request, h http.Header, b *bytes.Buffer) *weft
 	if f.Result != nil {
 		for _, row := range f.Result {
 			if row.DeviceID == p.DeviceID && row.TypeID == p.TypeID {
-				p.Plt.Thresholds = []float64{float64(row.Lower) * row.Scale, float64(row.Upper) * row.Scale}
+				p.Plt.Thresholds = []float64{row.FloatLower * row.Scale, row.FloatUpper * row.Scale}
 			}
 		}
 	}
@@ -554,11 +554,14 @@ func incFieldCount(m map[string]idCount, r *mtrpb.FieldMetricSummary) {
 
 func fieldStatusString(r *mtrpb.FieldMetricSummary) string {
 	switch {
-	case r.Upper == 0 && r.Lower == 0:
+	case r.Late:
+		return "late"
+	case r.Unknown, r.FloatUpper == 0 && r.FloatLower == 0:
 		return "unknown"
-	case r.Value >= r.Lower && r.Value <= r.Upper:
-		return "good"
-		// TBD: late
+	case r.FloatValue < r.FloatLower || r.FloatValue > r.FloatUpper:
+		return "bad"
+	case !(r.FloatWarningUpper == 0 && r.FloatWarningLower == 0) && (r.FloatValue < r.FloatWarningLower || r.FloatValue > r.FloatWarningUpper):
+		return "warning"
 	}
-	return "bad"
+	return "good"
 }
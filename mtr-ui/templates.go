@@ -24,26 +24,26 @@ var funcMap = template.FuncMap{
 	"rfc3339str": func(sec int64) string {
 		return time.Unix(sec, 0).Format(time.RFC3339)
 	},
-	"latencyColour": func(r *mtrpb.DataLatency, lower, upper int32) string {
+	"latencyColour": func(r *mtrpb.DataLatency, lower, upper float64) string {
 		if upper == 0 && lower == 0 {
 			return "red"
 		}
-		if r.Mean < float32(lower) || r.Mean > float32(upper) {
+		if r.FloatMean < lower || r.FloatMean > upper {
 			return "red"
 		}
-		if r.Fifty != 0 && (r.Fifty < lower || r.Fifty > upper) {
+		if r.FloatFifty != 0 && (r.FloatFifty < lower || r.FloatFifty > upper) {
 			return "red"
 		}
-		if r.Ninety != 0 && (r.Ninety < lower || r.Ninety > upper) {
+		if r.FloatNinety != 0 && (r.FloatNinety < lower || r.FloatNinety > upper) {
 			return "red"
 		}
 		return "black"
 	},
-	"fieldColour": func(r *mtrpb.FieldMetric, lower, upper int32) string {
+	"fieldColour": func(r *mtrpb.FieldMetric, lower, upper float64) string {
 		if upper == 0 && lower == 0 {
 			return "red"
 		}
-		if r.Value < float32(lower) || r.Value > float32(upper) {
+		if r.FloatValue < lower || r.FloatValue > upper {
 			return "red"
 		}
 		return "black"
@@ -0,0 +1,624 @@
+package mtrapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/GeoNet/mtr/internal"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options for a Client.  Server, User, and Key are required.
+type Options struct {
+	Server, User, Key string
+
+	// ApplicationID defaults to the executable name and InstanceID to the host name.
+	ApplicationID, InstanceID string
+
+	// Interval is how often metrics are sent.  Defaults to one minute.
+	Interval time.Duration
+
+	// Timeout for each request to the server.  Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// SpoolDir is a directory to spool metrics in while they are waiting to be sent.  Metrics in
+	// the spool are sent after a restart.  If SpoolDir is empty metrics are queued in memory.
+	// Each Client must have its own SpoolDir.
+	SpoolDir string
+
+	// NoBatch sends each metric, counter, and timer in its own PUT instead of one POST
+	// to /application/batch each Interval.  For servers without /application/batch.
+	NoBatch bool
+
+	// SpoolBytes bounds the size of the spool or memory queue.  The oldest metrics are
+	// dropped when it is full.  Defaults to 64 MiB.
+	SpoolBytes int64
+
+	// HTTPClient defaults to an http.Client with no timeout.
+	HTTPClient *http.Client
+}
+
+// Client gathers the counters, timers, and memory metrics for an application and
+// sends them to an mtr server every Interval.  Many Clients can be started e.g., to send to more
+// than one server.  Each gets all counter increments and timers from when it is started.
+// Metrics are queued (see spool.go) and sent in time order by a single goroutine which
+// retries until the server answers.
+type Client struct {
+	o Options
+
+	flush   chan chan bool
+	running chan bool
+	stop    chan bool
+	done    chan bool
+
+	q      queue
+	queued chan bool // signals the sender that there are records in q.
+	sent   chan bool // closed when the sender exits.
+
+	// cancel stops the sender.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	startOnce, stopOnce sync.Once
+
+	// the timers since they were last sent keyed by id.  Added to by Timer.Track.
+	tmu      sync.Mutex
+	sketches map[string]*internal.Sketch
+
+	// for aggregating counters.  Only used by run.
+	lastVal   [len(counters)]uint64
+	lastNamed map[*Counter]uint64
+	types     map[string]bool // the types from NewCounter and NewGauge that have been added to the server.
+	lastDrops uint64
+	lastNumGC uint32
+	lastCPU   time.Duration
+	last      time.Time
+}
+
+// started are the Clients that are sent timers.
+var started struct {
+	sync.RWMutex
+	c []*Client
+}
+
+// OptionsFromEnv returns Options from the environment vars MTR_SERVER, MTR_USER, MTR_KEY,
+// MTR_APPLICATIONID, MTR_INSTANCEID, and MTR_SPOOLDIR.
+func OptionsFromEnv() Options {
+	return Options{
+		Server:        os.Getenv("MTR_SERVER"),
+		User:          os.Getenv("MTR_USER"),
+		Key:           os.Getenv("MTR_KEY"),
+		ApplicationID: os.Getenv("MTR_APPLICATIONID"),
+		InstanceID:    os.Getenv("MTR_INSTANCEID"),
+		SpoolDir:      os.Getenv("MTR_SPOOLDIR"),
+	}
+}
+
+// NewClient returns a Client for o.  Call Start to begin sending metrics.
+func NewClient(o Options) (*Client, error) {
+	switch "" {
+	case o.Server, o.User, o.Key:
+		return nil, errors.New("mtrapp: Server, User, and Key are required")
+	}
+
+	if o.ApplicationID == "" {
+		s := os.Args[0]
+		o.ApplicationID = s[strings.LastIndex(s, "/")+1:]
+	}
+
+	if o.InstanceID == "" {
+		var err error
+		if o.InstanceID, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.Interval <= 0 {
+		o.Interval = time.Minute
+	}
+
+	if o.Timeout <= 0 {
+		o.Timeout = timeout
+	}
+
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{}
+	}
+
+	if o.SpoolBytes <= 0 {
+		o.SpoolBytes = spoolBytes
+	}
+
+	c := &Client{
+		o:         o,
+		flush:     make(chan chan bool),
+		running:   make(chan bool),
+		stop:      make(chan bool),
+		done:      make(chan bool),
+		sketches:  make(map[string]*internal.Sketch),
+		queued:    make(chan bool, 1),
+		sent:      make(chan bool),
+		lastNamed: make(map[*Counter]uint64),
+		types:     make(map[string]bool),
+	}
+
+	if o.SpoolDir != "" {
+		s, err := openSpool(o.SpoolDir, o.SpoolBytes)
+		if err != nil {
+			return nil, err
+		}
+		c.q = s
+	} else {
+		c.q = newMemQueue(o.SpoolBytes)
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	return c, nil
+}
+
+// Start starts gathering and sending metrics.  Calling Start more than once has no effect.
+func (c *Client) Start() {
+	c.startOnce.Do(func() {
+		c.last = time.Now().UTC()
+
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		c.lastNumGC = mem.NumGC
+		c.lastCPU, _ = cpuTime()
+
+		for i := range counters {
+			c.lastVal[i] = counters[i].value()
+		}
+
+		for _, n := range namedCounters() {
+			c.lastNamed[n] = n.value()
+		}
+
+		started.Lock()
+		started.c = append(started.c, c)
+		started.Unlock()
+
+		close(c.running)
+
+		go c.run()
+		go c.sender()
+	})
+}
+
+/*
+Flush sends the metrics gathered since they were last sent and waits for the queue
+to be sent or ctx to be done.  Does nothing for a nil, not started, or stopped Client.
+*/
+func (c *Client) Flush(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	select {
+	case <-c.running:
+	default:
+		return nil
+	}
+
+	f := make(chan bool)
+
+	select {
+	case c.flush <- f:
+		<-f
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return c.wait(ctx)
+}
+
+/*
+Stop stops gathering metrics, sends the metrics gathered since they were last sent (the final
+minute), and waits for the queue to be sent.  If ctx is done first ctx.Err() is returned and the
+remaining metrics are abandoned, or left in the spool if there is a SpoolDir.  Does nothing for a nil Client.
+*/
+func (c *Client) Stop(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	c.stopOnce.Do(func() {
+		started.Lock()
+		for i := range started.c {
+			if started.c[i] == c {
+				started.c = append(started.c[:i], started.c[i+1:]...)
+				break
+			}
+		}
+		started.Unlock()
+
+		close(c.stop)
+	})
+
+	// if the Client was never started there is nothing to send.
+	c.startOnce.Do(func() {
+		close(c.done)
+		close(c.sent)
+	})
+
+	// the final metrics are queued by run without waiting on the server.
+	<-c.done
+
+	err := c.wait(ctx)
+
+	c.cancel()
+	<-c.sent
+
+	if s, ok := c.q.(*spool); ok {
+		if e := s.close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// wait waits for the queue to be sent or ctx to be done.
+func (c *Client) wait(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for c.q.depth() > 0 {
+		select {
+		case <-ticker.C:
+		case <-c.sent:
+			// nothing is sending the queue.
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.o.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.send()
+		case f := <-c.flush:
+			c.send()
+			close(f)
+		case <-c.stop:
+			c.send()
+			return
+		}
+	}
+}
+
+// track adds the time taken (ms) for id to the timers.
+func (c *Client) track(id string, taken int) {
+	c.tmu.Lock()
+	defer c.tmu.Unlock()
+
+	s, ok := c.sketches[id]
+	if !ok {
+		s = internal.NewSketch()
+		c.sketches[id] = s
+	}
+
+	s.Add(int64(taken))
+}
+
+// send sends the memory and runtime metrics and the counters and timers since they were last sent.
+func (c *Client) send() {
+	var mem runtime.MemStats
+
+	now := time.Now().UTC()
+
+	runtime.ReadMemStats(&mem)
+
+	a := mtrpb.ApplicationBatch{ApplicationID: c.o.ApplicationID, InstanceID: c.o.InstanceID}
+
+	metric := func(typeID internal.ID, value int64) {
+		a.Metric = append(a.Metric, &mtrpb.ApplicationMetric{TypeID: int32(typeID), Seconds: now.Unix(), Value: value})
+	}
+
+	count := func(typeID internal.ID, count int) {
+		a.Counter = append(a.Counter, &mtrpb.ApplicationCounter{TypeID: int32(typeID), Seconds: c.last.Unix(), Count: int32(count)})
+	}
+
+	metric(internal.MemSys, int64(mem.Sys))
+	metric(internal.MemHeapAlloc, int64(mem.HeapAlloc))
+	metric(internal.MemHeapSys, int64(mem.HeapSys))
+	metric(internal.MemHeapObjects, int64(mem.HeapObjects))
+	metric(internal.Routines, int64(runtime.NumGoroutine()))
+	metric(internal.SpoolDepth, int64(c.q.depth()))
+
+	fifty, max := gcPauses(&mem, c.lastNumGC)
+	metric(internal.GCCount, int64(mem.NumGC-c.lastNumGC))
+	metric(internal.GCPauseFifty, fifty)
+	metric(internal.GCPauseMax, max)
+	c.lastNumGC = mem.NumGC
+
+	metric(internal.NextGC, int64(mem.NextGC))
+	metric(internal.StackInuse, int64(mem.StackInuse))
+	metric(internal.Threads, threads())
+
+	if n, ok := openFDs(); ok {
+		metric(internal.OpenFDs, n)
+	}
+
+	if t, ok := cpuTime(); ok {
+		metric(internal.CPUTime, int64((t-c.lastCPU)/time.Millisecond))
+		c.lastCPU = t
+	}
+
+	if d := c.q.dropped(); d > c.lastDrops {
+		count(internal.SpoolDropped, int(d-c.lastDrops))
+		c.lastDrops = d
+	}
+
+	// assume that retrieving values from the counters is fast
+	// enough that we don't need a time for each one.
+	var currVal [len(counters)]uint64
+
+	for i := range counters {
+		currVal[i] = counters[i].value()
+	}
+
+	for i := range counters {
+		if v := currVal[i] - c.lastVal[i]; v > 0 {
+			count(counters[i].id, int(v))
+		}
+	}
+
+	c.lastVal = currVal
+
+	for _, n := range namedCounters() {
+		c.addType(n.name)
+
+		v := n.value()
+		if d := v - c.lastNamed[n]; d > 0 {
+			a.Counter = append(a.Counter, &mtrpb.ApplicationCounter{TypeName: n.name, Seconds: c.last.Unix(), Count: int32(d)})
+		}
+		c.lastNamed[n] = v
+	}
+
+	names, values := namedGauges()
+	for i := range names {
+		c.addType(names[i])
+		a.Metric = append(a.Metric, &mtrpb.ApplicationMetric{TypeName: names[i], Seconds: now.Unix(), Value: values[i]})
+	}
+
+	c.tmu.Lock()
+	sketches := c.sketches
+	c.sketches = make(map[string]*internal.Sketch)
+	c.tmu.Unlock()
+
+	for k, v := range sketches {
+		a.Timer = append(a.Timer, &mtrpb.ApplicationTimer{
+			SourceID:   k,
+			Seconds:    c.last.Unix(),
+			Count:      int32(v.Count),
+			Average:    int32(v.Mean()),
+			Fifty:      int32(v.Quantile(0.5)),
+			Ninety:     int32(v.Quantile(0.9)),
+			NinetyNine: int32(v.Quantile(0.99)),
+			Min:        int32(v.Min),
+			Max:        int32(v.Max),
+			Sketch:     v.Marshal(),
+		})
+	}
+
+	c.last = now
+
+	c.queueBatch(&a)
+}
+
+// addType queues adding the type for a Counter or Gauge name to the server the first time it is sent.
+func (c *Client) addType(name string) {
+	if c.types[name] {
+		return
+	}
+
+	q := url.Values{}
+	q.Add("typeID", name)
+
+	c.push("/application/type?" + q.Encode())
+	c.types[name] = true
+}
+
+// queueBatch queues a as one POST to /application/batch or, if Options.NoBatch is set, as a PUT for each row.
+func (c *Client) queueBatch(a *mtrpb.ApplicationBatch) {
+	if !c.o.NoBatch {
+		b, err := proto.Marshal(a)
+		if err != nil {
+			log.Printf("error marshalling metrics: %s", err)
+			return
+		}
+
+		c.push("/application/batch " + base64.StdEncoding.EncodeToString(b))
+		return
+	}
+
+	for _, v := range a.Metric {
+		q := c.query()
+		q.Add("typeID", typeID(v.TypeID, v.TypeName))
+		q.Add("time", time.Unix(v.Seconds, 0).UTC().Format(time.RFC3339))
+		q.Add("value", strconv.FormatInt(v.Value, 10))
+
+		c.push("/application/metric?" + q.Encode())
+	}
+
+	for _, v := range a.Counter {
+		q := c.query()
+		q.Add("typeID", typeID(v.TypeID, v.TypeName))
+		q.Add("time", time.Unix(v.Seconds, 0).UTC().Format(time.RFC3339))
+		q.Add("count", strconv.Itoa(int(v.Count)))
+
+		c.push("/application/counter?" + q.Encode())
+	}
+
+	for _, v := range a.Timer {
+		q := c.query()
+		q.Add("sourceID", v.SourceID)
+		q.Add("time", time.Unix(v.Seconds, 0).UTC().Format(time.RFC3339))
+		q.Add("count", strconv.Itoa(int(v.Count)))
+		q.Add("average", strconv.Itoa(int(v.Average)))
+		q.Add("fifty", strconv.Itoa(int(v.Fifty)))
+		q.Add("ninety", strconv.Itoa(int(v.Ninety)))
+		q.Add("ninetynine", strconv.Itoa(int(v.NinetyNine)))
+		q.Add("min", strconv.Itoa(int(v.Min)))
+		q.Add("max", strconv.Itoa(int(v.Max)))
+		q.Add("sketch", base64.StdEncoding.EncodeToString(v.Sketch))
+
+		c.push("/application/timer?" + q.Encode())
+	}
+}
+
+// typeID returns the typeID query parameter for the internal.ID id or, if id is 0, the type name.
+func typeID(id int32, name string) string {
+	if id == 0 {
+		return name
+	}
+
+	return strconv.Itoa(int(id))
+}
+
+// query returns the query parameters for the application and instance.
+func (c *Client) query() url.Values {
+	q := url.Values{}
+	q.Add("applicationID", c.o.ApplicationID)
+	q.Add("instanceID", c.o.InstanceID)
+
+	return q
+}
+
+/*
+push queues the record r.  A record is a PUT with the path and query e.g.,
+/application/metric?applicationID=... or a POST with the path and base64 encoded
+protobuf body separated by a space e.g., /application/batch CgZtdHItYXBp...
+*/
+func (c *Client) push(r string) {
+	if err := c.q.push(r); err != nil {
+		log.Printf("error queueing metric: %s", err)
+		return
+	}
+
+	select {
+	case c.queued <- true:
+	default:
+	}
+}
+
+// maxBackoff is the longest time between retries when the server is not responding.
+const maxBackoff = time.Minute
+
+// sender sends the queue in order until the Client is cancelled.  Metrics are retried
+// with back off until the server answers.
+func (c *Client) sender() {
+	defer close(c.sent)
+
+	var tries uint
+
+	for {
+		r, ok, err := c.q.peek()
+		if err != nil {
+			log.Printf("error reading metrics queue: %s", err)
+		}
+
+		if !ok {
+			select {
+			case <-c.queued:
+				continue
+			case <-c.ctx.Done():
+				return
+			}
+		}
+
+		if err = c.do(r); err == nil {
+			if err = c.q.pop(); err != nil {
+				log.Printf("error reading metrics queue: %s", err)
+			}
+			tries = 0
+			continue
+		}
+
+		log.Printf("server not responding (%s); backing off and retrying...", err)
+
+		wait := time.Second << tries
+		if wait >= maxBackoff {
+			wait = maxBackoff
+		} else {
+			tries++
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// do sends the record r to the server.  An error is only returned if r should be retried.
+func (c *Client) do(r string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.o.Timeout)
+	defer cancel()
+
+	var req *http.Request
+	var err error
+
+	if i := strings.IndexByte(r, ' '); i >= 0 {
+		var b []byte
+		if b, err = base64.StdEncoding.DecodeString(r[i+1:]); err != nil {
+			log.Printf("error decoding metrics: %s", err)
+			return nil
+		}
+
+		if req, err = http.NewRequest("POST", c.o.Server+r[:i], bytes.NewReader(b)); err == nil {
+			req.Header.Set("Content-Type", "application/x-protobuf")
+		}
+	} else {
+		req, err = http.NewRequest("PUT", c.o.Server+r, nil)
+	}
+	if err != nil {
+		log.Printf("error creating metrics request: %s", err)
+		return nil
+	}
+
+	req.SetBasicAuth(c.o.User, c.o.Key)
+
+	res, err := c.o.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusOK, res.StatusCode == http.StatusTooManyRequests:
+		// 429 is returned for metrics that have already been sent for the minute.
+		return nil
+	case res.StatusCode >= 500:
+		return fmt.Errorf("status %d", res.StatusCode)
+	default:
+		log.Printf("Non 200 code from metrics: %d", res.StatusCode)
+		return nil
+	}
+}
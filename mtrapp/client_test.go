@@ -0,0 +1,348 @@
+package mtrapp
+
+import (
+	"context"
+	"github.com/GeoNet/mtr/internal"
+	"github.com/GeoNet/mtr/mtrpb"
+	"github.com/golang/protobuf/proto"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Stop sends the counters and timers for the final minute.
+func TestClientStop(t *testing.T) {
+	var mu sync.Mutex
+	var got []mtrpb.ApplicationBatch
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "key" {
+			t.Errorf("expected basic auth for %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" || r.URL.Path != "/application/batch" {
+			t.Errorf("expected POST /application/batch got %s %s", r.Method, r.URL.Path)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("expected Content-Type application/x-protobuf got %s", r.Header.Get("Content-Type"))
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var a mtrpb.ApplicationBatch
+		if err = proto.Unmarshal(b, &a); err != nil {
+			t.Error(err)
+			return
+		}
+
+		mu.Lock()
+		got = append(got, a)
+		mu.Unlock()
+	}))
+	defer s.Close()
+
+	c, err := NewClient(Options{Server: s.URL, User: "user", Key: "key", ApplicationID: "test", InstanceID: "test-1", Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+
+	MsgErr.Inc()
+	MsgErr.Inc()
+
+	tm := Start()
+	tm.Track("test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err = c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 batch got %d", len(got))
+	}
+
+	a := got[0]
+
+	if a.ApplicationID != "test" || a.InstanceID != "test-1" {
+		t.Errorf("expected test test-1 got %s %s", a.ApplicationID, a.InstanceID)
+	}
+
+	if len(a.Counter) != 1 || a.Counter[0].Count != 2 {
+		t.Errorf("expected 1 counter with count 2 got %v", a.Counter)
+	}
+
+	if len(a.Timer) != 1 || a.Timer[0].SourceID != "test" {
+		t.Errorf("expected 1 timer for test got %v", a.Timer)
+	}
+
+	if n := expectedMetrics(); len(a.Metric) != n {
+		t.Errorf("expected %d metrics got %d", n, len(a.Metric))
+	}
+
+	// timers are not sent to stopped Clients.
+	tm.Track("test")
+
+	if err = c.Flush(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+// Timers are summarised in a sketch and are not dropped when there are many of them.
+func TestClientTimers(t *testing.T) {
+	var mu sync.Mutex
+	var a mtrpb.ApplicationBatch
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err = proto.Unmarshal(b, &a); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer s.Close()
+
+	c, err := NewClient(Options{Server: s.URL, User: "user", Key: "key", Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+
+	for i := 1; i <= 1000; i++ {
+		tm := Timer{taken: i, stopped: true}
+		tm.Track("test-timers")
+	}
+
+	if err = c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(a.Timer) != 1 {
+		t.Fatalf("expected 1 timer got %d", len(a.Timer))
+	}
+
+	v := a.Timer[0]
+
+	if v.Count != 1000 || v.Min != 1 || v.Max != 1000 || v.Average != 500 {
+		t.Errorf("expected count 1000 min 1 max 1000 average 500 got %d %d %d %d", v.Count, v.Min, v.Max, v.Average)
+	}
+
+	if v.Fifty < 495 || v.Fifty > 505 || v.Ninety < 890 || v.Ninety > 910 || v.NinetyNine < 980 || v.NinetyNine > 1000 {
+		t.Errorf("expected percentiles close to 500, 900, and 990 got %d %d %d", v.Fifty, v.Ninety, v.NinetyNine)
+	}
+
+	sk, err := internal.UnmarshalSketch(v.Sketch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sk.Count != 1000 {
+		t.Errorf("expected 1000 timers in the sketch got %d", sk.Count)
+	}
+}
+
+// With NoBatch each metric, counter, and timer is a PUT.
+func TestClientNoBatch(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string][]string)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("expected PUT got %s", r.Method)
+		}
+
+		mu.Lock()
+		got[r.URL.Path] = append(got[r.URL.Path], r.URL.RawQuery)
+		mu.Unlock()
+	}))
+	defer s.Close()
+
+	c, err := NewClient(Options{Server: s.URL, User: "user", Key: "key", ApplicationID: "test", InstanceID: "test-1",
+		Interval: time.Hour, NoBatch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+
+	MsgErr.Inc()
+
+	tm := Start()
+	tm.Track("test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err = c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(got["/application/counter"]) != 1 {
+		t.Errorf("expected 1 counter got %d", len(got["/application/counter"]))
+	}
+
+	if len(got["/application/timer"]) != 1 {
+		t.Errorf("expected 1 timer got %d", len(got["/application/timer"]))
+	}
+
+	if n := expectedMetrics(); len(got["/application/metric"]) != n {
+		t.Errorf("expected %d metrics got %d", n, len(got["/application/metric"]))
+	}
+}
+
+// Types for Counters and Gauges from NewCounter and NewGauge are added before they are sent.
+func TestClientNamed(t *testing.T) {
+	defer func() {
+		named.Lock()
+		named.counters = make(map[string]*Counter)
+		named.gauges = make(map[string]*Gauge)
+		named.Unlock()
+	}()
+
+	var mu sync.Mutex
+	var got []string
+	var a mtrpb.ApplicationBatch
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got = append(got, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("typeID"))
+
+		if r.URL.Path == "/application/batch" {
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if err = proto.Unmarshal(b, &a); err != nil {
+				t.Error(err)
+			}
+		}
+	}))
+	defer s.Close()
+
+	c, err := NewClient(Options{Server: s.URL, User: "user", Key: "key", Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := NewCounter("quakes.published")
+	n.Inc()
+
+	c.Start()
+
+	if NewCounter("quakes.published") != n {
+		t.Error("expected the same Counter for the same name")
+	}
+
+	n.Inc()
+	n.Inc()
+
+	NewGauge("queue.depth", func() int64 { return 42 })
+
+	if err = c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := []string{
+		"PUT /application/type quakes.published",
+		"PUT /application/type queue.depth",
+		"POST /application/batch ",
+	}
+
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("expected requests %v got %v", expected, got)
+	}
+
+	var counted bool
+	for _, v := range a.Counter {
+		if v.TypeName == "quakes.published" {
+			counted = true
+			// the increment before the Client was started is not counted.
+			if v.Count != 2 {
+				t.Errorf("expected count 2 got %d", v.Count)
+			}
+		}
+	}
+
+	if !counted {
+		t.Error("expected a counter for quakes.published")
+	}
+
+	var gauged bool
+	for _, v := range a.Metric {
+		if v.TypeName == "queue.depth" {
+			gauged = true
+			if v.Value != 42 {
+				t.Errorf("expected value 42 got %d", v.Value)
+			}
+		}
+	}
+
+	if !gauged {
+		t.Error("expected a metric for queue.depth")
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient(Options{Server: "http://localhost"}); err == nil {
+		t.Error("expected error for missing User and Key")
+	}
+
+	var c *Client
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Errorf("expected nil error stopping a nil Client got %s", err)
+	}
+}
+
+// expectedMetrics returns the number of memory and runtime metrics sent each interval
+// on this platform.
+func expectedMetrics() int {
+	n := 12
+
+	if _, ok := openFDs(); ok {
+		n++
+	}
+
+	if _, ok := cpuTime(); ok {
+		n++
+	}
+
+	return n
+}
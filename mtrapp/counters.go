@@ -2,12 +2,18 @@ package mtrapp
 
 import (
 	"github.com/GeoNet/mtr/internal"
+	"sort"
+	"sync"
 	"sync/atomic"
 )
 
 // Increment these counters as required.
 var (
 	Requests                  = Counter{id: internal.Requests}                  // HTTP requests
+	Status2xx                 = Counter{id: internal.Status2xx}                 // HTTP status 200 - 299
+	Status3xx                 = Counter{id: internal.Status3xx}                 // HTTP status 300 - 399
+	Status4xx                 = Counter{id: internal.Status4xx}                 // HTTP status 400 - 499
+	Status5xx                 = Counter{id: internal.Status5xx}                 // HTTP status 500 - 599
 	StatusOK                  = Counter{id: internal.StatusOK}                  // HTTP status 200
 	StatusBadRequest          = Counter{id: internal.StatusBadRequest}          // HTTP status 400
 	StatusUnauthorized        = Counter{id: internal.StatusUnauthorized}        // HTTP status 401
@@ -22,6 +28,10 @@ var (
 
 var counters = [...]*Counter{
 	&Requests,
+	&Status2xx,
+	&Status3xx,
+	&Status4xx,
+	&Status5xx,
 	&StatusOK,
 	&StatusBadRequest,
 	&StatusUnauthorized,
@@ -34,13 +44,60 @@ var counters = [...]*Counter{
 	&MsgErr,
 }
 
-var lastVal [len(counters)]uint64
-var currVal [len(counters)]uint64
+// named are the Counters and Gauges from NewCounter and NewGauge keyed by name.
+var named = struct {
+	sync.Mutex
+	counters map[string]*Counter
+	gauges   map[string]*Gauge
+}{
+	counters: make(map[string]*Counter),
+	gauges:   make(map[string]*Gauge),
+}
 
 // Counter is for counting events.  It is safe for concurrent access.
 type Counter struct {
-	i  uint64
-	id internal.ID
+	i    uint64
+	id   internal.ID
+	name string // for Counters from NewCounter.
+}
+
+/*
+NewCounter returns a Counter for the application events called name e.g., quakes.published.
+The type for name is added to mtr-api the first time the Counter is sent.  Calling NewCounter
+again with the same name returns the same Counter.  name must not be a number.
+*/
+func NewCounter(name string) *Counter {
+	named.Lock()
+	defer named.Unlock()
+
+	if c, ok := named.counters[name]; ok {
+		return c
+	}
+
+	c := &Counter{name: name}
+	named.counters[name] = c
+
+	return c
+}
+
+// namedCounters returns the Counters from NewCounter sorted by name.
+func namedCounters() []*Counter {
+	named.Lock()
+	defer named.Unlock()
+
+	var names []string
+	for k := range named.counters {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	c := make([]*Counter, len(names))
+	for i, n := range names {
+		c[i] = named.counters[n]
+	}
+
+	return c
 }
 
 // Inc increments the counter by 1.
@@ -0,0 +1,59 @@
+package mtrapp
+
+import (
+	"sort"
+)
+
+// Gauge is an application value that is read each time metrics are sent e.g., a queue depth.
+type Gauge struct {
+	name string
+	f    func() int64
+}
+
+/*
+NewGauge returns a Gauge called name e.g., queue.depth.  f is called to read the value each
+time metrics are sent and must be safe to call from another goroutine.  The type for name is
+added to mtr-api the first time the Gauge is sent.  Calling NewGauge again with the same name
+replaces f.  name must not be a number.
+*/
+func NewGauge(name string, f func() int64) *Gauge {
+	named.Lock()
+	defer named.Unlock()
+
+	g, ok := named.gauges[name]
+	if !ok {
+		g = &Gauge{name: name}
+		named.gauges[name] = g
+	}
+
+	g.f = f
+
+	return g
+}
+
+// namedGauges returns the names and values of the Gauges sorted by name.
+func namedGauges() ([]string, []int64) {
+	named.Lock()
+
+	var names []string
+	for k := range named.gauges {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	f := make([]func() int64, len(names))
+	for i, n := range names {
+		f[i] = named.gauges[n].f
+	}
+
+	named.Unlock()
+
+	// the values are read without the lock in case f uses NewGauge.
+	v := make([]int64, len(names))
+	for i := range f {
+		v[i] = f[i]()
+	}
+
+	return names, v
+}
@@ -0,0 +1,117 @@
+package mtrapp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HandlerOptions are the options for Handler.
+type HandlerOptions struct {
+	// Route returns the timer id for a request.  Use it to collapse high cardinality paths
+	// e.g., /quake/2016p123456 to GET /quake/*.  Defaults to the method and path e.g., GET /quake/2016p123456
+	Route func(r *http.Request) string
+}
+
+/*
+Handler returns h instrumented with mtrapp metrics.  Each request increments Requests, the
+counter for the class of the response status e.g., Status4xx, and the counter for the status if
+there is one e.g., StatusNotFound.  The time taken by h is tracked with the id from o.Route e.g.,
+
+	http.Handle("/", mtrapp.Handler(mux, mtrapp.HandlerOptions{Route: mtrapp.PathDepth(1)}))
+*/
+func Handler(h http.Handler, o HandlerOptions) http.Handler {
+	route := o.Route
+	if route == nil {
+		route = func(r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := Start()
+
+		s := &statusWriter{ResponseWriter: w}
+
+		h.ServeHTTP(s, r)
+
+		t.Track(route(r))
+
+		Requests.Inc()
+
+		code := s.code
+		if code == 0 {
+			// nothing was written.
+			code = http.StatusOK
+		}
+
+		switch code / 100 {
+		case 2:
+			Status2xx.Inc()
+		case 3:
+			Status3xx.Inc()
+		case 4:
+			Status4xx.Inc()
+		case 5:
+			Status5xx.Inc()
+		}
+
+		switch code {
+		case http.StatusOK:
+			StatusOK.Inc()
+		case http.StatusBadRequest:
+			StatusBadRequest.Inc()
+		case http.StatusUnauthorized:
+			StatusUnauthorized.Inc()
+		case http.StatusNotFound:
+			StatusNotFound.Inc()
+		case http.StatusInternalServerError:
+			StatusInternalServerError.Inc()
+		case http.StatusServiceUnavailable:
+			StatusServiceUnavailable.Inc()
+		}
+	})
+}
+
+/*
+PathDepth returns a HandlerOptions.Route that keeps the method and the first n segments of the
+path and collapses the rest to * e.g., for n = 1 GET /quake/2016p123456 is GET /quake/*
+*/
+func PathDepth(n int) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		p := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", n+1)
+		if len(p) > n {
+			p[n] = "*"
+		}
+
+		return r.Method + " /" + strings.Join(p, "/")
+	}
+}
+
+// statusWriter records the status written to the ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	if s.code == 0 {
+		s.code = code
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusWriter) Write(b []byte) (int, error) {
+	if s.code == 0 {
+		s.code = http.StatusOK
+	}
+
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the wrapped ResponseWriter does.
+func (s *statusWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,89 @@
+package mtrapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	c, err := NewClient(Options{Server: s.URL, User: "user", Key: "key", Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+	defer c.Stop(context.Background())
+
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/quake/missing":
+			http.NotFound(w, r)
+		case "/redirect":
+			http.Redirect(w, r, "/quake", http.StatusMovedPermanently)
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+			w.WriteHeader(http.StatusOK) // ignored.
+		}
+	}), HandlerOptions{Route: PathDepth(1)})
+
+	requests, ok, c2xx, c3xx, c4xx, notFound, c5xx := Requests.value(), StatusOK.value(), Status2xx.value(),
+		Status3xx.value(), Status4xx.value(), StatusNotFound.value(), Status5xx.value()
+
+	for _, p := range []string{"/quake/2016p123456", "/quake/missing", "/redirect", "/error", "/"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+
+	for _, v := range []struct {
+		c        *Counter
+		before   uint64
+		expected uint64
+	}{
+		{&Requests, requests, 5},
+		{&StatusOK, ok, 2},
+		{&Status2xx, c2xx, 2},
+		{&Status3xx, c3xx, 1},
+		{&Status4xx, c4xx, 1},
+		{&StatusNotFound, notFound, 1},
+		{&Status5xx, c5xx, 1},
+	} {
+		if d := v.c.value() - v.before; d != v.expected {
+			t.Errorf("counter %d expected %d got %d", v.c.id, v.expected, d)
+		}
+	}
+
+	c.tmu.Lock()
+	defer c.tmu.Unlock()
+
+	for k, n := range map[string]int64{"GET /quake/*": 2, "GET /redirect": 1, "GET /error": 1, "GET /": 1} {
+		if s, ok := c.sketches[k]; !ok || s.Count != n {
+			t.Errorf("expected %d timers for %s", n, k)
+		}
+	}
+}
+
+func TestPathDepth(t *testing.T) {
+	in := []struct {
+		n        int
+		path     string
+		expected string
+	}{
+		{1, "/quake/2016p123456", "GET /quake/*"},
+		{1, "/quake", "GET /quake"},
+		{2, "/field/metric/summary", "GET /field/metric/*"},
+		{2, "/field/metric", "GET /field/metric"},
+		{1, "/", "GET /"},
+		{0, "/quake", "GET /*"},
+	}
+
+	for _, v := range in {
+		if r := PathDepth(v.n)(httptest.NewRequest("GET", v.path, nil)); r != v.expected {
+			t.Errorf("%d %s expected %s got %s", v.n, v.path, v.expected, r)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package mtrapp
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// openFDs returns the number of open file descriptors for the process.
+func openFDs() (int64, bool) {
+	d, err := os.Open("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	defer d.Close()
+
+	n, err := d.Readdirnames(-1)
+	if err != nil {
+		return 0, false
+	}
+
+	// less the descriptor used to read the directory.
+	return int64(len(n) - 1), true
+}
+
+// cpuTime returns the user and system CPU time used by the process.
+func cpuTime() (time.Duration, bool) {
+	var r syscall.Rusage
+
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &r); err != nil {
+		return 0, false
+	}
+
+	return time.Duration(r.Utime.Nano() + r.Stime.Nano()), true
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package mtrapp
+
+import (
+	"time"
+)
+
+// openFDs is only available on Linux.
+func openFDs() (int64, bool) {
+	return 0, false
+}
+
+// cpuTime is only available on Linux.
+func cpuTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,36 @@
+package mtrapp
+
+import (
+	"runtime"
+	"runtime/pprof"
+	"sort"
+)
+
+// gcPauses returns the 50th percentile and max of the GC pauses (µs) after GC number last.
+// Only the most recent 256 pauses are kept by the runtime.  Returns 0s if there have been no GCs.
+func gcPauses(mem *runtime.MemStats, last uint32) (fifty, max int64) {
+	n := int(mem.NumGC - last)
+	if n > len(mem.PauseNs) {
+		n = len(mem.PauseNs)
+	}
+
+	if n <= 0 {
+		return
+	}
+
+	p := make([]int, n)
+
+	// the most recent pause is at PauseNs[(NumGC+255)%256].
+	for i := range p {
+		p[i] = int(mem.PauseNs[(int(mem.NumGC)-1-i+len(mem.PauseNs))%len(mem.PauseNs)] / 1000)
+	}
+
+	sort.Ints(p)
+
+	return int64(p[(n-1)/2]), int64(p[n-1])
+}
+
+// threads returns the number of OS threads created by the runtime.
+func threads() int64 {
+	return int64(pprof.Lookup("threadcreate").Count())
+}
@@ -0,0 +1,32 @@
+package mtrapp
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGCPauses(t *testing.T) {
+	var mem runtime.MemStats
+
+	if f, m := gcPauses(&mem, 0); f != 0 || m != 0 {
+		t.Errorf("expected 0 0 for no GCs got %d %d", f, m)
+	}
+
+	// three GCs since last, the buffer wraps at 256.
+	mem.NumGC = 257
+	mem.PauseNs[0] = 9000
+	mem.PauseNs[255] = 1000
+	mem.PauseNs[254] = 5000
+	mem.PauseNs[253] = 100000
+
+	if f, m := gcPauses(&mem, 254); f != 5 || m != 9 {
+		t.Errorf("expected 5 9 got %d %d", f, m)
+	}
+
+	// more GCs than the runtime keeps.
+	mem.NumGC = 1000
+
+	if _, m := gcPauses(&mem, 1); m != 100 {
+		t.Errorf("expected max 100 got %d", m)
+	}
+}
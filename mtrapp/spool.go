@@ -0,0 +1,372 @@
+package mtrapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+Metrics waiting to be sent to the server are queued in time order.  Each record in the
+queue is a request for the server - see Client.push.
+
+If Options.SpoolDir is set the queue is a spool of segment files in the directory so that metrics
+survive restarts and mtr-api outages.  Records are appended to the newest segment and read from
+the oldest.  Segments are removed once they have been sent.  When the spool is larger than
+Options.SpoolBytes the oldest segment is dropped.  Without a SpoolDir the queue is in memory
+and is bounded in the same way by dropping the oldest records.
+*/
+
+// segmentBytes is the size at which a new segment is started.
+const segmentBytes = 1 << 20
+
+// spoolBytes is the default bound on the size of a queue.
+const spoolBytes = 64 << 20
+
+type queue interface {
+	// push adds r to the end of the queue.  The oldest records are dropped if the queue is full.
+	push(r string) error
+	// peek returns the oldest record.  ok is false if the queue is empty.
+	peek() (r string, ok bool, err error)
+	// pop removes the oldest record.
+	pop() error
+	// depth returns the number of records in the queue.
+	depth() int
+	// dropped returns the number of records dropped since the queue was created.
+	dropped() uint64
+}
+
+// memQueue is a queue in memory.
+type memQueue struct {
+	sync.Mutex
+	r        []string
+	size     int64
+	maxBytes int64
+	drops    uint64
+}
+
+func newMemQueue(maxBytes int64) *memQueue {
+	return &memQueue{maxBytes: maxBytes}
+}
+
+func (q *memQueue) push(r string) error {
+	q.Lock()
+	defer q.Unlock()
+
+	q.r = append(q.r, r)
+	q.size += int64(len(r))
+
+	for q.size > q.maxBytes && len(q.r) > 1 {
+		q.size -= int64(len(q.r[0]))
+		q.r = q.r[1:]
+		q.drops++
+	}
+
+	return nil
+}
+
+func (q *memQueue) peek() (string, bool, error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.r) == 0 {
+		return "", false, nil
+	}
+
+	return q.r[0], true, nil
+}
+
+func (q *memQueue) pop() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.r) > 0 {
+		q.size -= int64(len(q.r[0]))
+		q.r = q.r[1:]
+	}
+
+	return nil
+}
+
+func (q *memQueue) depth() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return len(q.r)
+}
+
+func (q *memQueue) dropped() uint64 {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.drops
+}
+
+// spool is a queue of segment files in dir.  Records are one per line.
+type spool struct {
+	sync.Mutex
+	dir      string
+	maxBytes int64
+	segBytes int64      // the size at which a new segment is started.
+	segs     []*segment // oldest first.  The last segment is open for appending in w.
+	w        *os.File
+	next     uint64   // the sequence number for the next segment.
+	off      int64    // the offset of the unread records in segs[0].
+	read     []string // records read from segs[0] that have not been popped.
+	n        int
+	drops    uint64
+}
+
+type segment struct {
+	name    string
+	size    int64
+	records int // records that have not been popped.
+}
+
+// segmentName returns the file name for segment seq.  Names sort in time order.
+func segmentName(seq uint64) string {
+	return fmt.Sprintf("%020d.spool", seq)
+}
+
+// openSpool opens the spool in dir, creating it if needed.  Records already
+// in the spool are sent before new records.
+func openSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.spool"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	s := &spool{dir: dir, maxBytes: maxBytes, segBytes: segmentBytes}
+
+	for _, name := range names {
+		var seq uint64
+		if _, err = fmt.Sscanf(filepath.Base(name), "%020d.spool", &seq); err != nil {
+			continue
+		}
+
+		var b []byte
+		if b, err = ioutil.ReadFile(name); err != nil {
+			return nil, err
+		}
+
+		// a partial last line from a crash is ignored.
+		seg := &segment{name: name, size: int64(len(b)), records: bytes.Count(b, []byte("\n"))}
+
+		s.segs = append(s.segs, seg)
+		s.n += seg.records
+		s.next = seq + 1
+	}
+
+	// always start a new segment for appending in case the last one has a partial line.
+	if err = s.roll(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// roll starts a new segment for appending.
+func (s *spool) roll() error {
+	if s.w != nil {
+		if err := s.w.Close(); err != nil {
+			return err
+		}
+	}
+
+	name := filepath.Join(s.dir, segmentName(s.next))
+	s.next++
+
+	w, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	s.w = w
+	s.segs = append(s.segs, &segment{name: name})
+
+	return nil
+}
+
+func (s *spool) push(r string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.w == nil {
+		return errors.New("spool is closed")
+	}
+
+	if s.segs[len(s.segs)-1].size >= s.segBytes {
+		if err := s.roll(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.w.WriteString(r + "\n"); err != nil {
+		return err
+	}
+
+	seg := s.segs[len(s.segs)-1]
+	seg.size += int64(len(r) + 1)
+	seg.records++
+	s.n++
+
+	// drop the oldest segments until the spool fits.
+	for s.size() > s.maxBytes && len(s.segs) > 1 {
+		s.drops += uint64(s.segs[0].records)
+		s.n -= s.segs[0].records
+
+		if err := s.remove(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// size returns the total size of the segments.
+func (s *spool) size() int64 {
+	var n int64
+	for _, seg := range s.segs {
+		n += seg.size
+	}
+
+	return n
+}
+
+// remove removes the oldest segment, which must not be the segment open for appending.
+func (s *spool) remove() error {
+	name := s.segs[0].name
+
+	s.segs = s.segs[1:]
+	s.off = 0
+	s.read = nil
+
+	return os.Remove(name)
+}
+
+func (s *spool) peek() (string, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for len(s.read) == 0 {
+		if s.n == 0 {
+			return "", false, nil
+		}
+
+		seg := s.segs[0]
+
+		if s.off < seg.size {
+			f, err := os.Open(seg.name)
+			if err != nil {
+				return "", false, err
+			}
+
+			b := make([]byte, seg.size-s.off)
+			_, err = f.ReadAt(b, s.off)
+			f.Close()
+			if err != nil {
+				return "", false, err
+			}
+
+			// only complete lines.
+			if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+				s.read = strings.Split(string(b[:i]), "\n")
+				s.off += int64(i + 1)
+			} else {
+				s.off = seg.size
+			}
+
+			continue
+		}
+
+		if len(s.segs) == 1 {
+			// the records have been lost e.g., the file was truncated.
+			s.n = 0
+			return "", false, nil
+		}
+
+		s.n -= seg.records
+
+		if err := s.remove(); err != nil {
+			return "", false, err
+		}
+	}
+
+	return s.read[0], true, nil
+}
+
+func (s *spool) pop() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.read) == 0 {
+		return nil
+	}
+
+	s.read = s.read[1:]
+	s.segs[0].records--
+	s.n--
+
+	// when everything has been sent start again with an empty segment so the
+	// spool doesn't grow and records aren't sent again after a restart.
+	if s.n == 0 && len(s.read) == 0 {
+		for len(s.segs) > 1 {
+			if err := s.remove(); err != nil {
+				return err
+			}
+		}
+
+		if s.w != nil {
+			if err := s.w.Truncate(0); err != nil {
+				return err
+			}
+		}
+
+		s.segs[0].size = 0
+		s.segs[0].records = 0
+		s.off = 0
+	}
+
+	return nil
+}
+
+func (s *spool) depth() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.n
+}
+
+func (s *spool) dropped() uint64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.drops
+}
+
+// close closes the segment open for appending.
+func (s *spool) close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.w == nil {
+		return nil
+	}
+
+	err := s.w.Close()
+	s.w = nil
+
+	return err
+}
@@ -0,0 +1,207 @@
+package mtrapp
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Records are read in order and those not popped are read again after the spool is reopened.
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtrapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := openSpool(dir, spoolBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []string{"a", "b", "c"} {
+		if err = s.push(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectPeek(t, s, "a")
+
+	if err = s.pop(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectPeek(t, s, "b")
+
+	if err = s.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = openSpool(dir, spoolBytes); err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+
+	// a is sent again.  The server rejects repeats for the same minute.
+	if s.depth() != 3 {
+		t.Errorf("expected depth 3 got %d", s.depth())
+	}
+
+	if err = s.push("d"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []string{"a", "b", "c", "d"} {
+		expectPeek(t, s, r)
+
+		if err = s.pop(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, ok, _ := s.peek(); ok {
+		t.Error("expected empty spool")
+	}
+
+	// the sent segments have been removed.
+	names, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 1 {
+		t.Errorf("expected 1 segment got %d", len(names))
+	}
+}
+
+// The oldest segments are dropped when the spool is full.
+func TestSpoolFull(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtrapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := openSpool(dir, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+
+	s.segBytes = 4
+
+	for _, r := range []string{"aaa", "bbb", "ccc", "ddd", "eee"} {
+		if err = s.push(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.dropped() != 2 {
+		t.Errorf("expected 2 dropped got %d", s.dropped())
+	}
+
+	if s.depth() != 3 {
+		t.Errorf("expected depth 3 got %d", s.depth())
+	}
+
+	expectPeek(t, s, "ccc")
+}
+
+func TestMemQueueFull(t *testing.T) {
+	q := newMemQueue(6)
+
+	for _, r := range []string{"aa", "bb", "cc", "dd"} {
+		q.push(r)
+	}
+
+	if q.dropped() != 1 {
+		t.Errorf("expected 1 dropped got %d", q.dropped())
+	}
+
+	expectPeek(t, q, "bb")
+}
+
+// Metrics are left in the spool while the server is unavailable and sent by the next Client.
+func TestClientSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtrapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var up bool
+	var got []string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		got = append(got, r.URL.Path)
+	}))
+	defer s.Close()
+
+	o := Options{Server: s.URL, User: "user", Key: "key", Interval: time.Hour, SpoolDir: dir}
+
+	c, err := NewClient(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err = c.Stop(ctx); err == nil {
+		t.Error("expected error stopping with the server unavailable")
+	}
+
+	if c.q.depth() == 0 {
+		t.Error("expected metrics in the spool")
+	}
+
+	mu.Lock()
+	up = true
+	mu.Unlock()
+
+	if c, err = NewClient(o); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Start()
+
+	if err = c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// a batch from each Client.
+	if len(got) != 2 {
+		t.Errorf("expected 2 batches got %d: %s", len(got), strings.Join(got, ", "))
+	}
+}
+
+func expectPeek(t *testing.T, q queue, expected string) {
+	r, ok, err := q.peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok || r != expected {
+		t.Errorf("expected %s got %s", expected, r)
+	}
+}
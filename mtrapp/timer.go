@@ -4,17 +4,6 @@ import (
 	"time"
 )
 
-var timers chan Timer
-
-// for aggregating timers
-var count = make(map[string]int)
-var sum = make(map[string]int)
-var taken = make(map[string][]int)
-
-func init() {
-	timers = make(chan Timer, 300)
-}
-
 // Timer is for timing events
 type Timer struct {
 	start   time.Time
@@ -37,7 +26,7 @@ func (t *Timer) Stop() {
 }
 
 // Stops the timer if it is not already stopped.  Tracks the time taken
-// in milliseconds with identity id.
+// in milliseconds with identity id in all started Clients.
 func (t *Timer) Track(id string) {
 	if !t.stopped {
 		t.Stop()
@@ -45,10 +34,11 @@ func (t *Timer) Track(id string) {
 
 	t.id = id
 
-	select {
-	case timers <- *t:
-	default:
+	started.RLock()
+	for _, c := range started.c {
+		c.track(t.id, t.taken)
 	}
+	started.RUnlock()
 }
 
 // Returns the time taken between start and stop in milliseconds.
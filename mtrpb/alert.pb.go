@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go.
+// source: alert.proto
+// DO NOT EDIT!
+
+/*
+Package mtrpb is a generated protocol buffer package.
+
+It is generated from these files:
+	alert.proto
+	app.proto
+	data.proto
+	detail.proto
+	field.proto
+	ingest.proto
+	retention.proto
+	tag.proto
+
+It has these top-level messages:
+	Alert
+	AlertResult
+	NotifyRule
+	NotifyRuleResult
+	AppIDSummary
+	AppIDSummaryResult
+	ApplicationBatch
+	ApplicationMetric
+	ApplicationCounter
+	ApplicationTimer
+	DataLatencySummary
+	DataLatencySummaryResult
+	DataSite
+	DataSiteAttribute
+	DataSiteResult
+	DataSiteLocation
+	DataSiteLocationResult
+	DataLatencyTag
+	DataLatencyTagResult
+	DataLatencyThreshold
+	DataLatencyThresholdResult
+	DataType
+	DataTypeResult
+	DataLatency
+	DataLatencyResult
+	DataCompletenessSummary
+	DataCompletenessSummaryResult
+	DataCompletenessTag
+	DataCompletenessTagResult
+	DataInterval
+	DataIntervalResult
+	DataLatencyBatchRow
+	DataLatencyBatch
+	DataCompletenessBatchRow
+	DataCompletenessBatch
+	FieldDeviceDetail
+	DataSiteDetail
+	FieldMetricSummary
+	FieldMetricSummaryResult
+	FieldMetricTag
+	FieldMetricTagResult
+	FieldMetricThreshold
+	FieldMetricThresholdResult
+	FieldMetricInterval
+	FieldMetricIntervalResult
+	FieldModel
+	FieldModelResult
+	FieldDevice
+	FieldDeviceAttribute
+	FieldDeviceResult
+	FieldDeviceLocation
+	FieldDeviceLocationResult
+	FieldType
+	FieldTypeResult
+	FieldState
+	FieldStateResult
+	FieldStateTag
+	FieldStateTagResult
+	FieldMetric
+	FieldMetricResult
+	FieldMetricBatchRow
+	FieldMetricBatch
+	BatchRowResult
+	BatchResult
+	IngestMap
+	IngestMapResult
+	PromWriteRequest
+	PromTimeSeries
+	PromLabel
+	PromSample
+	RetentionPolicy
+	RetentionPolicyResult
+	Tag
+	TagResult
+	TagSearchResult
+*/
+package mtrpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Alert is opened when a metric summary is outside its threshold and closed
+// when it is back inside the threshold.
+type Alert struct {
+	// The source of the metric; field.metric, data.latency, or data.completeness
+	Source string `protobuf:"bytes,1,opt,name=source" json:"source,omitempty"`
+	// The deviceID or siteID for the metric e.g., TAUP
+	ID string `protobuf:"bytes,2,opt,name=iD" json:"iD,omitempty"`
+	// The typeID for the metric e.g., latency.strong
+	TypeID string `protobuf:"bytes,3,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds when the alert was opened.
+	Opened int64 `protobuf:"varint,4,opt,name=opened" json:"opened,omitempty"`
+	// Unix time in seconds when the alert was closed.  0 if the alert is still open.
+	Closed int64 `protobuf:"varint,5,opt,name=closed" json:"closed,omitempty"`
+	// The metric value when the alert was opened.
+	Value float64 `protobuf:"fixed64,6,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Alert) Reset()                    { *m = Alert{} }
+func (m *Alert) String() string            { return proto.CompactTextString(m) }
+func (*Alert) ProtoMessage()               {}
+func (*Alert) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type AlertResult struct {
+	Result []*Alert `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *AlertResult) Reset()                    { *m = AlertResult{} }
+func (m *AlertResult) String() string            { return proto.CompactTextString(m) }
+func (*AlertResult) ProtoMessage()               {}
+func (*AlertResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *AlertResult) GetResult() []*Alert {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// NotifyRule sends notifications for alerts on metrics with tag to receiver.
+type NotifyRule struct {
+	// The tag e.g., TAUP
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	// The receiver for notifications e.g., https://example.com/hook, mailto:ops@example.com, file:///var/log/mtr-alert.log, or stdout
+	Receiver string `protobuf:"bytes,2,opt,name=receiver" json:"receiver,omitempty"`
+}
+
+func (m *NotifyRule) Reset()                    { *m = NotifyRule{} }
+func (m *NotifyRule) String() string            { return proto.CompactTextString(m) }
+func (*NotifyRule) ProtoMessage()               {}
+func (*NotifyRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type NotifyRuleResult struct {
+	Result []*NotifyRule `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *NotifyRuleResult) Reset()                    { *m = NotifyRuleResult{} }
+func (m *NotifyRuleResult) String() string            { return proto.CompactTextString(m) }
+func (*NotifyRuleResult) ProtoMessage()               {}
+func (*NotifyRuleResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *NotifyRuleResult) GetResult() []*NotifyRule {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Alert)(nil), "mtrpb.Alert")
+	proto.RegisterType((*AlertResult)(nil), "mtrpb.AlertResult")
+	proto.RegisterType((*NotifyRule)(nil), "mtrpb.NotifyRule")
+	proto.RegisterType((*NotifyRuleResult)(nil), "mtrpb.NotifyRuleResult")
+}
+
+var fileDescriptor0 = []byte{
+	// 246 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x90, 0x41, 0x4b, 0xc4, 0x30,
+	0x10, 0x85, 0x49, 0x6b, 0xbb, 0x3a, 0x15, 0x59, 0x83, 0x68, 0xf0, 0x54, 0x8a, 0x87, 0x7a, 0xe9,
+	0xc1, 0xbd, 0x09, 0x1e, 0x94, 0x5e, 0xbc, 0x78, 0xc8, 0xd1, 0x8b, 0x74, 0xbb, 0xa3, 0x04, 0xa2,
+	0x29, 0x69, 0xb2, 0xd0, 0x3f, 0xe1, 0x6f, 0x96, 0x24, 0x83, 0xca, 0xde, 0xe6, 0x7b, 0x79, 0x99,
+	0x37, 0x33, 0x50, 0x0d, 0x1a, 0xad, 0xeb, 0x26, 0x6b, 0x9c, 0xe1, 0xc5, 0xa7, 0xb3, 0xd3, 0xb6,
+	0xf9, 0x66, 0x50, 0x3c, 0x06, 0x99, 0x5f, 0x42, 0x39, 0x1b, 0x6f, 0x47, 0x14, 0xac, 0x66, 0xed,
+	0x89, 0x24, 0xe2, 0x67, 0x90, 0xa9, 0x5e, 0x64, 0x51, 0xcb, 0x54, 0xcf, 0xaf, 0x60, 0xe5, 0x96,
+	0x09, 0xdf, 0x54, 0x2f, 0xf2, 0x64, 0x0c, 0xf8, 0xdc, 0x87, 0x06, 0x66, 0xc2, 0x2f, 0xdc, 0x89,
+	0xa3, 0x9a, 0xb5, 0xb9, 0x24, 0x0a, 0xfa, 0xa8, 0xcd, 0x8c, 0x3b, 0x51, 0x24, 0x3d, 0x11, 0xbf,
+	0x80, 0x62, 0x3f, 0x68, 0x8f, 0xa2, 0xac, 0x59, 0xcb, 0x64, 0x82, 0x66, 0x03, 0x55, 0x9c, 0x47,
+	0xe2, 0xec, 0xb5, 0xe3, 0x37, 0x50, 0xda, 0x58, 0x09, 0x56, 0xe7, 0x6d, 0x75, 0x77, 0xda, 0xc5,
+	0xb9, 0xbb, 0xe4, 0xa1, 0xb7, 0xe6, 0x1e, 0xe0, 0xc5, 0x38, 0xf5, 0xbe, 0x48, 0xaf, 0x91, 0xaf,
+	0x21, 0x77, 0xc3, 0x07, 0xad, 0x11, 0x4a, 0x7e, 0x0d, 0xc7, 0x16, 0x47, 0x54, 0x7b, 0xb4, 0xb4,
+	0xc9, 0x2f, 0x37, 0x0f, 0xb0, 0xfe, 0xfb, 0x4b, 0xa9, 0xb7, 0x07, 0xa9, 0xe7, 0x94, 0xfa, 0xcf,
+	0x48, 0x86, 0xa7, 0xd5, 0x6b, 0xba, 0xe4, 0xb6, 0x8c, 0x77, 0xdd, 0xfc, 0x0c, 0x00, 0xf6, 0x95,
+	0x4e, 0x44, 0x66, 0x01, 0x00, 0x00,
+}
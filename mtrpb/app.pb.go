@@ -2,56 +2,6 @@
 // source: app.proto
 // DO NOT EDIT!
 
-/*
-Package mtrpb is a generated protocol buffer package.
-
-It is generated from these files:
-	app.proto
-	data.proto
-	field.proto
-	tag.proto
-
-It has these top-level messages:
-	AppIDSummary
-	AppIDSummaryResult
-	DataLatencySummary
-	DataLatencySummaryResult
-	DataSite
-	DataSiteResult
-	DataLatencyTag
-	DataLatencyTagResult
-	DataLatencyThreshold
-	DataLatencyThresholdResult
-	DataType
-	DataTypeResult
-	DataLatency
-	DataLatencyResult
-	DataCompletenessSummary
-	DataCompletenessSummaryResult
-	DataCompletenessTag
-	DataCompletenessTagResult
-	FieldMetricSummary
-	FieldMetricSummaryResult
-	FieldMetricTag
-	FieldMetricTagResult
-	FieldMetricThreshold
-	FieldMetricThresholdResult
-	FieldModel
-	FieldModelResult
-	FieldDevice
-	FieldDeviceResult
-	FieldType
-	FieldTypeResult
-	FieldState
-	FieldStateResult
-	FieldStateTag
-	FieldStateTagResult
-	FieldMetric
-	FieldMetricResult
-	Tag
-	TagResult
-	TagSearchResult
-*/
 package mtrpb
 
 import proto "github.com/golang/protobuf/proto"
@@ -63,12 +13,6 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
-
 type AppIDSummary struct {
 	// The applicationid for the metric e.g., mtr-api
 	ApplicationID string `protobuf:"bytes,1,opt,name=application_iD,json=applicationID" json:"application_iD,omitempty"`
@@ -77,7 +21,7 @@ type AppIDSummary struct {
 func (m *AppIDSummary) Reset()                    { *m = AppIDSummary{} }
 func (m *AppIDSummary) String() string            { return proto.CompactTextString(m) }
 func (*AppIDSummary) ProtoMessage()               {}
-func (*AppIDSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*AppIDSummary) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
 
 type AppIDSummaryResult struct {
 	Result []*AppIDSummary `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -86,7 +30,7 @@ type AppIDSummaryResult struct {
 func (m *AppIDSummaryResult) Reset()                    { *m = AppIDSummaryResult{} }
 func (m *AppIDSummaryResult) String() string            { return proto.CompactTextString(m) }
 func (*AppIDSummaryResult) ProtoMessage()               {}
-func (*AppIDSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*AppIDSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
 
 func (m *AppIDSummaryResult) GetResult() []*AppIDSummary {
 	if m != nil {
@@ -95,20 +39,139 @@ func (m *AppIDSummaryResult) GetResult() []*AppIDSummary {
 	return nil
 }
 
+// ApplicationBatch is all the metrics, counters, and timers for an application instance
+// for a minute.  It is uploaded by mtrapp in one request.
+type ApplicationBatch struct {
+	// The applicationID e.g., mtr-api
+	ApplicationID string `protobuf:"bytes,1,opt,name=application_iD,json=applicationID" json:"application_iD,omitempty"`
+	// The instanceID e.g., the host name.
+	InstanceID string                `protobuf:"bytes,2,opt,name=instance_iD,json=instanceID" json:"instance_iD,omitempty"`
+	Metric     []*ApplicationMetric  `protobuf:"bytes,3,rep,name=metric" json:"metric,omitempty"`
+	Counter    []*ApplicationCounter `protobuf:"bytes,4,rep,name=counter" json:"counter,omitempty"`
+	Timer      []*ApplicationTimer   `protobuf:"bytes,5,rep,name=timer" json:"timer,omitempty"`
+}
+
+func (m *ApplicationBatch) Reset()                    { *m = ApplicationBatch{} }
+func (m *ApplicationBatch) String() string            { return proto.CompactTextString(m) }
+func (*ApplicationBatch) ProtoMessage()               {}
+func (*ApplicationBatch) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+
+func (m *ApplicationBatch) GetMetric() []*ApplicationMetric {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
+func (m *ApplicationBatch) GetCounter() []*ApplicationCounter {
+	if m != nil {
+		return m.Counter
+	}
+	return nil
+}
+
+func (m *ApplicationBatch) GetTimer() []*ApplicationTimer {
+	if m != nil {
+		return m.Timer
+	}
+	return nil
+}
+
+// ApplicationMetric is a value for an app.type e.g., MemSys.
+type ApplicationMetric struct {
+	// The typeID (app.type typePK) e.g., 1000 for MemSys
+	TypeID int32 `protobuf:"varint,1,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds for the value.
+	Seconds int64 `protobuf:"varint,2,opt,name=seconds" json:"seconds,omitempty"`
+	Value   int64 `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+	// The typeID for a type added with PUT /application/type e.g., queue.depth.
+	// Used when type_iD is 0.
+	TypeName string `protobuf:"bytes,4,opt,name=type_name,json=typeName" json:"type_name,omitempty"`
+}
+
+func (m *ApplicationMetric) Reset()                    { *m = ApplicationMetric{} }
+func (m *ApplicationMetric) String() string            { return proto.CompactTextString(m) }
+func (*ApplicationMetric) ProtoMessage()               {}
+func (*ApplicationMetric) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+// ApplicationCounter is a count for an app.type for the minute starting at seconds.
+type ApplicationCounter struct {
+	// The typeID (app.type typePK) e.g., 200 for StatusOK
+	TypeID int32 `protobuf:"varint,1,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds for the start of the minute.
+	Seconds int64 `protobuf:"varint,2,opt,name=seconds" json:"seconds,omitempty"`
+	Count   int32 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	// The typeID for a type added with PUT /application/type e.g., quakes.published.
+	// Used when type_iD is 0.
+	TypeName string `protobuf:"bytes,4,opt,name=type_name,json=typeName" json:"type_name,omitempty"`
+}
+
+func (m *ApplicationCounter) Reset()                    { *m = ApplicationCounter{} }
+func (m *ApplicationCounter) String() string            { return proto.CompactTextString(m) }
+func (*ApplicationCounter) ProtoMessage()               {}
+func (*ApplicationCounter) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+// ApplicationTimer is the timing for a source for the minute starting at seconds.
+type ApplicationTimer struct {
+	// The sourceID e.g., a function name.
+	SourceID string `protobuf:"bytes,1,opt,name=source_iD,json=sourceID" json:"source_iD,omitempty"`
+	// Unix time in seconds for the start of the minute.
+	Seconds int64 `protobuf:"varint,2,opt,name=seconds" json:"seconds,omitempty"`
+	// The number of times the source was timed.
+	Count int32 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	// The average, fiftieth, and ninetieth percentile times (ms).
+	Average int32 `protobuf:"varint,4,opt,name=average" json:"average,omitempty"`
+	Fifty   int32 `protobuf:"varint,5,opt,name=fifty" json:"fifty,omitempty"`
+	Ninety  int32 `protobuf:"varint,6,opt,name=ninety" json:"ninety,omitempty"`
+	// The minimum, maximum, and ninety ninth percentile times (ms).
+	Min        int32 `protobuf:"varint,7,opt,name=min" json:"min,omitempty"`
+	Max        int32 `protobuf:"varint,8,opt,name=max" json:"max,omitempty"`
+	NinetyNine int32 `protobuf:"varint,9,opt,name=ninety_nine,json=ninetyNine" json:"ninety_nine,omitempty"`
+	// The times encoded as an internal.Sketch for merging with other minutes and instances.
+	Sketch []byte `protobuf:"bytes,10,opt,name=sketch,proto3" json:"sketch,omitempty"`
+}
+
+func (m *ApplicationTimer) Reset()                    { *m = ApplicationTimer{} }
+func (m *ApplicationTimer) String() string            { return proto.CompactTextString(m) }
+func (*ApplicationTimer) ProtoMessage()               {}
+func (*ApplicationTimer) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+
 func init() {
 	proto.RegisterType((*AppIDSummary)(nil), "mtrpb.AppIDSummary")
 	proto.RegisterType((*AppIDSummaryResult)(nil), "mtrpb.AppIDSummaryResult")
+	proto.RegisterType((*ApplicationBatch)(nil), "mtrpb.ApplicationBatch")
+	proto.RegisterType((*ApplicationMetric)(nil), "mtrpb.ApplicationMetric")
+	proto.RegisterType((*ApplicationCounter)(nil), "mtrpb.ApplicationCounter")
+	proto.RegisterType((*ApplicationTimer)(nil), "mtrpb.ApplicationTimer")
 }
 
-var fileDescriptor0 = []byte{
-	// 132 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x4c, 0x2c, 0x28, 0xd0,
-	0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xcd, 0x2d, 0x29, 0x2a, 0x48, 0x52, 0x32, 0xe5, 0xe2,
-	0x71, 0x2c, 0x28, 0xf0, 0x74, 0x09, 0x2e, 0xcd, 0xcd, 0x4d, 0x2c, 0xaa, 0x14, 0x52, 0xe5, 0xe2,
-	0x03, 0xaa, 0xc9, 0xc9, 0x4c, 0x4e, 0x2c, 0xc9, 0xcc, 0xcf, 0x8b, 0xcf, 0x74, 0x91, 0x60, 0x54,
-	0x60, 0xd4, 0xe0, 0x0c, 0xe2, 0x45, 0x12, 0xf5, 0x74, 0x51, 0x72, 0xe4, 0x12, 0x42, 0xd6, 0x16,
-	0x94, 0x5a, 0x5c, 0x9a, 0x53, 0x22, 0xa4, 0xcd, 0xc5, 0x56, 0x04, 0x66, 0x01, 0x35, 0x31, 0x6b,
-	0x70, 0x1b, 0x09, 0xeb, 0x81, 0x2d, 0xd1, 0x43, 0x51, 0x0a, 0x55, 0xe2, 0xc4, 0x1e, 0x05, 0x71,
-	0x42, 0x12, 0x1b, 0xd8, 0x41, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4d, 0x1e, 0xa5, 0xab,
-	0x9d, 0x00, 0x00, 0x00,
+var fileDescriptor1 = []byte{
+	// 424 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x53, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0x56, 0x96, 0x25, 0x69, 0xdf, 0x06, 0x2a, 0x06, 0xb1, 0x87, 0x38, 0x50, 0x45, 0x42, 0xaa,
+	0x84, 0xa8, 0x10, 0x13, 0x3f, 0xa0, 0x23, 0x97, 0x1e, 0xd8, 0xc1, 0x70, 0xe2, 0x32, 0x79, 0xc1,
+	0x63, 0x16, 0xb5, 0x63, 0x39, 0xce, 0xb4, 0x70, 0xe6, 0x07, 0xf3, 0x13, 0x90, 0x9f, 0x13, 0xb5,
+	0x50, 0x09, 0x4d, 0x3d, 0xd5, 0xdf, 0xf7, 0xbe, 0xef, 0x7d, 0xef, 0xb9, 0x31, 0x4c, 0x85, 0xb5,
+	0x4b, 0xeb, 0x1a, 0xdf, 0xb0, 0x4c, 0x7b, 0x67, 0xaf, 0xcb, 0x0f, 0x70, 0xba, 0xb2, 0x76, 0x5d,
+	0x7d, 0xee, 0xb4, 0x16, 0xae, 0x67, 0xaf, 0xe1, 0xb1, 0xb0, 0x76, 0xa3, 0x6a, 0xe1, 0x55, 0x63,
+	0xae, 0x54, 0x85, 0xc9, 0x3c, 0x59, 0x4c, 0xf9, 0xa3, 0x1d, 0x76, 0x5d, 0x95, 0x2b, 0x60, 0xbb,
+	0x36, 0x2e, 0xdb, 0x6e, 0xe3, 0xd9, 0x1b, 0xc8, 0x1d, 0x9d, 0x30, 0x99, 0xa7, 0x8b, 0x93, 0xf7,
+	0x4f, 0x97, 0x14, 0xb2, 0xfc, 0x4b, 0x3a, 0x48, 0xca, 0xdf, 0x09, 0xcc, 0x56, 0xdb, 0xa6, 0x17,
+	0xc2, 0xd7, 0xb7, 0x0f, 0x8c, 0x67, 0xaf, 0xe0, 0x44, 0x99, 0xd6, 0x0b, 0x53, 0xcb, 0xa0, 0x39,
+	0x22, 0x0d, 0x8c, 0xd4, 0xba, 0x62, 0xef, 0x20, 0xd7, 0xd2, 0x3b, 0x55, 0x63, 0x4a, 0x93, 0xe0,
+	0x76, 0x92, 0xb1, 0xcd, 0x27, 0xaa, 0xf3, 0x41, 0xc7, 0xce, 0xa1, 0xa8, 0x9b, 0xce, 0x78, 0xe9,
+	0xf0, 0x98, 0x2c, 0x2f, 0xf6, 0x2d, 0x1f, 0xa3, 0x80, 0x8f, 0x4a, 0xf6, 0x16, 0x32, 0xaf, 0xb4,
+	0x74, 0x98, 0x91, 0xe5, 0x6c, 0xdf, 0xf2, 0x25, 0x94, 0x79, 0x54, 0x95, 0x3d, 0x3c, 0xd9, 0x1b,
+	0x80, 0x9d, 0x41, 0xe1, 0x7b, 0x2b, 0xc7, 0x5d, 0x33, 0x9e, 0x07, 0xb8, 0xae, 0x18, 0x42, 0xd1,
+	0xca, 0xba, 0x31, 0xdf, 0x5a, 0x5a, 0x30, 0xe5, 0x23, 0x64, 0xcf, 0x20, 0xbb, 0x13, 0x9b, 0x4e,
+	0x62, 0x4a, 0x7c, 0x04, 0xec, 0x25, 0x4c, 0xa9, 0x91, 0x11, 0x5a, 0xe2, 0x31, 0x5d, 0xc9, 0x24,
+	0x10, 0x97, 0x42, 0xcb, 0xf2, 0x27, 0xb0, 0x9d, 0xe8, 0x61, 0x91, 0x03, 0xb3, 0x69, 0x7b, 0xca,
+	0xce, 0x78, 0x04, 0xff, 0xcf, 0xfe, 0x75, 0x04, 0xb3, 0x7f, 0xaf, 0x24, 0x38, 0xda, 0xa6, 0x73,
+	0xb5, 0xdc, 0xfe, 0xc9, 0x93, 0x48, 0x1c, 0x10, 0x8f, 0x50, 0x88, 0x3b, 0xe9, 0xc4, 0xf7, 0x18,
+	0x9e, 0xf1, 0x11, 0x06, 0xfd, 0x8d, 0xba, 0xf1, 0x3d, 0x66, 0x51, 0x4f, 0x80, 0x3d, 0x87, 0xdc,
+	0x28, 0x23, 0x7d, 0x8f, 0x79, 0x5c, 0x3b, 0x22, 0x36, 0x83, 0x54, 0x2b, 0x83, 0x05, 0x91, 0xe1,
+	0x48, 0x8c, 0xb8, 0xc7, 0xc9, 0xc0, 0x88, 0xfb, 0xf0, 0xed, 0x45, 0xf5, 0x55, 0xf8, 0xc1, 0x29,
+	0x55, 0x20, 0x52, 0x97, 0xca, 0xc8, 0xd0, 0xbc, 0xfd, 0x21, 0x7d, 0x7d, 0x8b, 0x30, 0x4f, 0x16,
+	0xa7, 0x7c, 0x40, 0x17, 0xc5, 0xd7, 0xf8, 0xe6, 0xae, 0x73, 0x7a, 0x81, 0xe7, 0x7f, 0x06, 0x00,
+	0xa2, 0x99, 0x45, 0x56, 0x8e, 0x03, 0x00, 0x00,
 }
@@ -35,12 +35,30 @@ type DataLatencySummary struct {
 	Lower int32 `protobuf:"varint,8,opt,name=lower" json:"lower,omitempty"`
 	// the scale factor to apply to the threshold values
 	Scale float64 `protobuf:"fixed64,9,opt,name=scale" json:"scale,omitempty"`
+	// true if there has been no value for longer than the expected reporting interval for the metric.
+	Late bool `protobuf:"varint,10,opt,name=late" json:"late,omitempty"`
+	// true if the latency has no threshold.  Upper and lower are 0.
+	Unknown bool `protobuf:"varint,11,opt,name=unknown" json:"unknown,omitempty"`
+	// The upper threshold for the metric to be good.  Values above it and below upper are warning.
+	// warning_upper and warning_lower are 0 if there is no warning threshold.
+	WarningUpper int32 `protobuf:"varint,12,opt,name=warning_upper,json=warningUpper" json:"warning_upper,omitempty"`
+	// The lower threshold for the metric to be good.  Values below it and above lower are warning.
+	WarningLower int32 `protobuf:"varint,13,opt,name=warning_lower,json=warningLower" json:"warning_lower,omitempty"`
+	// The values above as floats.  The integer values above are rounded from them and are kept
+	// for existing clients.
+	FloatMean         float64 `protobuf:"fixed64,14,opt,name=float_mean,json=floatMean" json:"float_mean,omitempty"`
+	FloatFifty        float64 `protobuf:"fixed64,15,opt,name=float_fifty,json=floatFifty" json:"float_fifty,omitempty"`
+	FloatNinety       float64 `protobuf:"fixed64,16,opt,name=float_ninety,json=floatNinety" json:"float_ninety,omitempty"`
+	FloatUpper        float64 `protobuf:"fixed64,17,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatLower        float64 `protobuf:"fixed64,18,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
+	FloatWarningUpper float64 `protobuf:"fixed64,19,opt,name=float_warning_upper,json=floatWarningUpper" json:"float_warning_upper,omitempty"`
+	FloatWarningLower float64 `protobuf:"fixed64,20,opt,name=float_warning_lower,json=floatWarningLower" json:"float_warning_lower,omitempty"`
 }
 
 func (m *DataLatencySummary) Reset()                    { *m = DataLatencySummary{} }
 func (m *DataLatencySummary) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencySummary) ProtoMessage()               {}
-func (*DataLatencySummary) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+func (*DataLatencySummary) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
 type DataLatencySummaryResult struct {
 	Result []*DataLatencySummary `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -49,7 +67,7 @@ type DataLatencySummaryResult struct {
 func (m *DataLatencySummaryResult) Reset()                    { *m = DataLatencySummaryResult{} }
 func (m *DataLatencySummaryResult) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencySummaryResult) ProtoMessage()               {}
-func (*DataLatencySummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+func (*DataLatencySummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
 func (m *DataLatencySummaryResult) GetResult() []*DataLatencySummary {
 	if m != nil {
@@ -65,12 +83,34 @@ type DataSite struct {
 	Latitude float64 `protobuf:"fixed64,2,opt,name=latitude" json:"latitude,omitempty"`
 	// The site longitude - not usually accurate enough for meta data
 	Longitude float64 `protobuf:"fixed64,3,opt,name=longitude" json:"longitude,omitempty"`
+	// The lifecycle status e.g., planned, active, maintenance, or retired
+	Status string `protobuf:"bytes,4,opt,name=status" json:"status,omitempty"`
+	// Key value attributes for the site e.g., owner
+	Attribute []*DataSiteAttribute `protobuf:"bytes,5,rep,name=attribute" json:"attribute,omitempty"`
 }
 
 func (m *DataSite) Reset()                    { *m = DataSite{} }
 func (m *DataSite) String() string            { return proto.CompactTextString(m) }
 func (*DataSite) ProtoMessage()               {}
-func (*DataSite) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+func (*DataSite) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+
+func (m *DataSite) GetAttribute() []*DataSiteAttribute {
+	if m != nil {
+		return m.Attribute
+	}
+	return nil
+}
+
+type DataSiteAttribute struct {
+	// The attribute key e.g., owner
+	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *DataSiteAttribute) Reset()                    { *m = DataSiteAttribute{} }
+func (m *DataSiteAttribute) String() string            { return proto.CompactTextString(m) }
+func (*DataSiteAttribute) ProtoMessage()               {}
+func (*DataSiteAttribute) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
 
 type DataSiteResult struct {
 	Result []*DataSite `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -79,7 +119,7 @@ type DataSiteResult struct {
 func (m *DataSiteResult) Reset()                    { *m = DataSiteResult{} }
 func (m *DataSiteResult) String() string            { return proto.CompactTextString(m) }
 func (*DataSiteResult) ProtoMessage()               {}
-func (*DataSiteResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+func (*DataSiteResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
 
 func (m *DataSiteResult) GetResult() []*DataSite {
 	if m != nil {
@@ -88,6 +128,37 @@ func (m *DataSiteResult) GetResult() []*DataSite {
 	return nil
 }
 
+// DataSiteLocation is a location for a site in effect from seconds until the next location.
+type DataSiteLocation struct {
+	// The siteID e.g., TAUP
+	SiteID string `protobuf:"bytes,1,opt,name=site_iD,json=siteID" json:"site_iD,omitempty"`
+	// Unix time in seconds the location is in effect from
+	Seconds   int64   `protobuf:"varint,2,opt,name=seconds" json:"seconds,omitempty"`
+	Latitude  float64 `protobuf:"fixed64,3,opt,name=latitude" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,4,opt,name=longitude" json:"longitude,omitempty"`
+}
+
+func (m *DataSiteLocation) Reset()                    { *m = DataSiteLocation{} }
+func (m *DataSiteLocation) String() string            { return proto.CompactTextString(m) }
+func (*DataSiteLocation) ProtoMessage()               {}
+func (*DataSiteLocation) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
+
+type DataSiteLocationResult struct {
+	Result []*DataSiteLocation `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *DataSiteLocationResult) Reset()                    { *m = DataSiteLocationResult{} }
+func (m *DataSiteLocationResult) String() string            { return proto.CompactTextString(m) }
+func (*DataSiteLocationResult) ProtoMessage()               {}
+func (*DataSiteLocationResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{6} }
+
+func (m *DataSiteLocationResult) GetResult() []*DataSiteLocation {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 type DataLatencyTag struct {
 	// The siteID for the latency e.g., TAUP
 	SiteID string `protobuf:"bytes,1,opt,name=site_iD,json=siteID" json:"site_iD,omitempty"`
@@ -100,7 +171,7 @@ type DataLatencyTag struct {
 func (m *DataLatencyTag) Reset()                    { *m = DataLatencyTag{} }
 func (m *DataLatencyTag) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencyTag) ProtoMessage()               {}
-func (*DataLatencyTag) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*DataLatencyTag) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{7} }
 
 type DataLatencyTagResult struct {
 	Result []*DataLatencyTag `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -109,7 +180,7 @@ type DataLatencyTagResult struct {
 func (m *DataLatencyTagResult) Reset()                    { *m = DataLatencyTagResult{} }
 func (m *DataLatencyTagResult) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencyTagResult) ProtoMessage()               {}
-func (*DataLatencyTagResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+func (*DataLatencyTagResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{8} }
 
 func (m *DataLatencyTagResult) GetResult() []*DataLatencyTag {
 	if m != nil {
@@ -129,21 +200,42 @@ type DataLatencyThreshold struct {
 	Upper int32 `protobuf:"varint,4,opt,name=upper" json:"upper,omitempty"`
 	// the scale factor to apply to the threshold values
 	Scale float64 `protobuf:"fixed64,5,opt,name=scale" json:"scale,omitempty"`
+	// true if the threshold is inherited from the template for the type.
+	// false if it is set (overridden) for the site.
+	Inherited bool `protobuf:"varint,6,opt,name=inherited" json:"inherited,omitempty"`
+	// The lower threshold for the latency to be good.  Values between lower and warning_lower are warning.
+	// warning_lower and warning_upper are 0 if there is no warning threshold.
+	WarningLower int32 `protobuf:"varint,7,opt,name=warning_lower,json=warningLower" json:"warning_lower,omitempty"`
+	// The upper threshold for the latency to be good.  Values between warning_upper and upper are warning.
+	WarningUpper int32 `protobuf:"varint,8,opt,name=warning_upper,json=warningUpper" json:"warning_upper,omitempty"`
+	// An alert opened for a latency outside lower and upper is not closed until the latency is
+	// back inside them by at least hysteresis.
+	Hysteresis int32 `protobuf:"varint,9,opt,name=hysteresis" json:"hysteresis,omitempty"`
+	// The thresholds above as floats.  The integer thresholds above are rounded from them and are
+	// kept for existing clients.
+	FloatLower        float64 `protobuf:"fixed64,10,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
+	FloatUpper        float64 `protobuf:"fixed64,11,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatWarningLower float64 `protobuf:"fixed64,12,opt,name=float_warning_lower,json=floatWarningLower" json:"float_warning_lower,omitempty"`
+	FloatWarningUpper float64 `protobuf:"fixed64,13,opt,name=float_warning_upper,json=floatWarningUpper" json:"float_warning_upper,omitempty"`
+	FloatHysteresis   float64 `protobuf:"fixed64,14,opt,name=float_hysteresis,json=floatHysteresis" json:"float_hysteresis,omitempty"`
 }
 
 func (m *DataLatencyThreshold) Reset()                    { *m = DataLatencyThreshold{} }
 func (m *DataLatencyThreshold) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencyThreshold) ProtoMessage()               {}
-func (*DataLatencyThreshold) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+func (*DataLatencyThreshold) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{9} }
 
 type DataLatencyThresholdResult struct {
+	// The thresholds in effect for each site.
 	Result []*DataLatencyThreshold `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+	// The templates for each type.  site_iD is empty.
+	Template []*DataLatencyThreshold `protobuf:"bytes,2,rep,name=template" json:"template,omitempty"`
 }
 
 func (m *DataLatencyThresholdResult) Reset()                    { *m = DataLatencyThresholdResult{} }
 func (m *DataLatencyThresholdResult) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencyThresholdResult) ProtoMessage()               {}
-func (*DataLatencyThresholdResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+func (*DataLatencyThresholdResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{10} }
 
 func (m *DataLatencyThresholdResult) GetResult() []*DataLatencyThreshold {
 	if m != nil {
@@ -152,17 +244,31 @@ func (m *DataLatencyThresholdResult) GetResult() []*DataLatencyThreshold {
 	return nil
 }
 
+func (m *DataLatencyThresholdResult) GetTemplate() []*DataLatencyThreshold {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
 type DataType struct {
 	// The TypeID in the table data.type
 	TypeID string `protobuf:"bytes,1,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
 	// The display field in the table data.type (the y label for plotting)
-	Display string `protobuf:"bytes,2,opt,name=display" json:"display,omitempty"`
+	Display     string `protobuf:"bytes,2,opt,name=display" json:"display,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	// the unit values are stored in
+	Unit string `protobuf:"bytes,4,opt,name=unit" json:"unit,omitempty"`
+	// values are multiplied by scale for display
+	Scale float64 `protobuf:"fixed64,5,opt,name=scale" json:"scale,omitempty"`
+	// the expected count for a completeness type (data.completeness_type).
+	Expected int32 `protobuf:"varint,6,opt,name=expected" json:"expected,omitempty"`
 }
 
 func (m *DataType) Reset()                    { *m = DataType{} }
 func (m *DataType) String() string            { return proto.CompactTextString(m) }
 func (*DataType) ProtoMessage()               {}
-func (*DataType) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+func (*DataType) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{11} }
 
 type DataTypeResult struct {
 	Result []*DataType `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -171,7 +277,7 @@ type DataTypeResult struct {
 func (m *DataTypeResult) Reset()                    { *m = DataTypeResult{} }
 func (m *DataTypeResult) String() string            { return proto.CompactTextString(m) }
 func (*DataTypeResult) ProtoMessage()               {}
-func (*DataTypeResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{9} }
+func (*DataTypeResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{12} }
 
 func (m *DataTypeResult) GetResult() []*DataType {
 	if m != nil {
@@ -190,12 +296,16 @@ type DataLatency struct {
 	Fifty int32 `protobuf:"varint,3,opt,name=fifty" json:"fifty,omitempty"`
 	// The ninetieth percentile value.  Might be unknown (0)
 	Ninety int32 `protobuf:"varint,4,opt,name=ninety" json:"ninety,omitempty"`
+	// The latencies above as doubles.  mean, fifty, and ninety are kept for existing clients.
+	FloatMean   float64 `protobuf:"fixed64,5,opt,name=float_mean,json=floatMean" json:"float_mean,omitempty"`
+	FloatFifty  float64 `protobuf:"fixed64,6,opt,name=float_fifty,json=floatFifty" json:"float_fifty,omitempty"`
+	FloatNinety float64 `protobuf:"fixed64,7,opt,name=float_ninety,json=floatNinety" json:"float_ninety,omitempty"`
 }
 
 func (m *DataLatency) Reset()                    { *m = DataLatency{} }
 func (m *DataLatency) String() string            { return proto.CompactTextString(m) }
 func (*DataLatency) ProtoMessage()               {}
-func (*DataLatency) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{10} }
+func (*DataLatency) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{13} }
 
 type DataLatencyResult struct {
 	// The siteID for the metric e.g., TAUP
@@ -209,12 +319,16 @@ type DataLatencyResult struct {
 	Result []*DataLatency `protobuf:"bytes,5,rep,name=result" json:"result,omitempty"`
 	// the scale factor to apply to the threshold values
 	Scale float64 `protobuf:"fixed64,6,opt,name=scale" json:"scale,omitempty"`
+	// The values above as floats.  The integer values above are rounded from them and are kept
+	// for existing clients.
+	FloatUpper float64 `protobuf:"fixed64,7,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatLower float64 `protobuf:"fixed64,8,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
 }
 
 func (m *DataLatencyResult) Reset()                    { *m = DataLatencyResult{} }
 func (m *DataLatencyResult) String() string            { return proto.CompactTextString(m) }
 func (*DataLatencyResult) ProtoMessage()               {}
-func (*DataLatencyResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{11} }
+func (*DataLatencyResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{14} }
 
 func (m *DataLatencyResult) GetResult() []*DataLatency {
 	if m != nil {
@@ -237,12 +351,14 @@ type DataCompletenessSummary struct {
 	Seconds int64 `protobuf:"varint,3,opt,name=seconds" json:"seconds,omitempty"`
 	// The completeness for a given period of time
 	Completeness float32 `protobuf:"fixed32,4,opt,name=completeness" json:"completeness,omitempty"`
+	// true if there has been no value for longer than the expected reporting interval for the metric.
+	Late bool `protobuf:"varint,5,opt,name=late" json:"late,omitempty"`
 }
 
 func (m *DataCompletenessSummary) Reset()                    { *m = DataCompletenessSummary{} }
 func (m *DataCompletenessSummary) String() string            { return proto.CompactTextString(m) }
 func (*DataCompletenessSummary) ProtoMessage()               {}
-func (*DataCompletenessSummary) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{12} }
+func (*DataCompletenessSummary) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{15} }
 
 type DataCompletenessSummaryResult struct {
 	Result []*DataCompletenessSummary `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -251,7 +367,7 @@ type DataCompletenessSummaryResult struct {
 func (m *DataCompletenessSummaryResult) Reset()                    { *m = DataCompletenessSummaryResult{} }
 func (m *DataCompletenessSummaryResult) String() string            { return proto.CompactTextString(m) }
 func (*DataCompletenessSummaryResult) ProtoMessage()               {}
-func (*DataCompletenessSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{13} }
+func (*DataCompletenessSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{16} }
 
 func (m *DataCompletenessSummaryResult) GetResult() []*DataCompletenessSummary {
 	if m != nil {
@@ -272,7 +388,7 @@ type DataCompletenessTag struct {
 func (m *DataCompletenessTag) Reset()                    { *m = DataCompletenessTag{} }
 func (m *DataCompletenessTag) String() string            { return proto.CompactTextString(m) }
 func (*DataCompletenessTag) ProtoMessage()               {}
-func (*DataCompletenessTag) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{14} }
+func (*DataCompletenessTag) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{17} }
 
 type DataCompletenessTagResult struct {
 	Result []*DataCompletenessTag `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -281,7 +397,7 @@ type DataCompletenessTagResult struct {
 func (m *DataCompletenessTagResult) Reset()                    { *m = DataCompletenessTagResult{} }
 func (m *DataCompletenessTagResult) String() string            { return proto.CompactTextString(m) }
 func (*DataCompletenessTagResult) ProtoMessage()               {}
-func (*DataCompletenessTagResult) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{15} }
+func (*DataCompletenessTagResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{18} }
 
 func (m *DataCompletenessTagResult) GetResult() []*DataCompletenessTag {
 	if m != nil {
@@ -290,11 +406,128 @@ func (m *DataCompletenessTagResult) GetResult() []*DataCompletenessTag {
 	return nil
 }
 
+// DataInterval is the expected reporting interval for a data latency or completeness metric.
+// If site_iD is empty it is the interval for all metrics of type_iD
+// otherwise it overrides the interval for the site.
+type DataInterval struct {
+	// The siteID for the metric e.g., TAUP
+	SiteID string `protobuf:"bytes,1,opt,name=site_iD,json=siteID" json:"site_iD,omitempty"`
+	// The typeID for the metric e.g., latency.strong
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// The expected reporting interval in seconds.  0 means the metric is never late.
+	ExpectedInterval int32 `protobuf:"varint,3,opt,name=expected_interval,json=expectedInterval" json:"expected_interval,omitempty"`
+}
+
+func (m *DataInterval) Reset()                    { *m = DataInterval{} }
+func (m *DataInterval) String() string            { return proto.CompactTextString(m) }
+func (*DataInterval) ProtoMessage()               {}
+func (*DataInterval) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{19} }
+
+type DataIntervalResult struct {
+	Result []*DataInterval `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *DataIntervalResult) Reset()                    { *m = DataIntervalResult{} }
+func (m *DataIntervalResult) String() string            { return proto.CompactTextString(m) }
+func (*DataIntervalResult) ProtoMessage()               {}
+func (*DataIntervalResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{20} }
+
+func (m *DataIntervalResult) GetResult() []*DataInterval {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// DataLatencyBatchRow is one data latency value in a batch upload.
+type DataLatencyBatchRow struct {
+	// The siteID for the metric e.g., TAUP
+	SiteID string `protobuf:"bytes,1,opt,name=site_iD,json=siteID" json:"site_iD,omitempty"`
+	// The typeID for the metric e.g., latency.strong
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds for the metric value (don't need nanos).
+	Seconds int64 `protobuf:"varint,3,opt,name=seconds" json:"seconds,omitempty"`
+	// The mean latency
+	Mean int32 `protobuf:"varint,4,opt,name=mean" json:"mean,omitempty"`
+	// The minimum latency.  Might be unknown (0)
+	Min int32 `protobuf:"varint,5,opt,name=min" json:"min,omitempty"`
+	// The maximum latency.  Might be unknown (0)
+	Max int32 `protobuf:"varint,6,opt,name=max" json:"max,omitempty"`
+	// The fiftieth percentile value.  Might be unknown (0)
+	Fifty int32 `protobuf:"varint,7,opt,name=fifty" json:"fifty,omitempty"`
+	// The ninetieth percentile value.  Might be unknown (0)
+	Ninety int32 `protobuf:"varint,8,opt,name=ninety" json:"ninety,omitempty"`
+	// The latencies as floats.  If they are not 0 they are used instead of the integer
+	// latencies above, which are kept for existing senders.
+	FloatMean   float64 `protobuf:"fixed64,9,opt,name=float_mean,json=floatMean" json:"float_mean,omitempty"`
+	FloatMin    float64 `protobuf:"fixed64,10,opt,name=float_min,json=floatMin" json:"float_min,omitempty"`
+	FloatMax    float64 `protobuf:"fixed64,11,opt,name=float_max,json=floatMax" json:"float_max,omitempty"`
+	FloatFifty  float64 `protobuf:"fixed64,12,opt,name=float_fifty,json=floatFifty" json:"float_fifty,omitempty"`
+	FloatNinety float64 `protobuf:"fixed64,13,opt,name=float_ninety,json=floatNinety" json:"float_ninety,omitempty"`
+}
+
+func (m *DataLatencyBatchRow) Reset()                    { *m = DataLatencyBatchRow{} }
+func (m *DataLatencyBatchRow) String() string            { return proto.CompactTextString(m) }
+func (*DataLatencyBatchRow) ProtoMessage()               {}
+func (*DataLatencyBatchRow) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{21} }
+
+type DataLatencyBatch struct {
+	Row []*DataLatencyBatchRow `protobuf:"bytes,1,rep,name=row" json:"row,omitempty"`
+}
+
+func (m *DataLatencyBatch) Reset()                    { *m = DataLatencyBatch{} }
+func (m *DataLatencyBatch) String() string            { return proto.CompactTextString(m) }
+func (*DataLatencyBatch) ProtoMessage()               {}
+func (*DataLatencyBatch) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{22} }
+
+func (m *DataLatencyBatch) GetRow() []*DataLatencyBatchRow {
+	if m != nil {
+		return m.Row
+	}
+	return nil
+}
+
+// DataCompletenessBatchRow is one data completeness count in a batch upload.
+type DataCompletenessBatchRow struct {
+	// The siteID for the completeness e.g., TAUP
+	SiteID string `protobuf:"bytes,1,opt,name=site_iD,json=siteID" json:"site_iD,omitempty"`
+	// The typeID for the completeness e.g., completeness.gnss.1hz
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds for the metric value (don't need nanos).
+	Seconds int64 `protobuf:"varint,3,opt,name=seconds" json:"seconds,omitempty"`
+	// The count for the period
+	Count int32 `protobuf:"varint,4,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *DataCompletenessBatchRow) Reset()                    { *m = DataCompletenessBatchRow{} }
+func (m *DataCompletenessBatchRow) String() string            { return proto.CompactTextString(m) }
+func (*DataCompletenessBatchRow) ProtoMessage()               {}
+func (*DataCompletenessBatchRow) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{23} }
+
+type DataCompletenessBatch struct {
+	Row []*DataCompletenessBatchRow `protobuf:"bytes,1,rep,name=row" json:"row,omitempty"`
+}
+
+func (m *DataCompletenessBatch) Reset()                    { *m = DataCompletenessBatch{} }
+func (m *DataCompletenessBatch) String() string            { return proto.CompactTextString(m) }
+func (*DataCompletenessBatch) ProtoMessage()               {}
+func (*DataCompletenessBatch) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{24} }
+
+func (m *DataCompletenessBatch) GetRow() []*DataCompletenessBatchRow {
+	if m != nil {
+		return m.Row
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DataLatencySummary)(nil), "mtrpb.DataLatencySummary")
 	proto.RegisterType((*DataLatencySummaryResult)(nil), "mtrpb.DataLatencySummaryResult")
 	proto.RegisterType((*DataSite)(nil), "mtrpb.DataSite")
+	proto.RegisterType((*DataSiteAttribute)(nil), "mtrpb.DataSiteAttribute")
 	proto.RegisterType((*DataSiteResult)(nil), "mtrpb.DataSiteResult")
+	proto.RegisterType((*DataSiteLocation)(nil), "mtrpb.DataSiteLocation")
+	proto.RegisterType((*DataSiteLocationResult)(nil), "mtrpb.DataSiteLocationResult")
 	proto.RegisterType((*DataLatencyTag)(nil), "mtrpb.DataLatencyTag")
 	proto.RegisterType((*DataLatencyTagResult)(nil), "mtrpb.DataLatencyTagResult")
 	proto.RegisterType((*DataLatencyThreshold)(nil), "mtrpb.DataLatencyThreshold")
@@ -307,43 +540,87 @@ func init() {
 	proto.RegisterType((*DataCompletenessSummaryResult)(nil), "mtrpb.DataCompletenessSummaryResult")
 	proto.RegisterType((*DataCompletenessTag)(nil), "mtrpb.DataCompletenessTag")
 	proto.RegisterType((*DataCompletenessTagResult)(nil), "mtrpb.DataCompletenessTagResult")
+	proto.RegisterType((*DataInterval)(nil), "mtrpb.DataInterval")
+	proto.RegisterType((*DataIntervalResult)(nil), "mtrpb.DataIntervalResult")
+	proto.RegisterType((*DataLatencyBatchRow)(nil), "mtrpb.DataLatencyBatchRow")
+	proto.RegisterType((*DataLatencyBatch)(nil), "mtrpb.DataLatencyBatch")
+	proto.RegisterType((*DataCompletenessBatchRow)(nil), "mtrpb.DataCompletenessBatchRow")
+	proto.RegisterType((*DataCompletenessBatch)(nil), "mtrpb.DataCompletenessBatch")
 }
 
-var fileDescriptor1 = []byte{
-	// 553 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x8a, 0x13, 0x41,
-	0x10, 0xa5, 0x77, 0x2e, 0x49, 0x6a, 0xc5, 0x4b, 0xbb, 0xba, 0xb3, 0xf1, 0x82, 0xcc, 0x8b, 0x8b,
-	0x60, 0xc0, 0x5d, 0x10, 0x7c, 0xf0, 0x45, 0xe3, 0x83, 0xa0, 0x88, 0xb3, 0x01, 0x51, 0x10, 0xe9,
-	0xcd, 0xf4, 0x66, 0x07, 0xe6, 0xc6, 0x4c, 0x07, 0x99, 0x4f, 0xd0, 0xdf, 0xf1, 0x97, 0xfc, 0x10,
-	0xfb, 0x9a, 0xed, 0x74, 0x26, 0x20, 0x41, 0xdf, 0xa6, 0x4e, 0x55, 0x75, 0x9f, 0xaa, 0x53, 0xd5,
-	0x03, 0x90, 0x12, 0x46, 0x26, 0x75, 0x53, 0xb1, 0x0a, 0x07, 0x05, 0x6b, 0xea, 0xf3, 0xf8, 0x37,
-	0x02, 0x3c, 0xe5, 0xe8, 0x3b, 0xc2, 0x68, 0x39, 0xef, 0xce, 0x96, 0x45, 0x41, 0x9a, 0x0e, 0x1f,
-	0xc2, 0xa0, 0xcd, 0x18, 0xfd, 0x96, 0x4d, 0x23, 0xf4, 0x08, 0x1d, 0x8f, 0x92, 0x50, 0x98, 0x6f,
-	0xa7, 0xc2, 0xc1, 0xba, 0x5a, 0x3a, 0xf6, 0x94, 0x43, 0x98, 0xdc, 0x11, 0xf1, 0x0c, 0x3a, 0xaf,
-	0xca, 0xb4, 0x8d, 0x3c, 0xee, 0xf0, 0x12, 0x63, 0x62, 0x0c, 0x7e, 0x41, 0x49, 0x19, 0xf9, 0x1c,
-	0x0e, 0x12, 0xf9, 0x8d, 0x0f, 0x20, 0xb8, 0xc8, 0x2e, 0x58, 0x17, 0x05, 0x12, 0x54, 0x06, 0xbe,
-	0x0b, 0x61, 0x99, 0x95, 0x94, 0xc3, 0xa1, 0x84, 0xb5, 0x25, 0xa2, 0x97, 0x75, 0x4d, 0x9b, 0x68,
-	0xa0, 0xa2, 0xa5, 0x21, 0xd0, 0xbc, 0xfa, 0xce, 0xd1, 0xa1, 0x42, 0xa5, 0x21, 0xd0, 0x76, 0x4e,
-	0x72, 0x1a, 0x8d, 0x38, 0x8a, 0x12, 0x65, 0xc4, 0xef, 0x21, 0xda, 0xac, 0x32, 0xa1, 0xed, 0x32,
-	0x67, 0xf8, 0x19, 0x84, 0x8d, 0xfc, 0xe2, 0xa5, 0x7a, 0xc7, 0xfb, 0x27, 0x47, 0x13, 0xd9, 0x9a,
-	0x49, 0x4f, 0x82, 0x0e, 0x8c, 0xbf, 0xc2, 0x50, 0x78, 0xcf, 0x78, 0x4f, 0xb6, 0xb7, 0x6a, 0x0c,
-	0xc3, 0x9c, 0xb0, 0x8c, 0x2d, 0x53, 0x2a, 0x7b, 0x85, 0x92, 0x95, 0x8d, 0xef, 0xc3, 0x28, 0xaf,
-	0xca, 0x85, 0x72, 0x7a, 0xd2, 0x79, 0x05, 0xc4, 0x2f, 0xe0, 0xba, 0x39, 0x5e, 0x73, 0x7c, 0xec,
-	0x70, 0xbc, 0x61, 0x71, 0x94, 0x61, 0x86, 0xd9, 0x4c, 0xa5, 0x6a, 0xde, 0x33, 0xb2, 0xd8, 0x41,
-	0xca, 0x9b, 0xe0, 0x31, 0xb2, 0x90, 0xb4, 0x46, 0x89, 0xf8, 0x8c, 0xdf, 0xc0, 0xc1, 0xfa, 0xa9,
-	0x9a, 0xd6, 0x53, 0x87, 0xd6, 0x9d, 0xcd, 0xd6, 0x89, 0x60, 0x43, 0xee, 0x27, 0x5a, 0x3f, 0xe7,
-	0x92, 0xe3, 0x97, 0x55, 0x9e, 0xee, 0xc0, 0x71, 0x25, 0xbe, 0xe7, 0x88, 0xaf, 0x06, 0xc5, 0x77,
-	0x06, 0x45, 0x8d, 0x44, 0x60, 0x8f, 0xc4, 0x47, 0x18, 0xf7, 0x71, 0xd1, 0x95, 0x9d, 0x3a, 0x95,
-	0xdd, 0xeb, 0xa9, 0x6c, 0x95, 0x62, 0xea, 0x7b, 0xa9, 0xc6, 0x62, 0xc6, 0x29, 0xda, 0xcc, 0x91,
-	0xbb, 0x28, 0x69, 0xd6, 0xd6, 0x39, 0xe9, 0x74, 0x49, 0xc6, 0x34, 0xb2, 0x8b, 0xf4, 0xbf, 0x90,
-	0x5d, 0x86, 0x99, 0x9b, 0x33, 0xd8, 0xb7, 0x98, 0xd9, 0xcb, 0x88, 0xfa, 0x97, 0x51, 0x5c, 0xbd,
-	0xe7, 0x2e, 0xa3, 0xd7, 0xbf, 0x8c, 0xbe, 0xbd, 0x8c, 0xf1, 0x2f, 0x04, 0xb7, 0xac, 0xbb, 0x34,
-	0xd3, 0x9d, 0x14, 0x54, 0x5a, 0x79, 0xbd, 0x4b, 0xed, 0xdb, 0xba, 0x3e, 0x59, 0xf5, 0x21, 0x90,
-	0x7d, 0xc0, 0x9b, 0x6a, 0x98, 0x56, 0x5c, 0xa9, 0x1d, 0xda, 0x6a, 0xff, 0x40, 0x70, 0x28, 0xa2,
-	0x5f, 0x57, 0x45, 0x9d, 0x53, 0x9e, 0x41, 0xdb, 0xf6, 0x7f, 0x3c, 0x76, 0x31, 0x5c, 0x9b, 0x5b,
-	0x57, 0xc8, 0x32, 0xf6, 0x92, 0x35, 0x2c, 0xfe, 0x04, 0x0f, 0xb6, 0x50, 0xd1, 0xcd, 0x7c, 0xee,
-	0xc8, 0xfe, 0xd0, 0x2a, 0xb7, 0x2f, 0xcb, 0x4c, 0xc1, 0x67, 0xb8, 0xed, 0x86, 0xfc, 0xab, 0x17,
-	0xe0, 0x03, 0x1c, 0xf5, 0x1c, 0xad, 0xf9, 0x9e, 0x38, 0x7c, 0xc7, 0x5b, 0xf8, 0x5a, 0x6f, 0xc1,
-	0xab, 0xc1, 0x17, 0xf5, 0x07, 0x3a, 0x0f, 0xe5, 0xff, 0xe8, 0xf4, 0x4f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0xa6, 0x96, 0x8b, 0xc4, 0x9d, 0x06, 0x00, 0x00,
+var fileDescriptor2 = []byte{
+	// 1154 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0x4d, 0x6f, 0xdc, 0x44,
+	0x18, 0x96, 0xd7, 0xeb, 0x5d, 0xef, 0xbb, 0x9b, 0x66, 0xe3, 0xa4, 0x8d, 0x9b, 0x7e, 0x2d, 0xe6,
+	0xc0, 0x42, 0x21, 0xa8, 0xad, 0x54, 0x84, 0xb8, 0xd0, 0x12, 0x10, 0x41, 0x29, 0x48, 0x6e, 0x50,
+	0x05, 0x97, 0x68, 0xb2, 0x3b, 0xc9, 0x8e, 0xea, 0x1d, 0x5b, 0xf6, 0xb8, 0x9b, 0x95, 0x90, 0xb8,
+	0xf3, 0x1f, 0x38, 0x72, 0xe4, 0x9f, 0xf0, 0x1f, 0x10, 0x12, 0x7f, 0x81, 0x3b, 0x9a, 0x0f, 0x3b,
+	0xb3, 0x63, 0xbb, 0x8b, 0x22, 0x72, 0x9b, 0xf7, 0x63, 0xc6, 0xcf, 0xfb, 0xce, 0x33, 0xcf, 0x8c,
+	0x01, 0xa6, 0x88, 0xa1, 0xfd, 0x24, 0x8d, 0x59, 0xec, 0x39, 0x73, 0x96, 0x26, 0xa7, 0xc1, 0x5f,
+	0x6d, 0xf0, 0x0e, 0x10, 0x43, 0x47, 0x88, 0x61, 0x3a, 0x59, 0xbe, 0xcc, 0xe7, 0x73, 0x94, 0x2e,
+	0xbd, 0x5d, 0xe8, 0x66, 0x84, 0xe1, 0x13, 0x72, 0xe0, 0x5b, 0x23, 0x6b, 0xdc, 0x0b, 0x3b, 0xdc,
+	0x3c, 0x3c, 0xe0, 0x01, 0xb6, 0x4c, 0x44, 0xa0, 0x25, 0x03, 0xdc, 0x3c, 0x3c, 0xf0, 0x7c, 0xe8,
+	0x66, 0x78, 0x12, 0xd3, 0x69, 0xe6, 0xdb, 0x23, 0x6b, 0x6c, 0x87, 0x85, 0xe9, 0x79, 0xd0, 0x9e,
+	0x63, 0x44, 0xfd, 0xf6, 0xc8, 0x1a, 0x3b, 0xa1, 0x18, 0x7b, 0x3b, 0xe0, 0x9c, 0x91, 0x33, 0xb6,
+	0xf4, 0x1d, 0xe1, 0x94, 0x86, 0x77, 0x0b, 0x3a, 0x94, 0x50, 0xcc, 0x96, 0x7e, 0x47, 0xb8, 0x95,
+	0xc5, 0xb3, 0xf3, 0x24, 0xc1, 0xa9, 0xdf, 0x95, 0xd9, 0xc2, 0xe0, 0xde, 0x28, 0x5e, 0xe0, 0xd4,
+	0x77, 0xa5, 0x57, 0x18, 0xdc, 0x9b, 0x4d, 0x50, 0x84, 0xfd, 0xde, 0xc8, 0x1a, 0x5b, 0xa1, 0x34,
+	0x38, 0x86, 0x08, 0x31, 0xec, 0xc3, 0xc8, 0x1a, 0xbb, 0xa1, 0x18, 0x73, 0xc4, 0x39, 0x7d, 0x4d,
+	0xe3, 0x05, 0xf5, 0xfb, 0xc2, 0x5d, 0x98, 0xde, 0xbb, 0xb0, 0xb1, 0x40, 0x29, 0x25, 0xf4, 0xfc,
+	0x44, 0x7e, 0x77, 0x20, 0xbe, 0x30, 0x50, 0xce, 0xef, 0xc5, 0xe7, 0xb5, 0x24, 0x09, 0x63, 0x63,
+	0x25, 0xe9, 0x48, 0xa0, 0xb9, 0x07, 0x70, 0x16, 0xc5, 0x88, 0x9d, 0x88, 0x0e, 0xdc, 0x10, 0x90,
+	0x7a, 0xc2, 0xf3, 0x82, 0xb7, 0xe1, 0x01, 0xf4, 0x65, 0x58, 0x36, 0x63, 0x53, 0xc4, 0xe5, 0x8c,
+	0xaf, 0x44, 0x47, 0xde, 0x81, 0x81, 0x4c, 0x50, 0x7d, 0x19, 0x8a, 0x0c, 0x39, 0xe9, 0x5b, 0xd9,
+	0x9c, 0x72, 0x0d, 0x09, 0x75, 0x4b, 0x5b, 0x43, 0x02, 0x2d, 0x13, 0x24, 0x4c, 0x4f, 0x4b, 0x90,
+	0x20, 0xf7, 0x61, 0x5b, 0x26, 0xac, 0x16, 0xbd, 0x2d, 0x12, 0xb7, 0x44, 0xe8, 0x95, 0x5e, 0x79,
+	0x25, 0x5f, 0x2e, 0xbc, 0x53, 0xcd, 0x17, 0xeb, 0x07, 0x2f, 0xc0, 0xaf, 0x52, 0x2c, 0xc4, 0x59,
+	0x1e, 0x31, 0xef, 0x11, 0x74, 0x52, 0x31, 0xf2, 0xad, 0x91, 0x3d, 0xee, 0x3f, 0xbe, 0xbd, 0x2f,
+	0x78, 0xb9, 0x5f, 0x33, 0x41, 0x25, 0x06, 0xbf, 0x5b, 0xe0, 0xf2, 0xf0, 0x4b, 0xc2, 0x70, 0x33,
+	0x51, 0xf7, 0xc0, 0x8d, 0x10, 0x23, 0x2c, 0x9f, 0x62, 0xc1, 0x54, 0x2b, 0x2c, 0x6d, 0xef, 0x2e,
+	0xf4, 0xa2, 0x98, 0x9e, 0xcb, 0xa0, 0x2d, 0x37, 0xa5, 0x74, 0x70, 0x16, 0x66, 0x0c, 0xb1, 0x3c,
+	0xf3, 0xdb, 0x6a, 0x45, 0x61, 0x79, 0x4f, 0xa1, 0x87, 0x18, 0x4b, 0xc9, 0x69, 0xce, 0xb0, 0xef,
+	0x08, 0xb4, 0xbe, 0x86, 0x96, 0xc3, 0x79, 0x56, 0xc4, 0xc3, 0xcb, 0xd4, 0xe0, 0x33, 0xd8, 0xaa,
+	0xc4, 0xbd, 0x21, 0xd8, 0xaf, 0xf1, 0x52, 0x61, 0xe6, 0x43, 0x4e, 0xdc, 0x37, 0x28, 0xca, 0xb1,
+	0x3a, 0x57, 0xd2, 0x08, 0x3e, 0x85, 0x1b, 0xc5, 0x64, 0xd5, 0xb1, 0xf7, 0x8c, 0x8e, 0x6d, 0x1a,
+	0x18, 0xca, 0x3e, 0xfd, 0x0c, 0xc3, 0xc2, 0x77, 0x14, 0x4f, 0x10, 0x23, 0x31, 0x6d, 0x6e, 0x97,
+	0x76, 0x7c, 0x5b, 0xab, 0xc7, 0x57, 0x6f, 0xa4, 0xfd, 0xb6, 0x46, 0xb6, 0x8d, 0x46, 0x06, 0x87,
+	0x70, 0xcb, 0x04, 0xa0, 0x6a, 0xf8, 0xd8, 0xa8, 0x61, 0xd7, 0xa8, 0xa1, 0x4c, 0x2f, 0x6a, 0x39,
+	0x86, 0x1b, 0x1a, 0x23, 0x8e, 0xd1, 0xf9, 0x15, 0x14, 0x6a, 0x08, 0x36, 0x43, 0xe7, 0xa2, 0x86,
+	0x5e, 0xc8, 0x87, 0xc1, 0x97, 0xb0, 0xb3, 0xba, 0xaa, 0x82, 0xf7, 0x91, 0x01, 0xef, 0x66, 0x95,
+	0x94, 0x3c, 0xb9, 0x00, 0xf7, 0xa7, 0xbd, 0xba, 0xce, 0x2c, 0xc5, 0xd9, 0x2c, 0x8e, 0xa6, 0x57,
+	0xc0, 0x58, 0x6a, 0x9a, 0x6d, 0x68, 0x9a, 0x3c, 0x92, 0x6d, 0x43, 0xff, 0xa4, 0xd2, 0x39, 0xba,
+	0xd2, 0xdd, 0x85, 0x1e, 0xa1, 0x33, 0x9c, 0x12, 0x86, 0xa7, 0x42, 0x46, 0xdd, 0xf0, 0xd2, 0x51,
+	0x15, 0xad, 0x6e, 0x8d, 0x68, 0x55, 0xe4, 0xcf, 0xad, 0x91, 0xbf, 0xfb, 0x00, 0xb3, 0x65, 0xc6,
+	0x70, 0x8a, 0x33, 0x92, 0x09, 0xb1, 0x75, 0x42, 0xcd, 0x63, 0xaa, 0x0e, 0x54, 0x54, 0xc7, 0xd0,
+	0xad, 0x7e, 0x45, 0xb7, 0x1a, 0x64, 0x66, 0xd0, 0x20, 0x33, 0x4d, 0x32, 0xb6, 0xd1, 0x24, 0x63,
+	0xef, 0xc3, 0x50, 0xe6, 0x6b, 0x75, 0x48, 0x85, 0xde, 0x14, 0xfe, 0xaf, 0x4b, 0x77, 0xf0, 0x8b,
+	0x05, 0x7b, 0x75, 0x3b, 0xac, 0xf8, 0xf2, 0xc4, 0xe0, 0xcb, 0x9d, 0x1a, 0xbe, 0x94, 0x53, 0x54,
+	0xaa, 0xf7, 0x09, 0xb8, 0x0c, 0xcf, 0x13, 0x71, 0x2d, 0xb5, 0xd6, 0x4f, 0x2b, 0x93, 0x83, 0xdf,
+	0x94, 0xfe, 0x1d, 0x2f, 0x13, 0xac, 0x33, 0xc9, 0x32, 0xef, 0xe3, 0x29, 0xc9, 0x92, 0x08, 0x2d,
+	0x15, 0xc5, 0x0a, 0xd3, 0x1b, 0x41, 0x7f, 0x8a, 0xb3, 0x49, 0x4a, 0x12, 0x7e, 0xc4, 0xd4, 0x79,
+	0xd0, 0x5d, 0xfc, 0xb6, 0xcc, 0x29, 0x61, 0x4a, 0xff, 0xc4, 0xb8, 0x81, 0x6d, 0x7b, 0xe0, 0xe2,
+	0x8b, 0x04, 0x4f, 0x0a, 0xb2, 0x39, 0x61, 0x69, 0x17, 0xd2, 0xc5, 0x61, 0xfe, 0x07, 0xe9, 0x12,
+	0x69, 0xc5, 0x89, 0xfa, 0xc3, 0x82, 0xbe, 0xd6, 0x05, 0x5d, 0x9d, 0xac, 0xfa, 0xc7, 0x05, 0xaf,
+	0xb1, 0x65, 0x3e, 0x2e, 0xec, 0xfa, 0xc7, 0x45, 0x7b, 0xe5, 0x71, 0xb1, 0x7a, 0x45, 0x3b, 0x6b,
+	0xae, 0xe8, 0xce, 0xda, 0x2b, 0xba, 0x5b, 0xb9, 0xa2, 0x83, 0x7f, 0x2c, 0xd8, 0xd2, 0xca, 0x51,
+	0xdd, 0xb8, 0x92, 0x3a, 0x48, 0x4e, 0xdb, 0xb5, 0xef, 0xa0, 0xb6, 0xae, 0x19, 0x1f, 0x94, 0xbd,
+	0x96, 0x57, 0x95, 0x57, 0x25, 0x57, 0x49, 0xc5, 0x72, 0x6f, 0x3b, 0xfa, 0xde, 0x1a, 0x07, 0xb4,
+	0xbb, 0xee, 0x61, 0xe1, 0x9a, 0x47, 0x3c, 0xf8, 0xd5, 0x82, 0x5d, 0xfe, 0xbd, 0x2f, 0xe2, 0x79,
+	0x12, 0x61, 0x86, 0x29, 0xce, 0xb2, 0xeb, 0x78, 0x61, 0x06, 0x30, 0x98, 0x68, 0x9f, 0x10, 0x8d,
+	0x68, 0x85, 0x2b, 0xbe, 0xf2, 0x05, 0xe8, 0x5c, 0xbe, 0x00, 0x83, 0x57, 0x70, 0xaf, 0x01, 0x9e,
+	0xda, 0xa2, 0xa7, 0x06, 0x61, 0xef, 0x6b, 0x4d, 0xac, 0x9b, 0x55, 0xf0, 0xf7, 0x07, 0xd8, 0x36,
+	0x53, 0xfe, 0xaf, 0x3b, 0xeb, 0x3b, 0xb8, 0x5d, 0xb3, 0xb4, 0xc2, 0xfb, 0xd8, 0xc0, 0xbb, 0xd7,
+	0x80, 0x57, 0xbf, 0xbd, 0xe6, 0x30, 0xe0, 0xe1, 0x43, 0xca, 0x70, 0xfa, 0x06, 0x45, 0x57, 0x00,
+	0xf9, 0x10, 0xb6, 0x8a, 0x43, 0x7f, 0x42, 0xd4, 0x32, 0x8a, 0xa2, 0xc3, 0x22, 0x50, 0x2c, 0x1f,
+	0x3c, 0x93, 0xff, 0x1b, 0x85, 0xad, 0x80, 0x3f, 0x34, 0x80, 0x6f, 0x6b, 0xc0, 0xcb, 0xd4, 0x02,
+	0xf1, 0xdf, 0x2d, 0xd8, 0xd6, 0x68, 0xfc, 0x1c, 0xb1, 0xc9, 0x2c, 0x8c, 0x17, 0xd7, 0xfe, 0xd3,
+	0x32, 0x04, 0x7b, 0x4e, 0xa8, 0xfa, 0x65, 0xe1, 0x43, 0xe1, 0x41, 0x17, 0x4a, 0xf9, 0xf8, 0xf0,
+	0x52, 0x7b, 0xba, 0xf5, 0xda, 0xe3, 0xbe, 0x45, 0x7b, 0x7a, 0xa6, 0xf6, 0xdc, 0x81, 0x9e, 0x0a,
+	0x13, 0xaa, 0x6e, 0x50, 0x57, 0x46, 0x89, 0x1e, 0x44, 0x17, 0x7e, 0x5f, 0x0f, 0xa2, 0x0b, 0x53,
+	0xb5, 0x06, 0x6b, 0x55, 0x6b, 0xa3, 0xaa, 0x5a, 0x9f, 0xc3, 0xd0, 0xec, 0xb2, 0xf7, 0x21, 0xd8,
+	0x69, 0xbc, 0xa8, 0x61, 0x97, 0xb1, 0x17, 0x21, 0x4f, 0x0b, 0x7e, 0x02, 0xdf, 0x64, 0xde, 0xb5,
+	0x6c, 0xd6, 0x0e, 0x38, 0x93, 0x38, 0xa7, 0xac, 0x50, 0x40, 0x61, 0x04, 0xdf, 0xc0, 0xcd, 0xda,
+	0xaf, 0x7b, 0x8f, 0xf4, 0x22, 0x1e, 0x34, 0x1c, 0x91, 0x95, 0x4a, 0x9e, 0x77, 0x7f, 0x94, 0xff,
+	0xcb, 0xa7, 0x1d, 0xf1, 0xf7, 0xfc, 0xe4, 0xdf, 0x01, 0x00, 0x9f, 0x46, 0x08, 0x9a, 0x4b, 0x0f,
+	0x00, 0x00,
 }
@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go.
+// source: detail.proto
+// DO NOT EDIT!
+
+package mtrpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// FieldDeviceDetail is everything about one field device.
+type FieldDeviceDetail struct {
+	// The device with its model and location.
+	Device *FieldDevice `protobuf:"bytes,1,opt,name=device" json:"device,omitempty"`
+	// The latest value for each metric.  Metrics without a threshold have lower and upper 0.
+	Metric    []*FieldMetricSummary `protobuf:"bytes,2,rep,name=metric" json:"metric,omitempty"`
+	State     []*FieldState         `protobuf:"bytes,3,rep,name=state" json:"state,omitempty"`
+	MetricTag []*FieldMetricTag     `protobuf:"bytes,4,rep,name=metric_tag,json=metricTag" json:"metric_tag,omitempty"`
+	StateTag  []*FieldStateTag      `protobuf:"bytes,5,rep,name=state_tag,json=stateTag" json:"state_tag,omitempty"`
+	// Alerts that are open or were closed in the last week.
+	Alert []*Alert `protobuf:"bytes,6,rep,name=alert" json:"alert,omitempty"`
+}
+
+func (m *FieldDeviceDetail) Reset()                    { *m = FieldDeviceDetail{} }
+func (m *FieldDeviceDetail) String() string            { return proto.CompactTextString(m) }
+func (*FieldDeviceDetail) ProtoMessage()               {}
+func (*FieldDeviceDetail) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
+func (m *FieldDeviceDetail) GetDevice() *FieldDevice {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *FieldDeviceDetail) GetMetric() []*FieldMetricSummary {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
+func (m *FieldDeviceDetail) GetState() []*FieldState {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *FieldDeviceDetail) GetMetricTag() []*FieldMetricTag {
+	if m != nil {
+		return m.MetricTag
+	}
+	return nil
+}
+
+func (m *FieldDeviceDetail) GetStateTag() []*FieldStateTag {
+	if m != nil {
+		return m.StateTag
+	}
+	return nil
+}
+
+func (m *FieldDeviceDetail) GetAlert() []*Alert {
+	if m != nil {
+		return m.Alert
+	}
+	return nil
+}
+
+// DataSiteDetail is everything about one data site.
+type DataSiteDetail struct {
+	// The site with its location.
+	Site *DataSite `protobuf:"bytes,1,opt,name=site" json:"site,omitempty"`
+	// The latest latency for each type.  Latencies without a threshold have lower and upper 0.
+	Latency         []*DataLatencySummary      `protobuf:"bytes,2,rep,name=latency" json:"latency,omitempty"`
+	Completeness    []*DataCompletenessSummary `protobuf:"bytes,3,rep,name=completeness" json:"completeness,omitempty"`
+	LatencyTag      []*DataLatencyTag          `protobuf:"bytes,4,rep,name=latency_tag,json=latencyTag" json:"latency_tag,omitempty"`
+	CompletenessTag []*DataCompletenessTag     `protobuf:"bytes,5,rep,name=completeness_tag,json=completenessTag" json:"completeness_tag,omitempty"`
+	// Alerts that are open or were closed in the last week.
+	Alert []*Alert `protobuf:"bytes,6,rep,name=alert" json:"alert,omitempty"`
+}
+
+func (m *DataSiteDetail) Reset()                    { *m = DataSiteDetail{} }
+func (m *DataSiteDetail) String() string            { return proto.CompactTextString(m) }
+func (*DataSiteDetail) ProtoMessage()               {}
+func (*DataSiteDetail) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *DataSiteDetail) GetSite() *DataSite {
+	if m != nil {
+		return m.Site
+	}
+	return nil
+}
+
+func (m *DataSiteDetail) GetLatency() []*DataLatencySummary {
+	if m != nil {
+		return m.Latency
+	}
+	return nil
+}
+
+func (m *DataSiteDetail) GetCompleteness() []*DataCompletenessSummary {
+	if m != nil {
+		return m.Completeness
+	}
+	return nil
+}
+
+func (m *DataSiteDetail) GetLatencyTag() []*DataLatencyTag {
+	if m != nil {
+		return m.LatencyTag
+	}
+	return nil
+}
+
+func (m *DataSiteDetail) GetCompletenessTag() []*DataCompletenessTag {
+	if m != nil {
+		return m.CompletenessTag
+	}
+	return nil
+}
+
+func (m *DataSiteDetail) GetAlert() []*Alert {
+	if m != nil {
+		return m.Alert
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*FieldDeviceDetail)(nil), "mtrpb.FieldDeviceDetail")
+	proto.RegisterType((*DataSiteDetail)(nil), "mtrpb.DataSiteDetail")
+}
+
+var fileDescriptor3 = []byte{
+	// 339 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x4f, 0xc2, 0x40,
+	0x10, 0xc5, 0xc3, 0x9f, 0x16, 0x99, 0x12, 0x91, 0x89, 0x26, 0x95, 0x83, 0x21, 0x78, 0x90, 0x78,
+	0x20, 0x01, 0x8c, 0x77, 0x11, 0x3d, 0xe9, 0xa5, 0x78, 0xf2, 0x62, 0x96, 0x76, 0x25, 0x4d, 0x5a,
+	0x20, 0xed, 0x68, 0xc2, 0x37, 0xf2, 0xeb, 0xf8, 0x8d, 0xcc, 0xce, 0xee, 0x9a, 0x45, 0x34, 0xf1,
+	0x36, 0x3b, 0xef, 0xfd, 0xde, 0x24, 0x2f, 0x0b, 0xad, 0x44, 0x92, 0x48, 0xb3, 0xe1, 0xa6, 0x58,
+	0xd3, 0x1a, 0xbd, 0x9c, 0x8a, 0xcd, 0xa2, 0x1b, 0x88, 0x4c, 0x16, 0xa4, 0x77, 0x5d, 0x48, 0x04,
+	0x09, 0x33, 0x07, 0xaf, 0xa9, 0xcc, 0x12, 0xfd, 0xe8, 0x7f, 0x54, 0xa1, 0x73, 0xaf, 0xde, 0x33,
+	0xf9, 0x9e, 0xc6, 0x72, 0xc6, 0x41, 0x78, 0x09, 0x7e, 0xc2, 0xef, 0xb0, 0xd2, 0xab, 0x0c, 0x82,
+	0x31, 0x0e, 0x39, 0x73, 0xe8, 0x38, 0x23, 0xe3, 0xc0, 0x11, 0xf8, 0xb9, 0xa4, 0x22, 0x8d, 0xc3,
+	0x6a, 0xaf, 0x36, 0x08, 0xc6, 0xa7, 0xae, 0xf7, 0x91, 0x95, 0xf9, 0x5b, 0x9e, 0x8b, 0x62, 0x1b,
+	0x19, 0x23, 0x5e, 0x80, 0x57, 0x92, 0x20, 0x19, 0xd6, 0x98, 0xe8, 0xb8, 0xc4, 0x5c, 0x09, 0x91,
+	0xd6, 0xf1, 0x0a, 0x40, 0x23, 0x2f, 0x24, 0x96, 0x61, 0x9d, 0xdd, 0x27, 0xfb, 0xf9, 0x4f, 0x62,
+	0x19, 0x35, 0x73, 0x3b, 0xe2, 0x08, 0x9a, 0x8c, 0x33, 0xe4, 0x31, 0x74, 0xbc, 0x77, 0x42, 0x31,
+	0x07, 0xa5, 0x99, 0xb0, 0x0f, 0x1e, 0xd7, 0x15, 0xfa, 0x6c, 0x6f, 0x19, 0xfb, 0x8d, 0xda, 0x45,
+	0x5a, 0xea, 0x7f, 0x56, 0xe1, 0x70, 0x26, 0x48, 0xcc, 0x53, 0xb2, 0x3d, 0x9d, 0x43, 0xbd, 0x4c,
+	0xc9, 0xb6, 0xd4, 0x36, 0x94, 0x35, 0x45, 0x2c, 0xe2, 0x04, 0x1a, 0x99, 0x20, 0xb9, 0x8a, 0xb7,
+	0x3f, 0x1a, 0x52, 0xbe, 0x07, 0xad, 0xd8, 0x86, 0xac, 0x13, 0xa7, 0xd0, 0x8a, 0xd7, 0xf9, 0x26,
+	0x93, 0x24, 0x57, 0xb2, 0x2c, 0x4d, 0x53, 0x67, 0x0e, 0x79, 0xeb, 0xc8, 0x16, 0xdf, 0x61, 0xf0,
+	0x1a, 0x02, 0x13, 0xf7, 0x4b, 0x7d, 0xce, 0x71, 0x55, 0x05, 0x64, 0xdf, 0x33, 0xde, 0xc1, 0x91,
+	0x9b, 0xe3, 0xd4, 0xd8, 0xfd, 0xe3, 0xbe, 0x4a, 0x68, 0xc7, 0xbb, 0x8b, 0xff, 0x74, 0x3a, 0x6d,
+	0x3c, 0xeb, 0xdf, 0xba, 0xf0, 0xf9, 0x3b, 0x4e, 0xbe, 0x06, 0x00, 0x0c, 0xd3, 0x33, 0x9d, 0xcb,
+	0x02, 0x00, 0x00,
+}
@@ -32,12 +32,28 @@ type FieldMetricSummary struct {
 	ModelID string `protobuf:"bytes,7,opt,name=model_iD,json=modelID" json:"model_iD,omitempty"`
 	// the scale factor to apply to the threshold values
 	Scale float64 `protobuf:"fixed64,8,opt,name=scale" json:"scale,omitempty"`
+	// true if there has been no value for longer than the expected reporting interval for the metric.
+	Late bool `protobuf:"varint,9,opt,name=late" json:"late,omitempty"`
+	// true if the metric has no threshold.  Upper and lower are 0.
+	Unknown bool `protobuf:"varint,10,opt,name=unknown" json:"unknown,omitempty"`
+	// The upper threshold for the metric to be good.  Values above it and below upper are warning.
+	// warning_upper and warning_lower are 0 if there is no warning threshold.
+	WarningUpper int32 `protobuf:"varint,11,opt,name=warning_upper,json=warningUpper" json:"warning_upper,omitempty"`
+	// The lower threshold for the metric to be good.  Values below it and above lower are warning.
+	WarningLower int32 `protobuf:"varint,12,opt,name=warning_lower,json=warningLower" json:"warning_lower,omitempty"`
+	// The values above as floats.  The integer values above are rounded from them and are kept
+	// for existing clients.
+	FloatValue        float64 `protobuf:"fixed64,13,opt,name=float_value,json=floatValue" json:"float_value,omitempty"`
+	FloatUpper        float64 `protobuf:"fixed64,14,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatLower        float64 `protobuf:"fixed64,15,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
+	FloatWarningUpper float64 `protobuf:"fixed64,16,opt,name=float_warning_upper,json=floatWarningUpper" json:"float_warning_upper,omitempty"`
+	FloatWarningLower float64 `protobuf:"fixed64,17,opt,name=float_warning_lower,json=floatWarningLower" json:"float_warning_lower,omitempty"`
 }
 
 func (m *FieldMetricSummary) Reset()                    { *m = FieldMetricSummary{} }
 func (m *FieldMetricSummary) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricSummary) ProtoMessage()               {}
-func (*FieldMetricSummary) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+func (*FieldMetricSummary) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0} }
 
 type FieldMetricSummaryResult struct {
 	Result []*FieldMetricSummary `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -46,7 +62,7 @@ type FieldMetricSummaryResult struct {
 func (m *FieldMetricSummaryResult) Reset()                    { *m = FieldMetricSummaryResult{} }
 func (m *FieldMetricSummaryResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricSummaryResult) ProtoMessage()               {}
-func (*FieldMetricSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+func (*FieldMetricSummaryResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{1} }
 
 func (m *FieldMetricSummaryResult) GetResult() []*FieldMetricSummary {
 	if m != nil {
@@ -67,7 +83,7 @@ type FieldMetricTag struct {
 func (m *FieldMetricTag) Reset()                    { *m = FieldMetricTag{} }
 func (m *FieldMetricTag) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricTag) ProtoMessage()               {}
-func (*FieldMetricTag) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+func (*FieldMetricTag) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
 
 type FieldMetricTagResult struct {
 	Result []*FieldMetricTag `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -76,7 +92,7 @@ type FieldMetricTagResult struct {
 func (m *FieldMetricTagResult) Reset()                    { *m = FieldMetricTagResult{} }
 func (m *FieldMetricTagResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricTagResult) ProtoMessage()               {}
-func (*FieldMetricTagResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+func (*FieldMetricTagResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{3} }
 
 func (m *FieldMetricTagResult) GetResult() []*FieldMetricTag {
 	if m != nil {
@@ -96,21 +112,44 @@ type FieldMetricThreshold struct {
 	Upper int32 `protobuf:"varint,4,opt,name=upper" json:"upper,omitempty"`
 	// The scale to multiply the thresholds by
 	Scale float64 `protobuf:"fixed64,5,opt,name=scale" json:"scale,omitempty"`
+	// The modelID for the device or, for a template, the model it applies to e.g., Trimble NetR9
+	ModelID string `protobuf:"bytes,6,opt,name=model_iD,json=modelID" json:"model_iD,omitempty"`
+	// true if the threshold is inherited from the template for the model and type.
+	// false if it is set (overridden) for the device.
+	Inherited bool `protobuf:"varint,7,opt,name=inherited" json:"inherited,omitempty"`
+	// The lower threshold for the metric to be good.  Values between lower and warning_lower are warning.
+	// warning_lower and warning_upper are 0 if there is no warning threshold.
+	WarningLower int32 `protobuf:"varint,8,opt,name=warning_lower,json=warningLower" json:"warning_lower,omitempty"`
+	// The upper threshold for the metric to be good.  Values between warning_upper and upper are warning.
+	WarningUpper int32 `protobuf:"varint,9,opt,name=warning_upper,json=warningUpper" json:"warning_upper,omitempty"`
+	// An alert opened for a value outside lower and upper is not closed until the value is
+	// back inside them by at least hysteresis.
+	Hysteresis int32 `protobuf:"varint,10,opt,name=hysteresis" json:"hysteresis,omitempty"`
+	// The thresholds above as floats.  The integer thresholds above are rounded from them and are
+	// kept for existing clients.
+	FloatLower        float64 `protobuf:"fixed64,11,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
+	FloatUpper        float64 `protobuf:"fixed64,12,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatWarningLower float64 `protobuf:"fixed64,13,opt,name=float_warning_lower,json=floatWarningLower" json:"float_warning_lower,omitempty"`
+	FloatWarningUpper float64 `protobuf:"fixed64,14,opt,name=float_warning_upper,json=floatWarningUpper" json:"float_warning_upper,omitempty"`
+	FloatHysteresis   float64 `protobuf:"fixed64,15,opt,name=float_hysteresis,json=floatHysteresis" json:"float_hysteresis,omitempty"`
 }
 
 func (m *FieldMetricThreshold) Reset()                    { *m = FieldMetricThreshold{} }
 func (m *FieldMetricThreshold) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricThreshold) ProtoMessage()               {}
-func (*FieldMetricThreshold) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+func (*FieldMetricThreshold) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{4} }
 
 type FieldMetricThresholdResult struct {
+	// The thresholds in effect for each device.
 	Result []*FieldMetricThreshold `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+	// The templates for each model and type.  device_iD is empty.
+	Template []*FieldMetricThreshold `protobuf:"bytes,2,rep,name=template" json:"template,omitempty"`
 }
 
 func (m *FieldMetricThresholdResult) Reset()                    { *m = FieldMetricThresholdResult{} }
 func (m *FieldMetricThresholdResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricThresholdResult) ProtoMessage()               {}
-func (*FieldMetricThresholdResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
+func (*FieldMetricThresholdResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{5} }
 
 func (m *FieldMetricThresholdResult) GetResult() []*FieldMetricThreshold {
 	if m != nil {
@@ -119,6 +158,46 @@ func (m *FieldMetricThresholdResult) GetResult() []*FieldMetricThreshold {
 	return nil
 }
 
+func (m *FieldMetricThresholdResult) GetTemplate() []*FieldMetricThreshold {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// FieldMetricInterval is the expected reporting interval for a field metric.
+// If device_iD is empty it is the interval for all metrics of type_iD
+// otherwise it overrides the interval for the device.
+type FieldMetricInterval struct {
+	// The deviceID for the metric e.g., idu-birchfarm
+	DeviceID string `protobuf:"bytes,1,opt,name=device_iD,json=deviceID" json:"device_iD,omitempty"`
+	// The typeID for the metric e.g., conn
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// The expected reporting interval in seconds.  0 means the metric is never late.
+	ExpectedInterval int32 `protobuf:"varint,3,opt,name=expected_interval,json=expectedInterval" json:"expected_interval,omitempty"`
+}
+
+func (m *FieldMetricInterval) Reset()                    { *m = FieldMetricInterval{} }
+func (m *FieldMetricInterval) String() string            { return proto.CompactTextString(m) }
+func (*FieldMetricInterval) ProtoMessage()               {}
+func (*FieldMetricInterval) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{6} }
+
+type FieldMetricIntervalResult struct {
+	Result []*FieldMetricInterval `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *FieldMetricIntervalResult) Reset()                    { *m = FieldMetricIntervalResult{} }
+func (m *FieldMetricIntervalResult) String() string            { return proto.CompactTextString(m) }
+func (*FieldMetricIntervalResult) ProtoMessage()               {}
+func (*FieldMetricIntervalResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{7} }
+
+func (m *FieldMetricIntervalResult) GetResult() []*FieldMetricInterval {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 type FieldModel struct {
 	// the modelID for the field threshold
 	ModelID string `protobuf:"bytes,1,opt,name=model_iD,json=modelID" json:"model_iD,omitempty"`
@@ -127,7 +206,7 @@ type FieldModel struct {
 func (m *FieldModel) Reset()                    { *m = FieldModel{} }
 func (m *FieldModel) String() string            { return proto.CompactTextString(m) }
 func (*FieldModel) ProtoMessage()               {}
-func (*FieldModel) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{6} }
+func (*FieldModel) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{8} }
 
 type FieldModelResult struct {
 	Result []*FieldModel `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -136,7 +215,7 @@ type FieldModelResult struct {
 func (m *FieldModelResult) Reset()                    { *m = FieldModelResult{} }
 func (m *FieldModelResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldModelResult) ProtoMessage()               {}
-func (*FieldModelResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{7} }
+func (*FieldModelResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{9} }
 
 func (m *FieldModelResult) GetResult() []*FieldModel {
 	if m != nil {
@@ -153,12 +232,34 @@ type FieldDevice struct {
 	// Decimal Latitude and Longitude, only uses three digits of precision after decimal
 	Latitude  float32 `protobuf:"fixed32,3,opt,name=latitude" json:"latitude,omitempty"`
 	Longitude float32 `protobuf:"fixed32,4,opt,name=longitude" json:"longitude,omitempty"`
+	// The lifecycle status e.g., planned, active, maintenance, or retired
+	Status string `protobuf:"bytes,5,opt,name=status" json:"status,omitempty"`
+	// Key value attributes for the device e.g., owner
+	Attribute []*FieldDeviceAttribute `protobuf:"bytes,6,rep,name=attribute" json:"attribute,omitempty"`
 }
 
 func (m *FieldDevice) Reset()                    { *m = FieldDevice{} }
 func (m *FieldDevice) String() string            { return proto.CompactTextString(m) }
 func (*FieldDevice) ProtoMessage()               {}
-func (*FieldDevice) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{8} }
+func (*FieldDevice) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{10} }
+
+func (m *FieldDevice) GetAttribute() []*FieldDeviceAttribute {
+	if m != nil {
+		return m.Attribute
+	}
+	return nil
+}
+
+type FieldDeviceAttribute struct {
+	// The attribute key e.g., owner
+	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *FieldDeviceAttribute) Reset()                    { *m = FieldDeviceAttribute{} }
+func (m *FieldDeviceAttribute) String() string            { return proto.CompactTextString(m) }
+func (*FieldDeviceAttribute) ProtoMessage()               {}
+func (*FieldDeviceAttribute) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{11} }
 
 type FieldDeviceResult struct {
 	Result []*FieldDevice `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -167,7 +268,7 @@ type FieldDeviceResult struct {
 func (m *FieldDeviceResult) Reset()                    { *m = FieldDeviceResult{} }
 func (m *FieldDeviceResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldDeviceResult) ProtoMessage()               {}
-func (*FieldDeviceResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{9} }
+func (*FieldDeviceResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{12} }
 
 func (m *FieldDeviceResult) GetResult() []*FieldDevice {
 	if m != nil {
@@ -176,17 +277,53 @@ func (m *FieldDeviceResult) GetResult() []*FieldDevice {
 	return nil
 }
 
+// FieldDeviceLocation is a location for a device in effect from seconds until the next location.
+type FieldDeviceLocation struct {
+	// The deviceID e.g., idu-birchfarm
+	DeviceID string `protobuf:"bytes,1,opt,name=device_iD,json=deviceID" json:"device_iD,omitempty"`
+	// Unix time in seconds the location is in effect from
+	Seconds   int64   `protobuf:"varint,2,opt,name=seconds" json:"seconds,omitempty"`
+	Latitude  float64 `protobuf:"fixed64,3,opt,name=latitude" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,4,opt,name=longitude" json:"longitude,omitempty"`
+}
+
+func (m *FieldDeviceLocation) Reset()                    { *m = FieldDeviceLocation{} }
+func (m *FieldDeviceLocation) String() string            { return proto.CompactTextString(m) }
+func (*FieldDeviceLocation) ProtoMessage()               {}
+func (*FieldDeviceLocation) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{13} }
+
+type FieldDeviceLocationResult struct {
+	Result []*FieldDeviceLocation `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *FieldDeviceLocationResult) Reset()                    { *m = FieldDeviceLocationResult{} }
+func (m *FieldDeviceLocationResult) String() string            { return proto.CompactTextString(m) }
+func (*FieldDeviceLocationResult) ProtoMessage()               {}
+func (*FieldDeviceLocationResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{14} }
+
+func (m *FieldDeviceLocationResult) GetResult() []*FieldDeviceLocation {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 type FieldType struct {
 	// The TypeID in the table field.type
 	TypeID string `protobuf:"bytes,1,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
 	// display in the table field.type, the units to display when plotting
-	Display string `protobuf:"bytes,2,opt,name=display" json:"display,omitempty"`
+	Display     string `protobuf:"bytes,2,opt,name=display" json:"display,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	// the unit values are stored in
+	Unit string `protobuf:"bytes,4,opt,name=unit" json:"unit,omitempty"`
+	// values are multiplied by scale for display
+	Scale float64 `protobuf:"fixed64,5,opt,name=scale" json:"scale,omitempty"`
 }
 
 func (m *FieldType) Reset()                    { *m = FieldType{} }
 func (m *FieldType) String() string            { return proto.CompactTextString(m) }
 func (*FieldType) ProtoMessage()               {}
-func (*FieldType) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{10} }
+func (*FieldType) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{15} }
 
 type FieldTypeResult struct {
 	Result []*FieldType `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -195,7 +332,7 @@ type FieldTypeResult struct {
 func (m *FieldTypeResult) Reset()                    { *m = FieldTypeResult{} }
 func (m *FieldTypeResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldTypeResult) ProtoMessage()               {}
-func (*FieldTypeResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{11} }
+func (*FieldTypeResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{16} }
 
 func (m *FieldTypeResult) GetResult() []*FieldType {
 	if m != nil {
@@ -218,7 +355,7 @@ type FieldState struct {
 func (m *FieldState) Reset()                    { *m = FieldState{} }
 func (m *FieldState) String() string            { return proto.CompactTextString(m) }
 func (*FieldState) ProtoMessage()               {}
-func (*FieldState) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{12} }
+func (*FieldState) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{17} }
 
 type FieldStateResult struct {
 	Result []*FieldState `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -227,7 +364,7 @@ type FieldStateResult struct {
 func (m *FieldStateResult) Reset()                    { *m = FieldStateResult{} }
 func (m *FieldStateResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldStateResult) ProtoMessage()               {}
-func (*FieldStateResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{13} }
+func (*FieldStateResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{18} }
 
 func (m *FieldStateResult) GetResult() []*FieldState {
 	if m != nil {
@@ -248,7 +385,7 @@ type FieldStateTag struct {
 func (m *FieldStateTag) Reset()                    { *m = FieldStateTag{} }
 func (m *FieldStateTag) String() string            { return proto.CompactTextString(m) }
 func (*FieldStateTag) ProtoMessage()               {}
-func (*FieldStateTag) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{14} }
+func (*FieldStateTag) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{19} }
 
 type FieldStateTagResult struct {
 	Result []*FieldStateTag `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -257,7 +394,7 @@ type FieldStateTagResult struct {
 func (m *FieldStateTagResult) Reset()                    { *m = FieldStateTagResult{} }
 func (m *FieldStateTagResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldStateTagResult) ProtoMessage()               {}
-func (*FieldStateTagResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{15} }
+func (*FieldStateTagResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{20} }
 
 func (m *FieldStateTagResult) GetResult() []*FieldStateTag {
 	if m != nil {
@@ -271,12 +408,14 @@ type FieldMetric struct {
 	Seconds int64 `protobuf:"varint,1,opt,name=seconds" json:"seconds,omitempty"`
 	// The value
 	Value float32 `protobuf:"fixed32,2,opt,name=value" json:"value,omitempty"`
+	// The value as a double.  value is kept for existing clients.
+	FloatValue float64 `protobuf:"fixed64,3,opt,name=float_value,json=floatValue" json:"float_value,omitempty"`
 }
 
 func (m *FieldMetric) Reset()                    { *m = FieldMetric{} }
 func (m *FieldMetric) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetric) ProtoMessage()               {}
-func (*FieldMetric) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{16} }
+func (*FieldMetric) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{21} }
 
 type FieldMetricResult struct {
 	// The deviceID for the metric e.g., idu-birchfarm
@@ -294,12 +433,17 @@ type FieldMetricResult struct {
 	Result []*FieldMetric `protobuf:"bytes,7,rep,name=result" json:"result,omitempty"`
 	// the scale factor to multiply the threshold values by
 	Scale float64 `protobuf:"fixed64,8,opt,name=scale" json:"scale,omitempty"`
+	// The values above as floats.  The integer values above are rounded from them and are kept
+	// for existing clients.
+	FloatValue float64 `protobuf:"fixed64,9,opt,name=float_value,json=floatValue" json:"float_value,omitempty"`
+	FloatUpper float64 `protobuf:"fixed64,10,opt,name=float_upper,json=floatUpper" json:"float_upper,omitempty"`
+	FloatLower float64 `protobuf:"fixed64,11,opt,name=float_lower,json=floatLower" json:"float_lower,omitempty"`
 }
 
 func (m *FieldMetricResult) Reset()                    { *m = FieldMetricResult{} }
 func (m *FieldMetricResult) String() string            { return proto.CompactTextString(m) }
 func (*FieldMetricResult) ProtoMessage()               {}
-func (*FieldMetricResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{17} }
+func (*FieldMetricResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{22} }
 
 func (m *FieldMetricResult) GetResult() []*FieldMetric {
 	if m != nil {
@@ -308,6 +452,73 @@ func (m *FieldMetricResult) GetResult() []*FieldMetric {
 	return nil
 }
 
+// FieldMetricBatchRow is one field metric value in a batch upload.
+type FieldMetricBatchRow struct {
+	// The deviceID for the metric e.g., idu-birchfarm
+	DeviceID string `protobuf:"bytes,1,opt,name=device_iD,json=deviceID" json:"device_iD,omitempty"`
+	// The typeID for the metric e.g., conn
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Unix time in seconds for the metric value (don't need nanos).
+	Seconds int64 `protobuf:"varint,3,opt,name=seconds" json:"seconds,omitempty"`
+	// The value in integer units, kept for senders that scale their values with field.type scale.
+	Value int32 `protobuf:"varint,4,opt,name=value" json:"value,omitempty"`
+	// The value.  If it is not 0 it is used instead of value.
+	FloatValue float64 `protobuf:"fixed64,5,opt,name=float_value,json=floatValue" json:"float_value,omitempty"`
+}
+
+func (m *FieldMetricBatchRow) Reset()                    { *m = FieldMetricBatchRow{} }
+func (m *FieldMetricBatchRow) String() string            { return proto.CompactTextString(m) }
+func (*FieldMetricBatchRow) ProtoMessage()               {}
+func (*FieldMetricBatchRow) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{23} }
+
+type FieldMetricBatch struct {
+	Row []*FieldMetricBatchRow `protobuf:"bytes,1,rep,name=row" json:"row,omitempty"`
+}
+
+func (m *FieldMetricBatch) Reset()                    { *m = FieldMetricBatch{} }
+func (m *FieldMetricBatch) String() string            { return proto.CompactTextString(m) }
+func (*FieldMetricBatch) ProtoMessage()               {}
+func (*FieldMetricBatch) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{24} }
+
+func (m *FieldMetricBatch) GetRow() []*FieldMetricBatchRow {
+	if m != nil {
+		return m.Row
+	}
+	return nil
+}
+
+// BatchRowResult is the outcome of saving one row from a batch upload.
+type BatchRowResult struct {
+	// The index of the row in the batch (zero based).
+	Row int32 `protobuf:"varint,1,opt,name=row" json:"row,omitempty"`
+	// The http status code for the row e.g., 200 saved, 429 already data for the minute,
+	// 400 unknown device or type.
+	Code int32 `protobuf:"varint,2,opt,name=code" json:"code,omitempty"`
+	// Any error message for the row.
+	Msg string `protobuf:"bytes,3,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (m *BatchRowResult) Reset()                    { *m = BatchRowResult{} }
+func (m *BatchRowResult) String() string            { return proto.CompactTextString(m) }
+func (*BatchRowResult) ProtoMessage()               {}
+func (*BatchRowResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{25} }
+
+type BatchResult struct {
+	Result []*BatchRowResult `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *BatchResult) Reset()                    { *m = BatchResult{} }
+func (m *BatchResult) String() string            { return proto.CompactTextString(m) }
+func (*BatchResult) ProtoMessage()               {}
+func (*BatchResult) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{26} }
+
+func (m *BatchResult) GetResult() []*BatchRowResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*FieldMetricSummary)(nil), "mtrpb.FieldMetricSummary")
 	proto.RegisterType((*FieldMetricSummaryResult)(nil), "mtrpb.FieldMetricSummaryResult")
@@ -315,10 +526,15 @@ func init() {
 	proto.RegisterType((*FieldMetricTagResult)(nil), "mtrpb.FieldMetricTagResult")
 	proto.RegisterType((*FieldMetricThreshold)(nil), "mtrpb.FieldMetricThreshold")
 	proto.RegisterType((*FieldMetricThresholdResult)(nil), "mtrpb.FieldMetricThresholdResult")
+	proto.RegisterType((*FieldMetricInterval)(nil), "mtrpb.FieldMetricInterval")
+	proto.RegisterType((*FieldMetricIntervalResult)(nil), "mtrpb.FieldMetricIntervalResult")
 	proto.RegisterType((*FieldModel)(nil), "mtrpb.FieldModel")
 	proto.RegisterType((*FieldModelResult)(nil), "mtrpb.FieldModelResult")
 	proto.RegisterType((*FieldDevice)(nil), "mtrpb.FieldDevice")
+	proto.RegisterType((*FieldDeviceAttribute)(nil), "mtrpb.FieldDeviceAttribute")
 	proto.RegisterType((*FieldDeviceResult)(nil), "mtrpb.FieldDeviceResult")
+	proto.RegisterType((*FieldDeviceLocation)(nil), "mtrpb.FieldDeviceLocation")
+	proto.RegisterType((*FieldDeviceLocationResult)(nil), "mtrpb.FieldDeviceLocationResult")
 	proto.RegisterType((*FieldType)(nil), "mtrpb.FieldType")
 	proto.RegisterType((*FieldTypeResult)(nil), "mtrpb.FieldTypeResult")
 	proto.RegisterType((*FieldState)(nil), "mtrpb.FieldState")
@@ -327,43 +543,78 @@ func init() {
 	proto.RegisterType((*FieldStateTagResult)(nil), "mtrpb.FieldStateTagResult")
 	proto.RegisterType((*FieldMetric)(nil), "mtrpb.FieldMetric")
 	proto.RegisterType((*FieldMetricResult)(nil), "mtrpb.FieldMetricResult")
+	proto.RegisterType((*FieldMetricBatchRow)(nil), "mtrpb.FieldMetricBatchRow")
+	proto.RegisterType((*FieldMetricBatch)(nil), "mtrpb.FieldMetricBatch")
+	proto.RegisterType((*BatchRowResult)(nil), "mtrpb.BatchRowResult")
+	proto.RegisterType((*BatchResult)(nil), "mtrpb.BatchResult")
 }
 
-var fileDescriptor2 = []byte{
-	// 553 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x55, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xd5, 0xc6, 0x71, 0x6c, 0x4f, 0x04, 0xa4, 0x4b, 0x10, 0x6e, 0xcb, 0x01, 0xf9, 0x42, 0x40,
-	0x10, 0x09, 0x7a, 0x44, 0x05, 0x09, 0x02, 0x12, 0x87, 0x1e, 0x70, 0x2b, 0x81, 0xb8, 0x20, 0x37,
-	0x5e, 0x52, 0x4b, 0x9b, 0xda, 0xb2, 0xd7, 0x45, 0x39, 0xf1, 0x07, 0xfc, 0x1e, 0x9f, 0xc0, 0x6f,
-	0xb0, 0x9e, 0x5d, 0x3b, 0x6b, 0x27, 0x20, 0x14, 0x81, 0xd4, 0x5b, 0xe6, 0xcd, 0xac, 0xdf, 0x9b,
-	0x79, 0xb3, 0x1b, 0x18, 0x7e, 0x49, 0x18, 0x8f, 0xa7, 0x59, 0x9e, 0x8a, 0x94, 0xda, 0x4b, 0x91,
-	0x67, 0xe7, 0xc1, 0x0f, 0x02, 0xf4, 0x6d, 0x05, 0x9f, 0x30, 0x91, 0x27, 0xf3, 0xd3, 0x72, 0xb9,
-	0x8c, 0xf2, 0x15, 0x3d, 0x04, 0x2f, 0x66, 0x57, 0xc9, 0x9c, 0x7d, 0x4e, 0x66, 0x3e, 0xb9, 0x4f,
-	0x26, 0x5e, 0xe8, 0x2a, 0xe0, 0xdd, 0x8c, 0xde, 0x05, 0x47, 0xac, 0x32, 0x4c, 0xf5, 0x30, 0x35,
-	0xa8, 0x42, 0x99, 0xf0, 0xc1, 0x29, 0xd8, 0x3c, 0xbd, 0x8c, 0x0b, 0xdf, 0x92, 0x09, 0x2b, 0xac,
-	0x43, 0x3a, 0x06, 0xfb, 0x2a, 0xe2, 0x25, 0xf3, 0xfb, 0x12, 0xb7, 0x43, 0x15, 0x54, 0x68, 0x99,
-	0x65, 0x2c, 0xf7, 0x6d, 0x85, 0x62, 0x50, 0xa1, 0x3c, 0xfd, 0x2a, 0xd1, 0x81, 0x42, 0x31, 0xa0,
-	0xfb, 0xe0, 0x2e, 0xd3, 0x98, 0xf1, 0x8a, 0xd5, 0x41, 0x56, 0x07, 0x63, 0x49, 0x2b, 0x0f, 0x14,
-	0xf3, 0x88, 0x33, 0xdf, 0x95, 0x38, 0x09, 0x55, 0x10, 0x9c, 0x80, 0xbf, 0xd9, 0x58, 0xc8, 0x8a,
-	0x92, 0x0b, 0xfa, 0x14, 0x06, 0x39, 0xfe, 0x92, 0xbd, 0x59, 0x93, 0xe1, 0xb3, 0xfd, 0x29, 0x4e,
-	0x63, 0xba, 0xe5, 0x80, 0x2e, 0x0c, 0x3e, 0xc2, 0x4d, 0x23, 0x7b, 0x16, 0x2d, 0x76, 0x9c, 0xd1,
-	0x08, 0x2c, 0x11, 0x2d, 0x70, 0x3e, 0x5e, 0x58, 0xfd, 0x0c, 0xde, 0xc0, 0xb8, 0xfd, 0x65, 0x2d,
-	0xf2, 0x49, 0x47, 0xe4, 0x9d, 0x4d, 0x91, 0x55, 0x71, 0x2d, 0xf0, 0x3b, 0x69, 0x7f, 0xe7, 0x42,
-	0xe2, 0x17, 0x29, 0x8f, 0x77, 0xd4, 0xd9, 0xb8, 0x60, 0x99, 0x2e, 0x34, 0x8e, 0xf5, 0x3b, 0x8e,
-	0x29, 0x03, 0x6c, 0xd3, 0x80, 0xf7, 0x70, 0xb0, 0x4d, 0x8f, 0xee, 0xee, 0xa8, 0xd3, 0xdd, 0xe1,
-	0x96, 0xee, 0x9a, 0x23, 0x75, 0x8f, 0x0f, 0x00, 0x54, 0xbe, 0x72, 0xbe, 0xb5, 0x12, 0xa4, 0xb5,
-	0x12, 0xc1, 0x31, 0x8c, 0xd6, 0x85, 0x9a, 0xf1, 0x61, 0x87, 0x71, 0xaf, 0xc5, 0x88, 0x85, 0x35,
-	0xcf, 0x37, 0x18, 0x22, 0x3a, 0xc3, 0x31, 0xfd, 0x79, 0x82, 0xa6, 0x8a, 0x5e, 0x7b, 0x31, 0x0f,
-	0xc0, 0xe5, 0x91, 0x48, 0x44, 0x19, 0x33, 0x1c, 0x63, 0x2f, 0x6c, 0x62, 0x7a, 0x0f, 0x3c, 0x9e,
-	0x5e, 0x2e, 0x54, 0xb2, 0x8f, 0xc9, 0x35, 0x10, 0xbc, 0x84, 0x3d, 0x43, 0x80, 0x6e, 0xe0, 0x51,
-	0xa7, 0x01, 0x6a, 0x36, 0xa0, 0x2b, 0xeb, 0x0e, 0x5e, 0x80, 0x87, 0xf0, 0x99, 0x74, 0xd3, 0x34,
-	0x99, 0x74, 0x2f, 0x6c, 0x9c, 0x14, 0x19, 0x8f, 0x56, 0xb5, 0x74, 0x1d, 0x06, 0xcf, 0xe1, 0x56,
-	0x73, 0x5e, 0xd3, 0x4f, 0x3a, 0xf4, 0x23, 0x93, 0x1e, 0xeb, 0x6a, 0xf2, 0x5c, 0xdb, 0x74, 0x2a,
-	0x22, 0xc1, 0xfe, 0xef, 0x5b, 0xe2, 0xea, 0xb7, 0xa4, 0x71, 0x1c, 0x39, 0xff, 0xc6, 0x71, 0x55,
-	0x58, 0x4b, 0xfe, 0x00, 0x37, 0xd6, 0xe8, 0xbf, 0xbc, 0xdd, 0xaf, 0xe1, 0x76, 0xeb, 0xc3, 0x5a,
-	0xda, 0xe3, 0x8e, 0xb4, 0xf1, 0x86, 0x34, 0xf3, 0x6e, 0x1f, 0xeb, 0x7d, 0x54, 0xf7, 0xc2, 0x9c,
-	0x0d, 0xf9, 0xcd, 0x6c, 0x7a, 0xb8, 0x51, 0x7a, 0x36, 0x3f, 0x89, 0x5e, 0x27, 0x75, 0x5e, 0x4b,
-	0xb8, 0x7e, 0x6f, 0xfc, 0x7a, 0xc1, 0x9d, 0xcd, 0x05, 0xd7, 0xda, 0x75, 0xc5, 0xf6, 0x47, 0xff,
-	0x95, 0xf3, 0x49, 0xfd, 0xaf, 0x9d, 0x0f, 0xf0, 0x5f, 0xee, 0xe8, 0x57, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x5e, 0xb2, 0xd3, 0x0d, 0xf4, 0x06, 0x00, 0x00,
+var fileDescriptor4 = []byte{
+	// 1045 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x57, 0x5f, 0x8f, 0xdb, 0x44,
+	0x10, 0xd7, 0x26, 0x97, 0xc4, 0x1e, 0xdf, 0x9f, 0xc4, 0x77, 0x80, 0xef, 0x8a, 0x20, 0x32, 0x0f,
+	0xa4, 0x50, 0x4e, 0xa2, 0x7d, 0x40, 0x88, 0xff, 0x25, 0xa0, 0x9e, 0xd4, 0x0a, 0xc9, 0x2d, 0x14,
+	0xf1, 0xc0, 0xc9, 0x67, 0x6f, 0x13, 0xab, 0x8e, 0x6d, 0xd9, 0xeb, 0x4b, 0xf3, 0x05, 0x78, 0xe8,
+	0x23, 0xcf, 0x7c, 0x22, 0x1e, 0xf9, 0x40, 0x08, 0xed, 0xec, 0xda, 0x59, 0xaf, 0x93, 0xbb, 0xea,
+	0x04, 0xa8, 0x6f, 0x3b, 0xb3, 0xbf, 0xdd, 0xf9, 0xcd, 0xe4, 0x37, 0xeb, 0x09, 0x58, 0xcf, 0x22,
+	0x1a, 0x87, 0xa7, 0x59, 0x9e, 0xb2, 0xd4, 0xee, 0x2d, 0x58, 0x9e, 0x5d, 0xb8, 0xbf, 0xef, 0x80,
+	0xfd, 0x3d, 0x77, 0x3f, 0xa2, 0x2c, 0x8f, 0x82, 0xc7, 0xe5, 0x62, 0xe1, 0xe7, 0x2b, 0xfb, 0x16,
+	0x98, 0x21, 0xbd, 0x8c, 0x02, 0x7a, 0x1e, 0x4d, 0x1d, 0x32, 0x26, 0x13, 0xd3, 0x33, 0x84, 0xe3,
+	0x6c, 0x6a, 0xbf, 0x05, 0x03, 0xb6, 0xca, 0x70, 0xab, 0x83, 0x5b, 0x7d, 0x6e, 0x9e, 0x4d, 0x6d,
+	0x07, 0x06, 0x05, 0x0d, 0xd2, 0x24, 0x2c, 0x9c, 0xee, 0x98, 0x4c, 0xba, 0x5e, 0x65, 0xda, 0x47,
+	0xd0, 0xbb, 0xf4, 0xe3, 0x92, 0x3a, 0x3b, 0x63, 0x32, 0xe9, 0x79, 0xc2, 0xe0, 0xde, 0x32, 0xcb,
+	0x68, 0xee, 0xf4, 0x84, 0x17, 0x0d, 0xee, 0x8d, 0xd3, 0x25, 0xcd, 0x9d, 0xbe, 0xf0, 0xa2, 0x61,
+	0x1f, 0x83, 0xb1, 0x48, 0x43, 0x1a, 0xf3, 0xa8, 0x03, 0x8c, 0x3a, 0x40, 0xfb, 0x6c, 0xca, 0x0f,
+	0x14, 0x81, 0x1f, 0x53, 0xc7, 0x18, 0x93, 0x09, 0xf1, 0x84, 0x61, 0xdb, 0xb0, 0x13, 0xfb, 0x8c,
+	0x3a, 0xe6, 0x98, 0x4c, 0x0c, 0x0f, 0xd7, 0x9c, 0x60, 0x99, 0x3c, 0x4f, 0xd2, 0x65, 0xe2, 0x00,
+	0xba, 0x2b, 0xd3, 0x7e, 0x0f, 0xf6, 0x96, 0x7e, 0x9e, 0x44, 0xc9, 0xec, 0x5c, 0x50, 0xb2, 0x30,
+	0xf8, 0xae, 0x74, 0xfe, 0x88, 0xcc, 0x14, 0x90, 0x60, 0xb8, 0xdb, 0x00, 0x3d, 0x44, 0xa2, 0xef,
+	0x82, 0xf5, 0x2c, 0x4e, 0x7d, 0x76, 0x2e, 0x12, 0xde, 0x43, 0x4e, 0x80, 0xae, 0x9f, 0x30, 0xeb,
+	0x1a, 0x20, 0x02, 0xed, 0x2b, 0x00, 0x11, 0xa6, 0x06, 0x88, 0x20, 0x07, 0x0a, 0x40, 0x84, 0x38,
+	0x85, 0x43, 0x01, 0x68, 0x52, 0x1e, 0x22, 0x70, 0x84, 0x5b, 0x4f, 0x55, 0xde, 0x2d, 0xbc, 0xb8,
+	0x78, 0xd4, 0xc6, 0xe3, 0xfd, 0xee, 0x23, 0x70, 0xda, 0x9a, 0xf0, 0x68, 0x51, 0xc6, 0xcc, 0xfe,
+	0x18, 0xfa, 0x39, 0xae, 0x1c, 0x32, 0xee, 0x4e, 0xac, 0xbb, 0xc7, 0xa7, 0x28, 0xa4, 0xd3, 0x0d,
+	0x07, 0x24, 0xd0, 0xfd, 0x19, 0xf6, 0x95, 0xdd, 0x27, 0xfe, 0xec, 0x86, 0xf2, 0x1a, 0x42, 0x97,
+	0xf9, 0x33, 0x94, 0x96, 0xe9, 0xf1, 0xa5, 0xfb, 0x1d, 0x1c, 0x35, 0x6f, 0x96, 0x24, 0x3f, 0xd2,
+	0x48, 0xbe, 0xd1, 0x26, 0xc9, 0xc1, 0x15, 0xc1, 0xbf, 0xbb, 0xcd, 0x7b, 0xe6, 0x39, 0x2d, 0xe6,
+	0x69, 0x1c, 0xde, 0x90, 0x67, 0x2d, 0xe0, 0xae, 0x2a, 0xe0, 0x5a, 0xec, 0x3b, 0x9a, 0xd8, 0x85,
+	0x76, 0x7b, 0xaa, 0x76, 0x55, 0xb1, 0xf7, 0x9b, 0x62, 0x7f, 0x1b, 0xcc, 0x28, 0x99, 0xd3, 0x3c,
+	0x62, 0x34, 0xc4, 0x46, 0x30, 0xbc, 0xb5, 0xa3, 0xad, 0x50, 0x63, 0x83, 0x42, 0x5b, 0x5a, 0x37,
+	0x37, 0x68, 0xfd, 0x1d, 0x80, 0xf9, 0xaa, 0x60, 0x34, 0xa7, 0x45, 0x54, 0x60, 0xb7, 0xf4, 0x3c,
+	0xc5, 0xa3, 0x8b, 0xd4, 0x6a, 0x89, 0x54, 0x93, 0xf9, 0x6e, 0x4b, 0xe6, 0x5b, 0x54, 0xb9, 0xb7,
+	0x45, 0x95, 0xdb, 0x54, 0xbf, 0xbf, 0x4d, 0xf5, 0xb7, 0x61, 0x28, 0xf0, 0x4a, 0x1e, 0xa2, 0x97,
+	0x0e, 0xd0, 0xff, 0xa0, 0x76, 0xbb, 0x2f, 0x09, 0x9c, 0x6c, 0x12, 0x80, 0x94, 0xd3, 0x3d, 0x4d,
+	0x4e, 0xb7, 0x36, 0xc8, 0xa9, 0x3e, 0x22, 0xa1, 0xf6, 0x27, 0x60, 0x30, 0xba, 0xc8, 0xf0, 0x0d,
+	0xea, 0x5c, 0x7f, 0xac, 0x06, 0xbb, 0x2f, 0xe0, 0x50, 0x41, 0x9c, 0x25, 0x8c, 0xe6, 0x97, 0x7e,
+	0x7c, 0x43, 0x2d, 0x7e, 0x08, 0x23, 0xfa, 0x22, 0xa3, 0x01, 0xa3, 0xe1, 0x79, 0x24, 0xaf, 0x92,
+	0xba, 0x1c, 0x56, 0x1b, 0x55, 0x08, 0xf7, 0x07, 0x38, 0xde, 0x10, 0x59, 0x16, 0xe1, 0xae, 0x56,
+	0x84, 0x93, 0x76, 0x36, 0xf5, 0x89, 0xaa, 0xb1, 0xde, 0x07, 0x10, 0xdb, 0x5c, 0xbc, 0x0d, 0x55,
+	0x93, 0x86, 0xaa, 0xdd, 0x2f, 0x60, 0xb8, 0x06, 0xca, 0x80, 0xb7, 0xb5, 0x80, 0xa3, 0x46, 0x40,
+	0x04, 0x56, 0x71, 0xfe, 0x22, 0x60, 0xa1, 0x7b, 0x8a, 0x05, 0xb9, 0xba, 0x56, 0x2a, 0x8d, 0x4e,
+	0xb3, 0xb9, 0x4e, 0xc0, 0x88, 0x7d, 0x16, 0xb1, 0x32, 0xa4, 0x58, 0xa4, 0x8e, 0x57, 0xdb, 0xbc,
+	0xf1, 0xe2, 0x34, 0x99, 0x89, 0xcd, 0x1d, 0xdc, 0x5c, 0x3b, 0xec, 0x37, 0xa1, 0x5f, 0x30, 0x9f,
+	0x95, 0x05, 0x36, 0xb2, 0xe9, 0x49, 0xcb, 0xfe, 0x14, 0x4c, 0x9f, 0xb1, 0x3c, 0xba, 0x28, 0x19,
+	0x75, 0xfa, 0x6d, 0x19, 0x08, 0xc2, 0xdf, 0x54, 0x10, 0x6f, 0x8d, 0x76, 0xbf, 0x84, 0xa3, 0x4d,
+	0x10, 0xfe, 0x0c, 0x3e, 0xa7, 0x2b, 0x99, 0x16, 0x5f, 0xae, 0xbf, 0xae, 0x22, 0x1d, 0x61, 0xb8,
+	0x5f, 0xc1, 0x48, 0x39, 0x2f, 0x8b, 0xfa, 0x81, 0x56, 0x54, 0xbb, 0x4d, 0xa6, 0xae, 0xea, 0x6f,
+	0x04, 0x0e, 0x15, 0xff, 0xc3, 0x34, 0xf0, 0x59, 0x94, 0x26, 0x57, 0x57, 0x57, 0x99, 0x01, 0x3a,
+	0xcd, 0x19, 0x40, 0x2f, 0x2e, 0xb9, 0xaa, 0xb8, 0x44, 0x29, 0x6e, 0xad, 0xcb, 0x26, 0x8f, 0x57,
+	0xd1, 0xa5, 0x76, 0xa2, 0xca, 0xec, 0x25, 0x01, 0x13, 0xf7, 0x9f, 0xac, 0x32, 0xaa, 0x36, 0x0f,
+	0xd1, 0xe7, 0x99, 0x30, 0x2a, 0xb2, 0xd8, 0x5f, 0x55, 0x42, 0x91, 0xa6, 0x3d, 0x06, 0x2b, 0xa4,
+	0x45, 0x90, 0x47, 0x19, 0xbf, 0x57, 0x7e, 0x92, 0x54, 0x17, 0x1f, 0x3f, 0xca, 0x24, 0x62, 0x98,
+	0x8c, 0xe9, 0xe1, 0x7a, 0xf3, 0x63, 0xef, 0x7e, 0x06, 0x07, 0x35, 0x17, 0x99, 0xd3, 0x44, 0xcb,
+	0x69, 0xa8, 0xe6, 0x84, 0xb8, 0x2a, 0x93, 0x5c, 0x76, 0xd8, 0x63, 0xc6, 0xe7, 0x9b, 0xff, 0x74,
+	0x6c, 0x33, 0x2a, 0x61, 0x55, 0xcd, 0x8a, 0x31, 0x5f, 0xa5, 0x59, 0x05, 0xb0, 0xa2, 0xfc, 0x14,
+	0xf6, 0xd6, 0xde, 0x7f, 0x73, 0x1a, 0xf8, 0x16, 0x0e, 0x1b, 0x17, 0x4b, 0x6a, 0x77, 0x34, 0x6a,
+	0x47, 0x2d, 0x6a, 0xea, 0x2c, 0xf0, 0x2b, 0x58, 0xca, 0x8b, 0xa6, 0xd6, 0x86, 0x6c, 0xa9, 0x4d,
+	0x07, 0xdf, 0x02, 0x61, 0xe8, 0xd3, 0x5f, 0x57, 0x9f, 0xfe, 0xdc, 0x3f, 0x3b, 0x30, 0x52, 0x02,
+	0x48, 0x8e, 0xaf, 0xdf, 0xbc, 0xbd, 0x7e, 0x28, 0x06, 0xed, 0x87, 0x42, 0x72, 0x97, 0x88, 0x2d,
+	0x03, 0xb8, 0x56, 0x0a, 0xf3, 0xba, 0x41, 0x18, 0xae, 0x1b, 0x84, 0x5b, 0x33, 0x86, 0xfb, 0x07,
+	0x69, 0x7c, 0x2b, 0xef, 0xfb, 0x2c, 0x98, 0x7b, 0xe9, 0xf2, 0x7f, 0x2a, 0xa7, 0x96, 0x60, 0xaf,
+	0xf5, 0x5b, 0x7f, 0x0d, 0x43, 0x9d, 0x9d, 0x7d, 0x07, 0xba, 0x79, 0xba, 0xdc, 0xfe, 0x0d, 0xad,
+	0x72, 0xf0, 0x38, 0xcc, 0x7d, 0x00, 0xfb, 0xb5, 0x43, 0xd4, 0x7a, 0x58, 0x9d, 0xe7, 0x44, 0xf8,
+	0x92, 0xbf, 0x34, 0x41, 0x1a, 0x0a, 0x1d, 0xf6, 0x3c, 0x5c, 0x73, 0xd4, 0xa2, 0xa8, 0x9b, 0x63,
+	0x51, 0xcc, 0xdc, 0xcf, 0xc1, 0x12, 0x37, 0x5d, 0x3d, 0x21, 0x37, 0xa3, 0x55, 0xbf, 0xf0, 0xfd,
+	0xc1, 0x2f, 0xe2, 0xff, 0xe2, 0x45, 0x1f, 0xff, 0x3d, 0xde, 0xfb, 0x67, 0x00, 0x20, 0x41, 0x06,
+	0x22, 0x4c, 0x0e, 0x00, 0x00,
 }
@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-go.
+// source: ingest.proto
+// DO NOT EDIT!
+
+package mtrpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// IngestMap maps a metric name from Influx line protocol or Prometheus remote write
+// onto a metric type in field.metric or data.latency.
+type IngestMap struct {
+	// The metric name e.g., voltage or gateway_voltage.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// The metric table, field.metric or data.latency.
+	Table string `protobuf:"bytes,2,opt,name=table" json:"table,omitempty"`
+	// The typeID e.g., voltage
+	TypeID string `protobuf:"bytes,3,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// The tag or label with the deviceID or siteID e.g., host
+	Label string `protobuf:"bytes,4,opt,name=label" json:"label,omitempty"`
+	// Values are multiplied by scale and rounded e.g., 1000 for V to mV.
+	Scale float64 `protobuf:"fixed64,5,opt,name=scale" json:"scale,omitempty"`
+}
+
+func (m *IngestMap) Reset()                    { *m = IngestMap{} }
+func (m *IngestMap) String() string            { return proto.CompactTextString(m) }
+func (*IngestMap) ProtoMessage()               {}
+func (*IngestMap) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
+
+type IngestMapResult struct {
+	Result []*IngestMap `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *IngestMapResult) Reset()                    { *m = IngestMapResult{} }
+func (m *IngestMapResult) String() string            { return proto.CompactTextString(m) }
+func (*IngestMapResult) ProtoMessage()               {}
+func (*IngestMapResult) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{1} }
+
+func (m *IngestMapResult) GetResult() []*IngestMap {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// PromWriteRequest is the body of a Prometheus remote write request (snappy compressed).
+// It is wire compatible with prometheus.WriteRequest.
+type PromWriteRequest struct {
+	Timeseries []*PromTimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+}
+
+func (m *PromWriteRequest) Reset()                    { *m = PromWriteRequest{} }
+func (m *PromWriteRequest) String() string            { return proto.CompactTextString(m) }
+func (*PromWriteRequest) ProtoMessage()               {}
+func (*PromWriteRequest) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{2} }
+
+func (m *PromWriteRequest) GetTimeseries() []*PromTimeSeries {
+	if m != nil {
+		return m.Timeseries
+	}
+	return nil
+}
+
+type PromTimeSeries struct {
+	Labels  []*PromLabel  `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
+	Samples []*PromSample `protobuf:"bytes,2,rep,name=samples" json:"samples,omitempty"`
+}
+
+func (m *PromTimeSeries) Reset()                    { *m = PromTimeSeries{} }
+func (m *PromTimeSeries) String() string            { return proto.CompactTextString(m) }
+func (*PromTimeSeries) ProtoMessage()               {}
+func (*PromTimeSeries) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{3} }
+
+func (m *PromTimeSeries) GetLabels() []*PromLabel {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *PromTimeSeries) GetSamples() []*PromSample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+type PromLabel struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *PromLabel) Reset()                    { *m = PromLabel{} }
+func (m *PromLabel) String() string            { return proto.CompactTextString(m) }
+func (*PromLabel) ProtoMessage()               {}
+func (*PromLabel) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
+type PromSample struct {
+	Value float64 `protobuf:"fixed64,1,opt,name=value" json:"value,omitempty"`
+	// Unix time in milliseconds.
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *PromSample) Reset()                    { *m = PromSample{} }
+func (m *PromSample) String() string            { return proto.CompactTextString(m) }
+func (*PromSample) ProtoMessage()               {}
+func (*PromSample) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+
+func init() {
+	proto.RegisterType((*IngestMap)(nil), "mtrpb.IngestMap")
+	proto.RegisterType((*IngestMapResult)(nil), "mtrpb.IngestMapResult")
+	proto.RegisterType((*PromWriteRequest)(nil), "mtrpb.PromWriteRequest")
+	proto.RegisterType((*PromTimeSeries)(nil), "mtrpb.PromTimeSeries")
+	proto.RegisterType((*PromLabel)(nil), "mtrpb.PromLabel")
+	proto.RegisterType((*PromSample)(nil), "mtrpb.PromSample")
+}
+
+var fileDescriptor5 = []byte{
+	// 298 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x91, 0x4f, 0x4b, 0x03, 0x31,
+	0x10, 0xc5, 0x49, 0xff, 0xb2, 0xa3, 0x68, 0x0d, 0x8a, 0x39, 0x78, 0x28, 0x39, 0x2d, 0x08, 0x3d,
+	0x28, 0x3d, 0x79, 0x11, 0xe9, 0xa5, 0xa0, 0x20, 0xa9, 0x20, 0x78, 0x91, 0xac, 0x0c, 0x25, 0x90,
+	0xb4, 0x31, 0x49, 0x05, 0xfd, 0xf4, 0x92, 0xd9, 0x75, 0xb7, 0x0a, 0xde, 0xf2, 0xde, 0xfb, 0xed,
+	0xec, 0xcb, 0x04, 0x0e, 0xcd, 0x66, 0x8d, 0x31, 0xcd, 0x7c, 0xd8, 0xa6, 0x2d, 0x1f, 0xba, 0x14,
+	0x7c, 0x25, 0xbf, 0xa0, 0x58, 0x92, 0xfd, 0xa0, 0x3d, 0xe7, 0x30, 0xd8, 0x68, 0x87, 0x82, 0x4d,
+	0x59, 0x59, 0x28, 0x3a, 0xf3, 0x53, 0x18, 0x26, 0x5d, 0x59, 0x14, 0x3d, 0x32, 0x6b, 0xc1, 0xcf,
+	0x61, 0x9c, 0x3e, 0x3d, 0xbe, 0x9a, 0x85, 0xe8, 0x93, 0x3f, 0xca, 0x72, 0xb9, 0xc8, 0xb8, 0xd5,
+	0x15, 0x5a, 0x31, 0xa8, 0x71, 0x12, 0xd9, 0x8d, 0x6f, 0xda, 0xa2, 0x18, 0x4e, 0x59, 0xc9, 0x54,
+	0x2d, 0xe4, 0x0d, 0x1c, 0xb7, 0xff, 0x56, 0x18, 0x77, 0x36, 0xf1, 0x12, 0x46, 0x81, 0x4e, 0x82,
+	0x4d, 0xfb, 0xe5, 0xc1, 0xd5, 0x64, 0x46, 0x35, 0x67, 0x1d, 0xd7, 0xe4, 0x72, 0x09, 0x93, 0xc7,
+	0xb0, 0x75, 0xcf, 0xc1, 0x24, 0x54, 0xf8, 0xbe, 0xc3, 0x98, 0xf8, 0x1c, 0x20, 0x19, 0x87, 0x11,
+	0x83, 0xc1, 0xd8, 0x4c, 0x38, 0x6b, 0x26, 0x64, 0xf8, 0xc9, 0x38, 0x5c, 0x51, 0xa8, 0xf6, 0x40,
+	0xb9, 0x86, 0xa3, 0xdf, 0x69, 0xae, 0x41, 0xc5, 0xe3, 0x9f, 0x1a, 0x19, 0xbb, 0xcf, 0x81, 0x6a,
+	0x72, 0x7e, 0x09, 0xe3, 0xa8, 0x9d, 0xb7, 0x18, 0x45, 0x8f, 0xd0, 0x93, 0x3d, 0x74, 0x45, 0x89,
+	0xfa, 0x21, 0xe4, 0x1c, 0x8a, 0x76, 0xc2, 0x7f, 0xcb, 0xfe, 0xd0, 0x76, 0xd7, 0x2e, 0x9b, 0x84,
+	0xbc, 0x05, 0xe8, 0xa6, 0x75, 0x0c, 0xab, 0x77, 0x49, 0x82, 0x5f, 0x40, 0x41, 0x37, 0x4a, 0xda,
+	0x79, 0xfa, 0xba, 0xaf, 0x3a, 0xe3, 0x6e, 0xfc, 0x52, 0x3f, 0x77, 0x35, 0xa2, 0xc7, 0xbf, 0xfe,
+	0x1e, 0x00, 0x63, 0xeb, 0x98, 0x25, 0x0c, 0x02, 0x00, 0x00,
+}
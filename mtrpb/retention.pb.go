@@ -0,0 +1,80 @@
+// Code generated by protoc-gen-go.
+// source: retention.proto
+// DO NOT EDIT!
+
+package mtrpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// RetentionPolicy is how long rows are kept in a metric table and the statistics
+// for the last time old rows were deleted.
+// If type_iD is empty the policy is the default for all types in the table.
+type RetentionPolicy struct {
+	// The table e.g., field.metric
+	Table string `protobuf:"bytes,1,opt,name=table" json:"table,omitempty"`
+	// The typeID e.g., voltage
+	TypeID string `protobuf:"bytes,2,opt,name=type_iD,json=typeID" json:"type_iD,omitempty"`
+	// Rows older than days are deleted.
+	Days int32 `protobuf:"varint,3,opt,name=days" json:"days,omitempty"`
+	// If true rows are only deleted after they have been rolled up.
+	RequireRollup bool `protobuf:"varint,4,opt,name=require_rollup,json=requireRollup" json:"require_rollup,omitempty"`
+	// Unix time in seconds for the last run.  0 if the policy has not been run.
+	LastRun int64 `protobuf:"varint,5,opt,name=last_run,json=lastRun" json:"last_run,omitempty"`
+	// The number of rows deleted in the last run.
+	LastDeleted int64 `protobuf:"varint,6,opt,name=last_deleted,json=lastDeleted" json:"last_deleted,omitempty"`
+	// The time taken for the last run in milliseconds.
+	LastTaken int64 `protobuf:"varint,7,opt,name=last_taken,json=lastTaken" json:"last_taken,omitempty"`
+}
+
+func (m *RetentionPolicy) Reset()                    { *m = RetentionPolicy{} }
+func (m *RetentionPolicy) String() string            { return proto.CompactTextString(m) }
+func (*RetentionPolicy) ProtoMessage()               {}
+func (*RetentionPolicy) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{0} }
+
+type RetentionPolicyResult struct {
+	Result []*RetentionPolicy `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
+}
+
+func (m *RetentionPolicyResult) Reset()                    { *m = RetentionPolicyResult{} }
+func (m *RetentionPolicyResult) String() string            { return proto.CompactTextString(m) }
+func (*RetentionPolicyResult) ProtoMessage()               {}
+func (*RetentionPolicyResult) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{1} }
+
+func (m *RetentionPolicyResult) GetResult() []*RetentionPolicy {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RetentionPolicy)(nil), "mtrpb.RetentionPolicy")
+	proto.RegisterType((*RetentionPolicyResult)(nil), "mtrpb.RetentionPolicyResult")
+}
+
+var fileDescriptor6 = []byte{
+	// 244 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x90, 0xbf, 0x4e, 0x84, 0x40,
+	0x10, 0xc6, 0xb3, 0x72, 0xc0, 0xdd, 0x9c, 0x7a, 0xc9, 0xc4, 0x3f, 0x6b, 0x61, 0x82, 0x97, 0x98,
+	0x50, 0x51, 0xe8, 0x1b, 0x18, 0x12, 0x63, 0x67, 0x36, 0x56, 0x36, 0x04, 0x64, 0x0a, 0xe2, 0x0a,
+	0xb8, 0xcc, 0x16, 0xbc, 0xa9, 0x8f, 0x63, 0x76, 0xb8, 0x6b, 0xae, 0x9b, 0xef, 0xf7, 0xfd, 0x9a,
+	0xf9, 0x60, 0xe7, 0x88, 0xa9, 0xe7, 0x6e, 0xe8, 0x8b, 0xd1, 0x0d, 0x3c, 0x60, 0xfc, 0xc3, 0x6e,
+	0x6c, 0xf6, 0x7f, 0x0a, 0x76, 0xe6, 0x58, 0xbd, 0x0f, 0xb6, 0xfb, 0x9a, 0xf1, 0x0a, 0x62, 0xae,
+	0x1b, 0x4b, 0x5a, 0x65, 0x2a, 0xdf, 0x98, 0x25, 0xe0, 0x2d, 0xa4, 0x3c, 0x8f, 0x54, 0x75, 0xa5,
+	0x3e, 0x13, 0x9e, 0x84, 0xf8, 0x56, 0x22, 0xc2, 0xaa, 0xad, 0xe7, 0x49, 0x47, 0x99, 0xca, 0x63,
+	0x23, 0x37, 0x3e, 0xc2, 0xa5, 0xa3, 0x5f, 0xdf, 0x39, 0xaa, 0xdc, 0x60, 0xad, 0x1f, 0xf5, 0x2a,
+	0x53, 0xf9, 0xda, 0x5c, 0x1c, 0xa8, 0x11, 0x88, 0x77, 0xb0, 0xb6, 0xf5, 0xc4, 0x95, 0xf3, 0xbd,
+	0x8e, 0x33, 0x95, 0x47, 0x26, 0x0d, 0xd9, 0xf8, 0x1e, 0x1f, 0xe0, 0x5c, 0xaa, 0x96, 0x2c, 0x31,
+	0xb5, 0x3a, 0x91, 0x7a, 0x1b, 0x58, 0xb9, 0x20, 0xbc, 0x07, 0x10, 0x85, 0xeb, 0x6f, 0xea, 0x75,
+	0x2a, 0xc2, 0x26, 0x90, 0x8f, 0x00, 0xf6, 0xaf, 0x70, 0x7d, 0xf2, 0x99, 0xa1, 0xc9, 0x5b, 0xc6,
+	0x02, 0x12, 0x27, 0x97, 0x56, 0x59, 0x94, 0x6f, 0x9f, 0x6e, 0x0a, 0xd9, 0xa2, 0x38, 0xb5, 0x0f,
+	0xd6, 0x4b, 0xfa, 0xb9, 0x8c, 0xd5, 0x24, 0x32, 0xdd, 0xf3, 0xff, 0x00, 0x9c, 0x7e, 0xb1, 0xe2,
+	0x4d, 0x01, 0x00, 0x00,
+}
@@ -20,7 +20,7 @@ type Tag struct {
 func (m *Tag) Reset()                    { *m = Tag{} }
 func (m *Tag) String() string            { return proto.CompactTextString(m) }
 func (*Tag) ProtoMessage()               {}
-func (*Tag) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+func (*Tag) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{0} }
 
 type TagResult struct {
 	Result []*Tag `protobuf:"bytes,1,rep,name=result" json:"result,omitempty"`
@@ -29,7 +29,7 @@ type TagResult struct {
 func (m *TagResult) Reset()                    { *m = TagResult{} }
 func (m *TagResult) String() string            { return proto.CompactTextString(m) }
 func (*TagResult) ProtoMessage()               {}
-func (*TagResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+func (*TagResult) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1} }
 
 func (m *TagResult) GetResult() []*Tag {
 	if m != nil {
@@ -48,7 +48,7 @@ type TagSearchResult struct {
 func (m *TagSearchResult) Reset()                    { *m = TagSearchResult{} }
 func (m *TagSearchResult) String() string            { return proto.CompactTextString(m) }
 func (*TagSearchResult) ProtoMessage()               {}
-func (*TagSearchResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+func (*TagSearchResult) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{2} }
 
 func (m *TagSearchResult) GetFieldMetric() []*FieldMetricSummary {
 	if m != nil {
@@ -84,7 +84,7 @@ func init() {
 	proto.RegisterType((*TagSearchResult)(nil), "mtrpb.TagSearchResult")
 }
 
-var fileDescriptor3 = []byte{
+var fileDescriptor7 = []byte{
 	// 257 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x90, 0xb1, 0x4e, 0xc3, 0x30,
 	0x10, 0x86, 0xd5, 0x06, 0x8a, 0x72, 0x45, 0xa2, 0xf5, 0x42, 0xe8, 0x80, 0x50, 0xa6, 0x4e, 0x41,
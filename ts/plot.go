@@ -72,16 +72,35 @@ type line struct {
 }
 
 type threshold struct {
-	Min, Max       float64
-	H              int // height in px for the threshold rect
-	Y              int // Y on plot for the threshold rect.
-	UpperLimitPx   int // height in px for the threshold upper limit
-	LowerLimitPx   int // height in px for the threshold lower limit
-	ShowRect       bool
-	ShowLowerLimit bool
-	ShowUpperLimit bool
+	Min, Max               float64
+	WarningMin, WarningMax float64 // both 0 for no warning threshold
+	H                      int     // height in px for the threshold rect
+	Y                      int     // Y on plot for the threshold rect.
+	UpperLimitPx           int     // height in px for the threshold upper limit
+	LowerLimitPx           int     // height in px for the threshold lower limit
+	WarningUpperLimitPx    int     // height in px for the warning upper limit
+	WarningLowerLimitPx    int     // height in px for the warning lower limit
+	ShowRect               bool
+	ShowLowerLimit         bool
+	ShowUpperLimit         bool
+	ShowWarningLowerLimit  bool
+	ShowWarningUpperLimit  bool
+	Zones                  []zone // amber (warning) and red (critical) zones, clipped to the plot.
 }
 
+/*
+zone is a band across the plot in svg space.
+*/
+type zone struct {
+	Y, H   int
+	Colour string
+}
+
+const (
+	warningColour  = "#ff7f00" // amber
+	criticalColour = "#e41a1c" // red
+)
+
 type pts []pt
 
 type Series struct {
@@ -144,9 +163,17 @@ func (p *Plot) SetYRange(r float64) {
 	p.plt.YRange = r
 }
 
-func (p *Plot) SetThreshold(min, max float64) {
+/*
+SetThreshold sets the critical thresholds min and max and the warning thresholds warningMin and
+warningMax, which should be inside min and max.  Values above max or below min are in a red zone and
+values between a warning and a critical threshold are in an amber zone.  Use warningMin and warningMax
+0 for no warning threshold.
+*/
+func (p *Plot) SetThreshold(min, max, warningMin, warningMax float64) {
 	p.plt.Threshold.Min = min
 	p.plt.Threshold.Max = max
+	p.plt.Threshold.WarningMin = warningMin
+	p.plt.Threshold.WarningMax = warningMax
 	p.plt.Threshold.ShowRect = true
 }
 
@@ -275,6 +302,8 @@ func (p *Plot) scaleData() {
 	}
 
 	if p.plt.Threshold.ShowRect {
+		p.setZones()
+
 		p.plt.Threshold.H = int(((p.plt.Threshold.Max - p.plt.Threshold.Min) * p.plt.dy) + 0.5)
 		p.plt.Threshold.Y = p.plt.height - int(((p.plt.Threshold.Max-p.plt.YMin)*p.plt.dy)+0.5)
 		p.plt.Threshold.UpperLimitPx = p.plt.Threshold.Y
@@ -308,6 +337,49 @@ func (p *Plot) scaleData() {
 	return
 }
 
+/*
+setZones sets the red zones above and below the critical thresholds and, if there is a warning
+threshold, the amber zones between the critical and warning thresholds.  Zones are clipped
+to the plot and zones that are outside it are not drawn.  scaleData() must have set dy.
+*/
+func (p *Plot) setZones() {
+	t := &p.plt.Threshold
+	t.Zones = nil
+
+	y := func(v float64) int {
+		return p.plt.height - int(((v-p.plt.YMin)*p.plt.dy)+0.5)
+	}
+
+	add := func(top, bottom int, colour string) {
+		if top < 0 {
+			top = 0
+		}
+		if bottom > p.plt.height {
+			bottom = p.plt.height
+		}
+		if bottom > top {
+			t.Zones = append(t.Zones, zone{Y: top, H: bottom - top, Colour: colour})
+		}
+	}
+
+	add(0, y(t.Max), criticalColour)
+	add(y(t.Min), p.plt.height, criticalColour)
+
+	if t.WarningMin == 0 && t.WarningMax == 0 {
+		t.ShowWarningLowerLimit = false
+		t.ShowWarningUpperLimit = false
+		return
+	}
+
+	add(y(t.Max), y(t.WarningMax), warningColour)
+	add(y(t.WarningMin), y(t.Min), warningColour)
+
+	t.WarningUpperLimitPx = y(t.WarningMax)
+	t.WarningLowerLimitPx = y(t.WarningMin)
+	t.ShowWarningUpperLimit = t.WarningUpperLimitPx >= 0 && t.WarningUpperLimitPx <= p.plt.height
+	t.ShowWarningLowerLimit = t.WarningLowerLimitPx >= 0 && t.WarningLowerLimitPx <= p.plt.height
+}
+
 /*
 setAxes builds x and y grids.  Major ticks are labelled, minor ticks are not.
 scaleData() should be called before setAxes()
@@ -55,6 +55,20 @@ var funcMap = template.FuncMap{
 			return false
 		}
 	},
+	// step adds a point before each point at the previous value so that a
+	// polyline holds each value until the next one e.g., for on off states.
+	"step": func(p pts) pts {
+		var s pts
+
+		for i := range p {
+			if i > 0 {
+				s = append(s, pt{X: p[i].X, Y: p[i-1].Y})
+			}
+			s = append(s, p[i])
+		}
+
+		return s
+	},
 }
 
 type SVGPlot struct {
@@ -83,6 +97,12 @@ var Scatter = SVGPlot{
 	height:   210,
 }
 
+var Step = SVGPlot{
+	template: template.Must(template.New("plot").Funcs(funcMap).Parse(plotBaseTemplate + plotStepTemplate)),
+	width:    780,
+	height:   210,
+}
+
 var MixedAppMetrics = SVGPlot{
 	template: template.Must(template.New("plot").Funcs(funcMap).Parse(plotAppMetricsTemplate + plotAppMixedTemplate)),
 	width:    640,
@@ -122,6 +142,9 @@ const plotBaseTemplate = `<?xml version="1.0"?>
 
 <g transform="translate(10,60)">
 
+{{range .Threshold.Zones}}
+<rect x="0" y="{{.Y}}" width="780" height="{{.H}}" fill="{{.Colour}}" fill-opacity="0.15"/>
+{{end}}
 {{if .Threshold.ShowRect}}
 <rect x="0" y="{{.Threshold.Y}}" width="780" height="{{.Threshold.H}}" fill="lightgrey" fill-opacity="0.3"/>
 {{if .Threshold.ShowUpperLimit}}
@@ -131,6 +154,12 @@ const plotBaseTemplate = `<?xml version="1.0"?>
 <line style="stroke:rgb(255,0,0); fill: none; stroke-width: 1px; stroke-linecap: round; stroke-linejoin: round"  x1="0" y1="{{.Threshold.LowerLimitPx}}" x2="780" y2="{{.Threshold.LowerLimitPx}}"/>
 {{end}}
 {{end}}
+{{if .Threshold.ShowWarningUpperLimit}}
+<line style="stroke:#ff7f00; fill: none; stroke-width: 1px; stroke-linecap: round; stroke-linejoin: round"  x1="0" y1="{{.Threshold.WarningUpperLimitPx}}" x2="780" y2="{{.Threshold.WarningUpperLimitPx}}"/>
+{{end}}
+{{if .Threshold.ShowWarningLowerLimit}}
+<line style="stroke:#ff7f00; fill: none; stroke-width: 1px; stroke-linecap: round; stroke-linejoin: round"  x1="0" y1="{{.Threshold.WarningLowerLimitPx}}" x2="780" y2="{{.Threshold.WarningLowerLimitPx}}"/>
+{{end}}
 
 <text x="{{400}}" y="220" text-anchor="middle" dominant-baseline="hanging">{{.Axes.Xlabel}}</text>
 
@@ -194,6 +223,13 @@ const plotLineTemplate = `
 {{end}}
 {{end}}`
 
+const plotStepTemplate = `
+{{define "data"}}
+{{range .Data}}
+<polyline style="stroke: {{.Series.Colour}}; fill: none; stroke-width: 2px; stroke-linejoin: miter" points="{{range step .Pts}}{{.X}},{{.Y}} {{end}}" />
+{{end}}
+{{end}}`
+
 const scatterDotTemplate = `
 {{define "data"}}
 {{range .Data}}